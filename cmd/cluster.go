@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/isurusiri/tipsy/internal/config"
+	"github.com/isurusiri/tipsy/internal/k8s"
+	"github.com/isurusiri/tipsy/internal/state"
+	"github.com/isurusiri/tipsy/internal/utils"
+	"k8s.io/client-go/kubernetes"
+)
+
+// newClient creates the Kubernetes client for a command and records which cluster it is
+// connected to, so actions saved to state can be told apart from those of other clusters
+func newClient() (*kubernetes.Clientset, error) {
+	client, err := k8s.NewClient(config.GlobalConfig.Kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+
+	state.SetCluster(clusterRef(client))
+	return client, nil
+}
+
+// clusterRef identifies the cluster the client is connected to
+// An identity missing the kube-system UID is still usable, matched by server URL instead
+func clusterRef(client kubernetes.Interface) *state.ClusterRef {
+	identity, err := k8s.GetClusterIdentity(config.GlobalConfig.Kubeconfig, client)
+	if err != nil {
+		utils.Warn(fmt.Sprintf("Could not fully identify the cluster: %v", err))
+	}
+	if identity.Server == "" && identity.UID == "" {
+		return nil
+	}
+
+	return &state.ClusterRef{Server: identity.Server, Context: identity.Context, UID: identity.UID}
+}
+
+// clusterScope returns the cluster rollback and status should confine themselves to,
+// or nil for every cluster
+func clusterScope(allClusters bool) *state.ClusterRef {
+	if allClusters {
+		return nil
+	}
+	return state.CurrentCluster()
+}
@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/isurusiri/tipsy/internal/chaos"
+	"github.com/isurusiri/tipsy/internal/config"
+	"github.com/isurusiri/tipsy/internal/state"
+	"github.com/isurusiri/tipsy/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+var (
+	configFaultConfigMap  string
+	configFaultSecret     string
+	configFaultNamespace  string
+	configFaultSet        []string
+	configFaultDeleteKeys []string
+	configFaultDuration   string
+)
+
+// configFaultCmd represents the config-fault command
+var configFaultCmd = &cobra.Command{
+	Use:   "config-fault",
+	Short: "Inject bad configuration by mutating a ConfigMap or Secret",
+	Long: `Inject bad configuration by setting or deleting keys in a ConfigMap or Secret.
+
+This command will:
+1. Fetch the target ConfigMap or Secret
+2. Save the original object under ~/.tipsy/rollback for rollback purposes
+3. Set and delete the requested keys
+4. With --duration, restore the original object once the duration has passed
+
+Rollback refuses to overwrite the object if someone else has changed it since
+the fault was injected; the original stays in the backup file.
+
+Secret values given to --set are plain text.
+
+Examples:
+  tipsy config-fault --configmap app-config --delete-key database_url
+  tipsy config-fault --configmap app-config --set log_level=garbage --duration 2m
+  tipsy config-fault --secret db-credentials --set password=rotated --namespace payments
+  tipsy config-fault --secret db-credentials --delete-key password --dry-run`,
+	Run: func(cmd *cobra.Command, args []string) {
+		// Print configuration if verbose mode is enabled
+		PrintConfig()
+
+		// Validate that exactly one object is specified
+		if configFaultConfigMap == "" && configFaultSecret == "" {
+			utils.Error("either --configmap or --secret must be specified")
+			cmd.Help()
+			return
+		}
+
+		if configFaultConfigMap != "" && configFaultSecret != "" {
+			utils.Error("--configmap and --secret cannot be used together")
+			cmd.Help()
+			return
+		}
+
+		if len(configFaultSet) == 0 && len(configFaultDeleteKeys) == 0 {
+			utils.Error("at least one --set or --delete-key must be specified")
+			cmd.Help()
+			return
+		}
+
+		values, err := chaos.ParseKeyValues(configFaultSet)
+		if err != nil {
+			utils.Error(err.Error())
+			return
+		}
+
+		kind, name := chaos.KindConfigMap, configFaultConfigMap
+		if configFaultSecret != "" {
+			kind, name = chaos.KindSecret, configFaultSecret
+		}
+
+		// Use global namespace if not specified locally
+		targetNamespace := configFaultNamespace
+		if targetNamespace == "" {
+			targetNamespace = config.GlobalConfig.Namespace
+		}
+		if targetNamespace == "" {
+			targetNamespace = "default"
+		}
+
+		// Parse duration
+		durationParsed, err := time.ParseDuration(configFaultDuration)
+		if err != nil {
+			utils.Error(fmt.Sprintf("Invalid duration format '%s': %v", configFaultDuration, err))
+			return
+		}
+
+		// Create Kubernetes client
+		client, err := newClient()
+		if err != nil {
+			utils.Error(fmt.Sprintf("Failed to create Kubernetes client: %v", err))
+			return
+		}
+
+		// Catch interrupts before injecting, so one that arrives before the hold starts still rolls back
+		signals, release := catchSignalsForHold(durationParsed)
+		defer release()
+
+		// Execute the config fault
+		result, err := chaos.MutateConfig(client, targetNamespace, kind, name, values, configFaultDeleteKeys, config.GlobalConfig.DryRun)
+		if err != nil {
+			utils.Error(fmt.Sprintf("Failed to inject config fault: %v", err))
+			return
+		}
+
+		// Save state for the config fault
+		var actions []state.ChaosAction
+		if !config.GlobalConfig.DryRun {
+			action := state.ChaosAction{
+				ID:        state.NewActionID(),
+				Type:      "config-fault",
+				TargetPod: name, // Using object name as target
+				TargetUID: string(result.UID),
+				Namespace: targetNamespace,
+				Timestamp: time.Now().UTC().Format(time.RFC3339),
+				Metadata: map[string]string{
+					"kind":       kind,
+					"keys":       strings.Join(result.Changed, ","),
+					"backupPath": result.BackupPath,
+					"checksum":   result.Checksum,
+					"duration":   configFaultDuration,
+				},
+			}
+			if err := state.SaveAction(action); err != nil {
+				utils.Warn(fmt.Sprintf("Failed to save state for %s '%s': %v", kind, name, err))
+			} else {
+				actions = append(actions, action)
+			}
+		}
+
+		utils.Info("Config fault operation completed successfully")
+
+		holdFault(client, signals, actions, durationParsed)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(configFaultCmd)
+
+	// Local flags for the config-fault command
+	configFaultCmd.Flags().StringVar(&configFaultConfigMap, "configmap", "", "Name of the ConfigMap to mutate")
+	configFaultCmd.Flags().StringVar(&configFaultSecret, "secret", "", "Name of the Secret to mutate")
+	configFaultCmd.Flags().StringVar(&configFaultNamespace, "namespace", "", "Kubernetes namespace to operate in (optional, defaults to global namespace or 'default')")
+	configFaultCmd.Flags().StringArrayVar(&configFaultSet, "set", nil, "Set a key to a value, as key=value (repeatable)")
+	configFaultCmd.Flags().StringArrayVar(&configFaultDeleteKeys, "delete-key", nil, "Delete a key (repeatable)")
+	configFaultCmd.Flags().StringVar(&configFaultDuration, "duration", "0s", "How long to keep the mutation before restoring the original (e.g., '30s', '5m'); 0 keeps it until 'tipsy rollback'")
+}
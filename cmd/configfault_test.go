@@ -0,0 +1,19 @@
+package cmd
+
+import (
+	"testing"
+)
+
+func TestConfigFaultCmd(t *testing.T) {
+	// Test that the config-fault command is registered with the expected flags
+	configFaultCommand := getCommand("config-fault")
+	if configFaultCommand == nil {
+		t.Fatal("config-fault command not found in root command")
+	}
+
+	for _, flag := range []string{"configmap", "secret", "namespace", "set", "delete-key", "duration"} {
+		if configFaultCommand.Flag(flag) == nil {
+			t.Errorf("config-fault command missing --%s flag", flag)
+		}
+	}
+}
@@ -6,7 +6,6 @@ import (
 
 	"github.com/isurusiri/tipsy/internal/chaos"
 	"github.com/isurusiri/tipsy/internal/config"
-	"github.com/isurusiri/tipsy/internal/k8s"
 	"github.com/isurusiri/tipsy/internal/state"
 	"github.com/isurusiri/tipsy/internal/utils"
 	"github.com/spf13/cobra"
@@ -17,6 +16,7 @@ var (
 	cpuStressNamespace string
 	cpuStressDuration  string
 	cpuStressMethod    string
+	cpuStressOrdinals  ordinalFlags
 )
 
 // cpustressCmd represents the cpustress command
@@ -69,14 +69,15 @@ Examples:
 		}
 
 		// Create Kubernetes client
-		client, err := k8s.NewClient(config.GlobalConfig.Kubeconfig)
+		client, err := newClient()
 		if err != nil {
 			utils.Error(fmt.Sprintf("Failed to create Kubernetes client: %v", err))
 			return
 		}
 
 		// Execute the CPU stress injection
-		affectedPods, err := chaos.InjectCPUStress(client, targetNamespace, cpuStressSelector, cpuStressMethod, durationParsed, config.GlobalConfig.DryRun)
+		target := cpuStressOrdinals.apply(chaos.PodTarget{Namespace: targetNamespace, Selector: cpuStressSelector})
+		affectedPods, err := chaos.InjectCPUStressForTarget(client, target, cpuStressMethod, durationParsed, config.GlobalConfig.DryRun)
 		if err != nil {
 			utils.Error(fmt.Sprintf("Failed to inject CPU stress: %v", err))
 			return
@@ -84,10 +85,10 @@ Examples:
 
 		// Save state for each affected pod
 		if !config.GlobalConfig.DryRun {
-			for _, podName := range affectedPods {
-				action := state.ChaosAction{
+			for _, pod := range affectedPods {
+				action := withPodIdentity(state.ChaosAction{
 					Type:      "cpustress",
-					TargetPod: podName,
+					TargetPod: pod.Name,
 					Namespace: targetNamespace,
 					Timestamp: time.Now().UTC().Format(time.RFC3339),
 					Metadata: map[string]string{
@@ -95,9 +96,9 @@ Examples:
 						"duration": cpuStressDuration,
 						"selector": cpuStressSelector,
 					},
-				}
+				}, pod)
 				if err := state.SaveAction(action); err != nil {
-					utils.Warn(fmt.Sprintf("Failed to save state for pod '%s': %v", podName, err))
+					utils.Warn(fmt.Sprintf("Failed to save state for pod '%s': %v", pod.Name, err))
 				}
 			}
 		}
@@ -114,6 +115,7 @@ func init() {
 	cpustressCmd.Flags().StringVar(&cpuStressNamespace, "namespace", "", "Kubernetes namespace to operate in (optional, defaults to global namespace or 'default')")
 	cpustressCmd.Flags().StringVar(&cpuStressDuration, "duration", "60s", "How long to run CPU stress (e.g., '30s', '1m', '5m')")
 	cpustressCmd.Flags().StringVar(&cpuStressMethod, "method", "stress-ng", "CPU stress method: 'stress-ng' or 'yes'")
+	cpuStressOrdinals.register(cpustressCmd)
 
 	// Mark selector as required
 	cpustressCmd.MarkFlagRequired("selector")
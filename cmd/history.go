@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/isurusiri/tipsy/internal/config"
+	"github.com/isurusiri/tipsy/internal/state"
+	"github.com/isurusiri/tipsy/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+var (
+	historySince     string
+	historyType      string
+	historyNamespace string
+	historyOutput    string
+)
+
+// historyCmd represents the history command
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Show the history of chaos experiments",
+	Long: `Show every fault tipsy has injected and rolled back, as recorded in
+~/.tipsy/history.jsonl.
+
+Unlike 'tipsy list', which only shows the faults currently recorded in state,
+history keeps every injection, rollback and failed rollback, so past game days
+can be audited.
+
+--since takes a duration relative to now (24h, 30m), a date (2024-05-01) or an
+RFC3339 timestamp (2024-05-01T14:00:00Z).
+
+Examples:
+  tipsy history                          # Show the full history
+  tipsy history --since 24h              # Show the last day
+  tipsy history --since 2024-05-01       # Show everything since a date
+  tipsy history --type scale             # Show only scale actions
+  tipsy history --namespace production -o json`,
+	Run: func(cmd *cobra.Command, args []string) {
+		// Print configuration if verbose mode is enabled
+		PrintConfig()
+
+		if historyOutput != outputTable && historyOutput != outputJSON {
+			utils.Error(fmt.Sprintf("Invalid output format '%s': must be '%s' or '%s'", historyOutput, outputTable, outputJSON))
+			cmd.Help()
+			return
+		}
+
+		since, err := parseSince(historySince, time.Now())
+		if err != nil {
+			utils.Error(err.Error())
+			cmd.Help()
+			return
+		}
+
+		// Use global namespace if not specified locally
+		filterNamespace := historyNamespace
+		if filterNamespace == "" {
+			filterNamespace = config.GlobalConfig.Namespace
+		}
+
+		events, err := state.LoadHistory(state.HistoryFilter{Since: since, Type: historyType, Namespace: filterNamespace})
+		if err != nil {
+			utils.Error(fmt.Sprintf("Failed to load history: %v", err))
+			return
+		}
+
+		if historyOutput == outputJSON {
+			err = writeHistoryJSON(cmd.OutOrStdout(), events)
+		} else {
+			err = writeHistoryTable(cmd.OutOrStdout(), events)
+		}
+		if err != nil {
+			utils.Error(fmt.Sprintf("Failed to print history: %v", err))
+		}
+	},
+}
+
+// parseSince parses the --since flag: a duration back from now, a date or an RFC3339 timestamp
+// An empty value means the whole history
+func parseSince(value string, now time.Time) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	if duration, err := time.ParseDuration(value); err == nil {
+		if duration < 0 {
+			return time.Time{}, fmt.Errorf("invalid --since '%s': duration must not be negative", value)
+		}
+		return now.Add(-duration), nil
+	}
+	if at, err := time.Parse(time.RFC3339, value); err == nil {
+		return at, nil
+	}
+	if at, err := time.ParseInLocation(time.DateOnly, value, time.Local); err == nil {
+		return at, nil
+	}
+
+	return time.Time{}, fmt.Errorf("invalid --since '%s': must be a duration (24h), a date (2024-05-01) or an RFC3339 timestamp", value)
+}
+
+// writeHistoryJSON prints history events as a pretty-printed JSON array
+func writeHistoryJSON(out io.Writer, events []state.HistoryEvent) error {
+	data, err := json.MarshalIndent(events, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal history to JSON: %w", err)
+	}
+
+	_, err = fmt.Fprintln(out, string(data))
+	return err
+}
+
+// writeHistoryTable prints history events as an aligned table, one row per event
+// ELAPSED is how long the fault had been in place when it was rolled back
+func writeHistoryTable(out io.Writer, events []state.HistoryEvent) error {
+	if len(events) == 0 {
+		_, err := fmt.Fprintln(out, "No history recorded")
+		return err
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "TIME\tEVENT\tID\tTYPE\tTARGET\tNAMESPACE\tELAPSED\tERROR")
+	for _, event := range events {
+		elapsed := "-"
+		if event.Event != state.EventInjected && event.Elapsed() > 0 {
+			elapsed = event.Elapsed().Round(time.Second).String()
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			event.Time.Format(time.RFC3339), event.Event, orDash(event.ActionID), event.Type, event.Target,
+			orDash(event.Namespace), elapsed, orDash(event.Error))
+	}
+
+	return w.Flush()
+}
+
+func init() {
+	rootCmd.AddCommand(historyCmd)
+
+	// Local flags for the history command
+	historyCmd.Flags().StringVar(&historySince, "since", "", "Show only events since a duration ago (24h), a date (2024-05-01) or an RFC3339 timestamp")
+	historyCmd.Flags().StringVar(&historyType, "type", "", "Show only events for actions of specific type")
+	historyCmd.Flags().StringVar(&historyNamespace, "namespace", "", "Show only events in this namespace (optional, defaults to global namespace or all namespaces)")
+	historyCmd.Flags().StringVarP(&historyOutput, "output", "o", outputTable, "Output format (table or json)")
+}
@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/isurusiri/tipsy/internal/state"
+)
+
+func TestHistoryCmdFlags(t *testing.T) {
+	command := getCommand("history")
+	if command == nil {
+		t.Fatal("history command not found in root command")
+	}
+
+	for _, flag := range []string{"since", "type", "namespace", "output"} {
+		if command.Flag(flag) == nil {
+			t.Errorf("history command missing --%s flag", flag)
+		}
+	}
+}
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2024, 5, 2, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		value    string
+		expected time.Time
+	}{
+		{"", time.Time{}},
+		{"24h", time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)},
+		{"2024-05-01T14:00:00Z", time.Date(2024, 5, 1, 14, 0, 0, 0, time.UTC)},
+		{"2024-05-01", time.Date(2024, 5, 1, 0, 0, 0, 0, time.Local)},
+	}
+
+	for _, tc := range testCases {
+		since, err := parseSince(tc.value, now)
+		if err != nil {
+			t.Errorf("Unexpected error for '%s': %v", tc.value, err)
+			continue
+		}
+		if !since.Equal(tc.expected) {
+			t.Errorf("Expected '%s' to parse as %v, got %v", tc.value, tc.expected, since)
+		}
+	}
+
+	for _, value := range []string{"yesterday", "-1h", "05/01/2024"} {
+		if _, err := parseSince(value, now); err == nil {
+			t.Errorf("Expected an error for '%s'", value)
+		}
+	}
+}
+
+func TestWriteHistoryTable(t *testing.T) {
+	started := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	events := []state.HistoryEvent{
+		{Time: started, Event: state.EventInjected, ActionID: "aaa111", Type: "scale", Target: "web", Namespace: "default", StartedAt: started},
+		{Time: started.Add(90 * time.Second), Event: state.EventRollbackFailed, ActionID: "aaa111", Type: "scale", Target: "web",
+			Namespace: "default", StartedAt: started, Error: "deployment is being deleted"},
+	}
+
+	var out bytes.Buffer
+	if err := writeHistoryTable(&out, events); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Expected a header and 2 rows, got:\n%s", out.String())
+	}
+	if fields := strings.Fields(lines[1]); fields[1] != state.EventInjected || fields[6] != "-" {
+		t.Errorf("Unexpected injection row: %s", lines[1])
+	}
+	if !strings.Contains(lines[2], "1m30s") || !strings.Contains(lines[2], "deployment is being deleted") {
+		t.Errorf("Expected the failed rollback to show its elapsed time and error, got: %s", lines[2])
+	}
+
+	out.Reset()
+	if err := writeHistoryTable(&out, nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if strings.TrimSpace(out.String()) != "No history recorded" {
+		t.Errorf("Unexpected output for empty history: %s", out.String())
+	}
+}
@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/isurusiri/tipsy/internal/config"
+	"github.com/isurusiri/tipsy/internal/rollback"
+	"github.com/isurusiri/tipsy/internal/state"
+	"github.com/isurusiri/tipsy/internal/utils"
+	"k8s.io/client-go/kubernetes"
+)
+
+// holdSignals end a hold early; the fault is rolled back all the same
+var holdSignals = []os.Signal{os.Interrupt, syscall.SIGTERM}
+
+// catchSignalsForHold starts catching holdSignals before a fault with the given duration is
+// injected, so that a signal arriving before the hold starts still rolls the fault back
+// Nothing is caught when there will be no hold; release stops catching them
+func catchSignalsForHold(duration time.Duration) (<-chan os.Signal, func()) {
+	if duration <= 0 || config.GlobalConfig.DryRun {
+		return nil, func() {}
+	}
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, holdSignals...)
+	return signals, func() { signal.Stop(signals) }
+}
+
+// holdFault keeps a fault in place for the given duration and then rolls back the actions recorded for it.
+// A zero duration leaves the fault in place until `tipsy rollback` is run.
+// signals come from catchSignalsForHold, which must not be released until holdFault returns:
+// SIGINT or SIGTERM, even one received during injection, cuts the hold short but still rolls back,
+// and cannot interrupt the rollback itself.
+func holdFault(client kubernetes.Interface, signals <-chan os.Signal, actions []state.ChaosAction, duration time.Duration) {
+	if duration <= 0 || len(actions) == 0 || config.GlobalConfig.DryRun {
+		return
+	}
+
+	utils.Info(fmt.Sprintf("Holding fault for %s before rolling back; interrupt to roll back early", duration))
+	if sig := waitForHold(os.Stdout, isTerminal(os.Stdout), duration, time.Second, signals); sig != nil {
+		utils.Warn(fmt.Sprintf("Received %s, rolling back now", sig))
+	}
+
+	_, failedActions := rollback.RollbackActions(client, actions, false, false)
+	if len(failedActions) > 0 {
+		utils.Warn(fmt.Sprintf("Failed to rollback %d action(s), run 'tipsy rollback' to retry", len(failedActions)))
+	}
+}
+
+// catchHoldSignals catches holdSignals until release is called, for faults that restore
+// themselves and must not be cut short by an interrupt
+// The returned channel is closed on the first signal
+func catchHoldSignals() (<-chan struct{}, func()) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, holdSignals...)
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		select {
+		case sig := <-signals:
+			utils.Warn(fmt.Sprintf("Received %s, rolling back now", sig))
+			close(stop)
+		case <-done:
+		}
+	}()
+
+	return stop, func() {
+		signal.Stop(signals)
+		close(done)
+	}
+}
+
+// waitForHold blocks until the duration has passed or a signal arrives, and returns the
+// signal, or nil if the duration passed
+// With countdown set, the time left is redrawn on one line of out every tick
+func waitForHold(out io.Writer, countdown bool, duration, tick time.Duration, signals <-chan os.Signal) os.Signal {
+	deadline := time.Now().Add(duration)
+
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+	ticker := time.NewTicker(tick)
+	defer ticker.Stop()
+
+	if countdown {
+		// Clear the countdown line once the hold is over
+		defer fmt.Fprint(out, "\r\033[K")
+	}
+
+	for {
+		if countdown {
+			fmt.Fprintf(out, "\r\033[KRolling back in %s", time.Until(deadline).Round(time.Second))
+		}
+
+		select {
+		case <-timer.C:
+			return nil
+		case sig := <-signals:
+			return sig
+		case <-ticker.C:
+		}
+	}
+}
+
+// isTerminal reports whether a file is a terminal, where a countdown can be redrawn in place
+func isTerminal(file *os.File) bool {
+	info, err := file.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
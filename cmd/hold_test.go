@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestWaitForHold(t *testing.T) {
+	var out bytes.Buffer
+	if sig := waitForHold(&out, true, 30*time.Millisecond, 10*time.Millisecond, make(chan os.Signal)); sig != nil {
+		t.Errorf("Expected the hold to run out, got %v", sig)
+	}
+	if !strings.Contains(out.String(), "Rolling back in") {
+		t.Errorf("Expected a countdown, got %q", out.String())
+	}
+	if !strings.HasSuffix(out.String(), "\r\033[K") {
+		t.Errorf("Expected the countdown line to be cleared, got %q", out.String())
+	}
+
+	// Without a terminal nothing is drawn
+	out.Reset()
+	waitForHold(&out, false, 10*time.Millisecond, time.Millisecond, make(chan os.Signal))
+	if out.Len() != 0 {
+		t.Errorf("Expected no countdown, got %q", out.String())
+	}
+}
+
+func TestWaitForHoldSignal(t *testing.T) {
+	signals := make(chan os.Signal, 1)
+	signals <- syscall.SIGTERM
+
+	start := time.Now()
+	if sig := waitForHold(&bytes.Buffer{}, false, time.Minute, time.Second, signals); sig != syscall.SIGTERM {
+		t.Errorf("Expected SIGTERM, got %v", sig)
+	}
+	if time.Since(start) > 5*time.Second {
+		t.Error("Expected the signal to end the hold early")
+	}
+}
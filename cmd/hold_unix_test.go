@@ -0,0 +1,142 @@
+//go:build unix
+
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/isurusiri/tipsy/internal/config"
+	"github.com/isurusiri/tipsy/internal/state"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// swappedServiceFixture returns a client holding a selector-swapped service and the action
+// recorded for the swap, with state kept in a temporary file
+func swappedServiceFixture(t *testing.T) (*fake.Clientset, state.ChaosAction) {
+	t.Helper()
+
+	// Reset global config for testing
+	config.GlobalConfig = config.Config{}
+
+	t.Cleanup(state.ReloadStateFilePath)
+	t.Setenv("TIPSY_STATE_FILE", filepath.Join(t.TempDir(), "state.json"))
+	state.ReloadStateFilePath()
+
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Spec:       corev1.ServiceSpec{Selector: map[string]string{"app": "decoy"}},
+	}
+	client := fake.NewSimpleClientset(service)
+
+	action := state.ChaosAction{
+		ID:        "abc123",
+		Type:      "misroute",
+		TargetPod: "web",
+		Namespace: "default",
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+		Metadata: map[string]string{
+			"mode":             "selector-swap",
+			"originalSelector": "app=web",
+			"swappedSelector":  "app=decoy",
+		},
+	}
+	return client, action
+}
+
+// expectSelectorRestored fails the test unless the fixture service has its original selector back
+func expectSelectorRestored(t *testing.T, client *fake.Clientset) {
+	t.Helper()
+
+	restored, err := client.CoreV1().Services("default").Get(context.TODO(), "web", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Failed to get service: %v", err)
+	}
+	if restored.Spec.Selector["app"] != "web" {
+		t.Errorf("Expected the original selector restored, got %v", restored.Spec.Selector)
+	}
+}
+
+func TestHoldFaultRollsBackOnSIGTERM(t *testing.T) {
+	// Disable color for testing
+	originalNoColor := color.NoColor
+	color.NoColor = true
+	defer func() {
+		color.NoColor = originalNoColor
+	}()
+
+	client, action := swappedServiceFixture(t)
+	signals, release := catchSignalsForHold(time.Minute)
+	defer release()
+
+	if err := state.SaveAction(action); err != nil {
+		t.Fatalf("Failed to save action: %v", err)
+	}
+
+	go func() {
+		time.Sleep(200 * time.Millisecond)
+		syscall.Kill(os.Getpid(), syscall.SIGTERM)
+	}()
+
+	start := time.Now()
+	holdFault(client, signals, []state.ChaosAction{action}, time.Minute)
+	if time.Since(start) > 30*time.Second {
+		t.Fatal("Expected SIGTERM to end the hold early")
+	}
+
+	expectSelectorRestored(t, client)
+}
+
+func TestHoldFaultRollsBackOnSIGTERMBeforeHold(t *testing.T) {
+	// Disable color for testing
+	originalNoColor := color.NoColor
+	color.NoColor = true
+	defer func() {
+		color.NoColor = originalNoColor
+	}()
+
+	client, action := swappedServiceFixture(t)
+	signals, release := catchSignalsForHold(time.Minute)
+	defer release()
+
+	// The signal arrives while the fault is still being injected and recorded
+	syscall.Kill(os.Getpid(), syscall.SIGTERM)
+	if err := state.SaveAction(action); err != nil {
+		t.Fatalf("Failed to save action: %v", err)
+	}
+
+	start := time.Now()
+	holdFault(client, signals, []state.ChaosAction{action}, time.Minute)
+	if time.Since(start) > 30*time.Second {
+		t.Fatal("Expected the earlier SIGTERM to end the hold at once")
+	}
+
+	expectSelectorRestored(t, client)
+}
+
+func TestCatchHoldSignals(t *testing.T) {
+	// Disable color for testing
+	originalNoColor := color.NoColor
+	color.NoColor = true
+	defer func() {
+		color.NoColor = originalNoColor
+	}()
+
+	stop, release := catchHoldSignals()
+	defer release()
+
+	syscall.Kill(os.Getpid(), syscall.SIGINT)
+
+	select {
+	case <-stop:
+	case <-time.After(30 * time.Second):
+		t.Fatal("Expected SIGINT to close the stop channel")
+	}
+}
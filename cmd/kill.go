@@ -6,16 +6,17 @@ import (
 
 	"github.com/isurusiri/tipsy/internal/chaos"
 	"github.com/isurusiri/tipsy/internal/config"
-	"github.com/isurusiri/tipsy/internal/k8s"
 	"github.com/isurusiri/tipsy/internal/state"
 	"github.com/isurusiri/tipsy/internal/utils"
 	"github.com/spf13/cobra"
 )
 
 var (
-	selector  string
-	namespace string
-	count     int
+	selector        string
+	namespace       string
+	count           int
+	killLeaderLease string
+	killOrdinals    ordinalFlags
 )
 
 // killCmd represents the kill command
@@ -29,39 +30,50 @@ This command will:
 2. Randomly select the specified number of pods to kill
 3. Delete the selected pods (or simulate deletion in dry-run mode)
 
+With --leader-lease, the pod holding the Lease is killed instead and tipsy
+reports how long it takes for a new holder to take over.
+
 Examples:
   tipsy kill --selector "app=nginx" --count 2
   tipsy kill --selector "environment=staging" --namespace production --count 1
-  tipsy kill --selector "tier=frontend" --dry-run --verbose`,
+  tipsy kill --selector "tier=frontend" --dry-run --verbose
+  tipsy kill --leader-lease kube-system/my-controller
+  tipsy kill --selector "app=postgres" --ordinal 0`,
 	Run: func(cmd *cobra.Command, args []string) {
 		// Print configuration if verbose mode is enabled
 		PrintConfig()
 
 		// Validate required flags
-		if selector == "" {
-			utils.Error("--selector flag is required")
+		if selector == "" && killLeaderLease == "" {
+			utils.Error("either --selector or --leader-lease must be specified")
 			cmd.Help()
 			return
 		}
 
 		// Use global namespace if not specified locally
-		targetNamespace := namespace
-		if targetNamespace == "" {
-			targetNamespace = config.GlobalConfig.Namespace
-		}
-		if targetNamespace == "" {
-			targetNamespace = "default"
+		targetNamespace, err := targetNamespaceFor(namespace, killLeaderLease)
+		if err != nil {
+			utils.Error(err.Error())
+			return
 		}
 
 		// Create Kubernetes client
-		client, err := k8s.NewClient(config.GlobalConfig.Kubeconfig)
+		client, err := newClient()
 		if err != nil {
 			utils.Error(fmt.Sprintf("Failed to create Kubernetes client: %v", err))
 			return
 		}
 
+		// Record the current leader so the failover can be timed
+		watch, err := startLeaderWatch(client, killLeaderLease)
+		if err != nil {
+			utils.Error(fmt.Sprintf("Failed to read leader lease: %v", err))
+			return
+		}
+
 		// Execute the kill operation
-		killedPods, err := chaos.KillPods(client, targetNamespace, selector, count, config.GlobalConfig.DryRun)
+		target := killOrdinals.apply(chaos.PodTarget{Namespace: targetNamespace, Selector: selector, LeaderLease: killLeaderLease})
+		killedPods, err := chaos.KillPodsForTarget(client, target, count, config.GlobalConfig.DryRun)
 		if err != nil {
 			utils.Error(fmt.Sprintf("Failed to kill pods: %v", err))
 			return
@@ -69,23 +81,27 @@ Examples:
 
 		// Save state for each killed pod
 		if !config.GlobalConfig.DryRun {
-			for _, podName := range killedPods {
-				action := state.ChaosAction{
+			for _, pod := range killedPods {
+				action := withPodIdentity(state.ChaosAction{
 					Type:      "kill",
-					TargetPod: podName,
+					TargetPod: pod.Name,
 					Namespace: targetNamespace,
 					Timestamp: time.Now().UTC().Format(time.RFC3339),
-					Metadata: map[string]string{
+					Metadata: watch.leaderMetadata(map[string]string{
 						"selector": selector,
 						"count":    fmt.Sprintf("%d", count),
-					},
-				}
+					}),
+				}, pod)
 				if err := state.SaveAction(action); err != nil {
-					utils.Warn(fmt.Sprintf("Failed to save state for pod '%s': %v", podName, err))
+					utils.Warn(fmt.Sprintf("Failed to save state for pod '%s': %v", pod.Name, err))
 				}
 			}
 		}
 
+		if len(killedPods) > 0 {
+			watch.wait(client, chaos.DefaultLeaderTimeout)
+		}
+
 		utils.Info("Kill operation completed successfully")
 	},
 }
@@ -94,10 +110,9 @@ func init() {
 	rootCmd.AddCommand(killCmd)
 
 	// Local flags for the kill command
-	killCmd.Flags().StringVar(&selector, "selector", "", "Kubernetes label selector (required)")
+	killCmd.Flags().StringVar(&selector, "selector", "", "Kubernetes label selector (required unless --leader-lease is set)")
 	killCmd.Flags().StringVar(&namespace, "namespace", "", "Kubernetes namespace to operate in (optional, defaults to global namespace or 'default')")
 	killCmd.Flags().IntVar(&count, "count", 1, "Number of pods to kill (optional, default 1)")
-
-	// Mark selector as required
-	killCmd.MarkFlagRequired("selector")
+	killCmd.Flags().StringVar(&killLeaderLease, "leader-lease", "", "Kill the pod holding this coordination Lease, as namespace/name")
+	killOrdinals.register(killCmd)
 }
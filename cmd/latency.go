@@ -6,17 +6,18 @@ import (
 
 	"github.com/isurusiri/tipsy/internal/chaos"
 	"github.com/isurusiri/tipsy/internal/config"
-	"github.com/isurusiri/tipsy/internal/k8s"
 	"github.com/isurusiri/tipsy/internal/state"
 	"github.com/isurusiri/tipsy/internal/utils"
 	"github.com/spf13/cobra"
 )
 
 var (
-	latencySelector  string
-	latencyNamespace string
-	delay            string
-	duration         string
+	latencySelector    string
+	latencyNamespace   string
+	delay              string
+	duration           string
+	latencyLeaderLease string
+	latencyOrdinals    ordinalFlags
 )
 
 // latencyCmd represents the latency command
@@ -30,28 +31,31 @@ This command will:
 2. Add ephemeral containers to inject network latency using tc netem
 3. The latency will be applied for the specified duration
 
+With --leader-lease, only the pod holding the Lease is targeted and tipsy
+reports how long it takes for a new holder to take over.
+
 Examples:
   tipsy latency --selector "app=nginx" --delay "200ms" --duration "30s"
   tipsy latency --selector "environment=staging" --namespace production --delay "500ms" --duration "1m"
-  tipsy latency --selector "tier=frontend" --dry-run --verbose`,
+  tipsy latency --selector "tier=frontend" --dry-run --verbose
+  tipsy latency --leader-lease kube-system/my-controller --delay "500ms" --duration "1m"
+  tipsy latency --selector "app=kafka" --except-ordinal 0 --delay "300ms" --duration "2m"`,
 	Run: func(cmd *cobra.Command, args []string) {
 		// Print configuration if verbose mode is enabled
 		PrintConfig()
 
 		// Validate required flags
-		if latencySelector == "" {
-			utils.Error("--selector flag is required")
+		if latencySelector == "" && latencyLeaderLease == "" {
+			utils.Error("either --selector or --leader-lease must be specified")
 			cmd.Help()
 			return
 		}
 
 		// Use global namespace if not specified locally
-		targetNamespace := latencyNamespace
-		if targetNamespace == "" {
-			targetNamespace = config.GlobalConfig.Namespace
-		}
-		if targetNamespace == "" {
-			targetNamespace = "default"
+		targetNamespace, err := targetNamespaceFor(latencyNamespace, latencyLeaderLease)
+		if err != nil {
+			utils.Error(err.Error())
+			return
 		}
 
 		// Parse duration
@@ -62,14 +66,22 @@ Examples:
 		}
 
 		// Create Kubernetes client
-		client, err := k8s.NewClient(config.GlobalConfig.Kubeconfig)
+		client, err := newClient()
 		if err != nil {
 			utils.Error(fmt.Sprintf("Failed to create Kubernetes client: %v", err))
 			return
 		}
 
+		// Record the current leader so the failover can be timed
+		watch, err := startLeaderWatch(client, latencyLeaderLease)
+		if err != nil {
+			utils.Error(fmt.Sprintf("Failed to read leader lease: %v", err))
+			return
+		}
+
 		// Execute the latency injection
-		affectedPods, err := chaos.InjectLatency(client, targetNamespace, latencySelector, delay, durationParsed, config.GlobalConfig.DryRun)
+		target := latencyOrdinals.apply(chaos.PodTarget{Namespace: targetNamespace, Selector: latencySelector, LeaderLease: latencyLeaderLease})
+		affectedPods, err := chaos.InjectLatencyForTarget(client, target, delay, durationParsed, config.GlobalConfig.DryRun)
 		if err != nil {
 			utils.Error(fmt.Sprintf("Failed to inject latency: %v", err))
 			return
@@ -77,24 +89,29 @@ Examples:
 
 		// Save state for each affected pod
 		if !config.GlobalConfig.DryRun {
-			for _, podName := range affectedPods {
-				action := state.ChaosAction{
+			for _, pod := range affectedPods {
+				action := withPodIdentity(state.ChaosAction{
 					Type:      "latency",
-					TargetPod: podName,
+					TargetPod: pod.Name,
 					Namespace: targetNamespace,
 					Timestamp: time.Now().UTC().Format(time.RFC3339),
-					Metadata: map[string]string{
+					Metadata: watch.leaderMetadata(map[string]string{
 						"delay":    delay,
 						"duration": duration,
 						"selector": latencySelector,
-					},
-				}
+					}),
+				}, pod)
 				if err := state.SaveAction(action); err != nil {
-					utils.Warn(fmt.Sprintf("Failed to save state for pod '%s': %v", podName, err))
+					utils.Warn(fmt.Sprintf("Failed to save state for pod '%s': %v", pod.Name, err))
 				}
 			}
 		}
 
+		// An isolated or slowed leader may lose its Lease while the fault is active
+		if len(affectedPods) > 0 {
+			watch.wait(client, durationParsed)
+		}
+
 		utils.Info("Latency injection operation completed successfully")
 	},
 }
@@ -103,11 +120,10 @@ func init() {
 	rootCmd.AddCommand(latencyCmd)
 
 	// Local flags for the latency command
-	latencyCmd.Flags().StringVar(&latencySelector, "selector", "", "Kubernetes label selector (required)")
+	latencyCmd.Flags().StringVar(&latencySelector, "selector", "", "Kubernetes label selector (required unless --leader-lease is set)")
 	latencyCmd.Flags().StringVar(&latencyNamespace, "namespace", "", "Kubernetes namespace to operate in (optional, defaults to global namespace or 'default')")
 	latencyCmd.Flags().StringVar(&delay, "delay", "200ms", "Network delay to inject (e.g., '200ms', '500ms', '1s')")
 	latencyCmd.Flags().StringVar(&duration, "duration", "30s", "How long to keep latency active (e.g., '30s', '1m', '5m')")
-
-	// Mark selector as required
-	latencyCmd.MarkFlagRequired("selector")
+	latencyCmd.Flags().StringVar(&latencyLeaderLease, "leader-lease", "", "Target only the pod holding this coordination Lease, as namespace/name")
+	latencyOrdinals.register(latencyCmd)
 }
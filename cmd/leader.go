@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/isurusiri/tipsy/internal/chaos"
+	"github.com/isurusiri/tipsy/internal/config"
+	"github.com/isurusiri/tipsy/internal/utils"
+	"k8s.io/client-go/kubernetes"
+)
+
+// targetNamespaceFor picks the namespace for a pod fault: the local flag, then the global flag,
+// then the namespace of the leader Lease if one is given, then 'default'
+func targetNamespaceFor(localNamespace, leaderLease string) (string, error) {
+	if localNamespace != "" {
+		return localNamespace, nil
+	}
+	if config.GlobalConfig.Namespace != "" {
+		return config.GlobalConfig.Namespace, nil
+	}
+	if leaderLease != "" {
+		leaseNamespace, _, err := chaos.ParseLeaseRef(leaderLease)
+		if err != nil {
+			return "", err
+		}
+		return leaseNamespace, nil
+	}
+	return "default", nil
+}
+
+// leaderWatch records the holder of a Lease before a fault so the failover can be timed afterwards
+type leaderWatch struct {
+	lease  string
+	holder string
+	start  time.Time
+}
+
+// startLeaderWatch records the current holder of a Lease
+// Returns nil if no Lease was given or in dry-run mode
+func startLeaderWatch(client kubernetes.Interface, leaderLease string) (*leaderWatch, error) {
+	if leaderLease == "" || config.GlobalConfig.DryRun {
+		return nil, nil
+	}
+
+	holder, err := chaos.GetLeaseHolder(client, leaderLease)
+	if err != nil {
+		return nil, err
+	}
+
+	return &leaderWatch{lease: leaderLease, holder: holder, start: time.Now()}, nil
+}
+
+// wait blocks until a new holder takes the Lease or the timeout passes, and reports how long it took
+func (w *leaderWatch) wait(client kubernetes.Interface, timeout time.Duration) {
+	if w == nil {
+		return
+	}
+
+	newHolder, elapsed, err := chaos.WaitForNewLeader(client, w.lease, w.holder, w.start, timeout)
+	if err != nil {
+		utils.Warn(fmt.Sprintf("Leader failover not observed: %v", err))
+		return
+	}
+
+	utils.Info(fmt.Sprintf("Leader failover: '%s' -> '%s' in %s", w.holder, newHolder, elapsed.Round(time.Millisecond)))
+}
+
+// leaderMetadata adds the Lease and its original holder to action metadata
+func (w *leaderWatch) leaderMetadata(metadata map[string]string) map[string]string {
+	if w != nil {
+		metadata["leaderLease"] = w.lease
+		metadata["leader"] = w.holder
+	}
+	return metadata
+}
@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/isurusiri/tipsy/internal/config"
+)
+
+func TestTargetNamespaceFor(t *testing.T) {
+	originalConfig := config.GlobalConfig
+	defer func() {
+		config.GlobalConfig = originalConfig
+	}()
+
+	testCases := []struct {
+		name            string
+		localNamespace  string
+		globalNamespace string
+		leaderLease     string
+		expected        string
+		expectError     bool
+	}{
+		{name: "local flag wins", localNamespace: "apps", globalNamespace: "global", leaderLease: "kube-system/lease", expected: "apps"},
+		{name: "global flag", globalNamespace: "global", leaderLease: "kube-system/lease", expected: "global"},
+		{name: "lease namespace", leaderLease: "kube-system/lease", expected: "kube-system"},
+		{name: "default", expected: "default"},
+		{name: "invalid lease", leaderLease: "lease", expectError: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config.GlobalConfig = config.Config{Namespace: tc.globalNamespace}
+
+			namespace, err := targetNamespaceFor(tc.localNamespace, tc.leaderLease)
+			if tc.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if namespace != tc.expected {
+				t.Errorf("Expected namespace '%s', got '%s'", tc.expected, namespace)
+			}
+		})
+	}
+}
+
+func TestLeaderLeaseFlags(t *testing.T) {
+	for _, name := range []string{"kill", "latency", "packetloss"} {
+		command := getCommand(name)
+		if command == nil {
+			t.Fatalf("%s command not found in root command", name)
+		}
+		if command.Flag("leader-lease") == nil {
+			t.Errorf("%s command missing --leader-lease flag", name)
+		}
+	}
+}
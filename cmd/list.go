@@ -0,0 +1,168 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/isurusiri/tipsy/internal/config"
+	"github.com/isurusiri/tipsy/internal/state"
+	"github.com/isurusiri/tipsy/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+// Output formats for listing actions
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+)
+
+var (
+	listType      string
+	listPod       string
+	listNamespace string
+	listOutput    string
+)
+
+// listCmd represents the list command
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List recorded chaos actions",
+	Long: `List the chaos actions recorded in the configured state store:
+~/.tipsy/state.json, or the tipsy-state ConfigMap with --state-backend configmap.
+
+Each action has an ID that can be passed to 'tipsy rollback --id' to undo
+just that action.
+
+Examples:
+  tipsy list                           # List every recorded action
+  tipsy list --type latency            # List only latency actions
+  tipsy list --namespace production    # List actions in a namespace
+  tipsy list --pod my-pod -o json      # List actions for a pod as JSON`,
+	Run: func(cmd *cobra.Command, args []string) {
+		// Print configuration if verbose mode is enabled
+		PrintConfig()
+
+		if listOutput != outputTable && listOutput != outputJSON {
+			utils.Error(fmt.Sprintf("Invalid output format '%s': must be '%s' or '%s'", listOutput, outputTable, outputJSON))
+			cmd.Help()
+			return
+		}
+
+		// Use global namespace if not specified locally
+		filterNamespace := listNamespace
+		if filterNamespace == "" {
+			filterNamespace = config.GlobalConfig.Namespace
+		}
+
+		actions, err := state.LoadActions()
+		if err != nil {
+			utils.Error(fmt.Sprintf("Failed to load actions from state: %v", err))
+			return
+		}
+
+		actions = filterListedActions(actions, listType, listPod, filterNamespace)
+
+		if listOutput == outputJSON {
+			err = writeActionsJSON(cmd.OutOrStdout(), actions)
+		} else {
+			err = writeActionsTable(cmd.OutOrStdout(), actions, time.Now())
+		}
+		if err != nil {
+			utils.Error(fmt.Sprintf("Failed to print actions: %v", err))
+		}
+	},
+}
+
+// filterListedActions keeps the actions matching every non-empty filter
+func filterListedActions(actions []state.ChaosAction, filterType, filterPod, filterNamespace string) []state.ChaosAction {
+	filtered := []state.ChaosAction{}
+
+	for _, action := range actions {
+		if filterType != "" && action.Type != filterType {
+			continue
+		}
+		if filterPod != "" && action.TargetPod != filterPod {
+			continue
+		}
+		if filterNamespace != "" && action.Namespace != filterNamespace {
+			continue
+		}
+		filtered = append(filtered, action)
+	}
+
+	return filtered
+}
+
+// writeActionsJSON prints actions as a pretty-printed JSON array
+func writeActionsJSON(out io.Writer, actions []state.ChaosAction) error {
+	data, err := json.MarshalIndent(actions, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal actions to JSON: %w", err)
+	}
+
+	_, err = fmt.Fprintln(out, string(data))
+	return err
+}
+
+// writeActionsTable prints actions as an aligned table, one row per action
+func writeActionsTable(out io.Writer, actions []state.ChaosAction, now time.Time) error {
+	if len(actions) == 0 {
+		_, err := fmt.Fprintln(out, "No actions recorded")
+		return err
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "ID\tTYPE\tTARGET\tNAMESPACE\tSTATUS\tAGE\tEXPIRES")
+	for _, action := range actions {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			orDash(action.ID), action.Type, action.TargetPod, orDash(action.Namespace), orDash(action.Status),
+			actionAge(action, now), actionExpiry(action, now))
+	}
+
+	return w.Flush()
+}
+
+// actionAge describes how long ago an action was recorded
+func actionAge(action state.ChaosAction, now time.Time) string {
+	started := action.StartedAt
+	if started.IsZero() {
+		timestamp, err := time.Parse(time.RFC3339, action.Timestamp)
+		if err != nil {
+			return "-"
+		}
+		started = timestamp
+	}
+	return now.Sub(started).Round(time.Second).String()
+}
+
+// actionExpiry describes when an action's duration runs out
+func actionExpiry(action state.ChaosAction, now time.Time) string {
+	if action.ExpiresAt.IsZero() {
+		return "-"
+	}
+	if action.Expired(now) {
+		return "expired"
+	}
+	return "in " + action.ExpiresAt.Sub(now).Round(time.Second).String()
+}
+
+// orDash renders an empty value as "-" so table columns stay aligned
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
+
+func init() {
+	rootCmd.AddCommand(listCmd)
+
+	// Local flags for the list command
+	listCmd.Flags().StringVar(&listType, "type", "", "List only actions of specific type")
+	listCmd.Flags().StringVar(&listPod, "pod", "", "List only actions for specific pod or target")
+	listCmd.Flags().StringVar(&listNamespace, "namespace", "", "List only actions in this namespace (optional, defaults to global namespace or all namespaces)")
+	listCmd.Flags().StringVarP(&listOutput, "output", "o", outputTable, "Output format (table or json)")
+}
@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/isurusiri/tipsy/internal/state"
+)
+
+func testListedActions() []state.ChaosAction {
+	return []state.ChaosAction{
+		{
+			ID:        "aaa111",
+			Type:      "latency",
+			TargetPod: "web-1",
+			Namespace: "default",
+			Timestamp: "2024-01-01T00:00:00Z",
+			ExpiresAt: time.Date(2024, 1, 1, 0, 15, 0, 0, time.UTC),
+			Status:    state.StatusActive,
+		},
+		{ID: "bbb222", Type: "cpustress", TargetPod: "web-2", Namespace: "production", Timestamp: "2024-01-01T00:01:00Z"},
+		{Type: "cordon", TargetPod: "node-1", Timestamp: "2024-01-01T00:02:00Z"},
+	}
+}
+
+func TestListCmdFlags(t *testing.T) {
+	command := getCommand("list")
+	if command == nil {
+		t.Fatal("list command not found in root command")
+	}
+
+	for _, flag := range []string{"type", "pod", "namespace", "output"} {
+		if command.Flag(flag) == nil {
+			t.Errorf("list command missing --%s flag", flag)
+		}
+	}
+
+	if command.Flag("output").Shorthand != "o" {
+		t.Error("Expected -o as shorthand for --output")
+	}
+}
+
+func TestFilterListedActions(t *testing.T) {
+	testCases := []struct {
+		name            string
+		filterType      string
+		filterPod       string
+		filterNamespace string
+		expectedIDs     []string
+	}{
+		{name: "no filters", expectedIDs: []string{"aaa111", "bbb222", ""}},
+		{name: "by type", filterType: "cpustress", expectedIDs: []string{"bbb222"}},
+		{name: "by pod", filterPod: "web-1", expectedIDs: []string{"aaa111"}},
+		{name: "by namespace", filterNamespace: "production", expectedIDs: []string{"bbb222"}},
+		{name: "no matches", filterType: "latency", filterNamespace: "production", expectedIDs: []string{}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			filtered := filterListedActions(testListedActions(), tc.filterType, tc.filterPod, tc.filterNamespace)
+			if len(filtered) != len(tc.expectedIDs) {
+				t.Fatalf("Expected %d actions, got %d", len(tc.expectedIDs), len(filtered))
+			}
+			for i, action := range filtered {
+				if action.ID != tc.expectedIDs[i] {
+					t.Errorf("Expected action %d to have ID '%s', got '%s'", i, tc.expectedIDs[i], action.ID)
+				}
+			}
+		})
+	}
+}
+
+func TestWriteActionsTable(t *testing.T) {
+	var out bytes.Buffer
+	now := time.Date(2024, 1, 1, 0, 10, 0, 0, time.UTC)
+
+	if err := writeActionsTable(&out, testListedActions(), now); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("Expected a header and 3 rows, got:\n%s", out.String())
+	}
+	if fields := strings.Fields(lines[0]); strings.Join(fields, " ") != "ID TYPE TARGET NAMESPACE STATUS AGE EXPIRES" {
+		t.Errorf("Unexpected header: %s", lines[0])
+	}
+	if fields := strings.Fields(lines[1]); strings.Join(fields, " ") != "aaa111 latency web-1 default active 10m0s in 5m0s" {
+		t.Errorf("Unexpected row: %s", lines[1])
+	}
+	// A node action has no namespace, and an action recorded before IDs existed has none either
+	if fields := strings.Fields(lines[3]); strings.Join(fields, " ") != "- cordon node-1 - - 8m0s -" {
+		t.Errorf("Unexpected row: %s", lines[3])
+	}
+
+	out.Reset()
+	if err := writeActionsTable(&out, nil, now); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(out.String(), "No actions recorded") {
+		t.Errorf("Expected a message for an empty list, got: %s", out.String())
+	}
+}
+
+func TestWriteActionsJSON(t *testing.T) {
+	var out bytes.Buffer
+
+	if err := writeActionsJSON(&out, testListedActions()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var actions []state.ChaosAction
+	if err := json.Unmarshal(out.Bytes(), &actions); err != nil {
+		t.Fatalf("Output is not valid JSON: %v", err)
+	}
+	if len(actions) != 3 || actions[0].ID != "aaa111" {
+		t.Errorf("Unexpected actions: %+v", actions)
+	}
+}
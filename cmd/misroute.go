@@ -6,7 +6,6 @@ import (
 
 	"github.com/isurusiri/tipsy/internal/chaos"
 	"github.com/isurusiri/tipsy/internal/config"
-	"github.com/isurusiri/tipsy/internal/k8s"
 	"github.com/isurusiri/tipsy/internal/state"
 	"github.com/isurusiri/tipsy/internal/utils"
 	"github.com/spf13/cobra"
@@ -17,6 +16,12 @@ var (
 	misrouteNamespace         string
 	misrouteRemoveAll         bool
 	misrouteReplaceWithSelector string
+	misrouteMode              string
+	misrouteRemovePercent     int
+	misrouteRemoveCount       int
+	misrouteMixWithSelector   string
+	misrouteWeight            int
+	misrouteDuration          string
 )
 
 // misrouteCmd represents the misroute command
@@ -26,14 +31,31 @@ var misrouteCmd = &cobra.Command{
 	Long: `Misroute traffic by removing or replacing endpoints in a Kubernetes Service.
 
 This command will:
-1. Fetch the target service and its current endpoints
-2. Save the original endpoints for rollback purposes
-3. Either remove all endpoints or replace them with pods matching a selector
-4. Update the service endpoints to simulate misrouting
+1. Fetch the target service, its current endpoints and its EndpointSlices
+2. Save the original endpoints and slices for rollback purposes, in a checksummed
+   backup of their own, so misrouting a service twice keeps the true original
+3. Remove all endpoints, replace them with pods matching a selector, remove
+   some of the ready addresses, or mix pods matching a selector in with them
+4. Detach the service's EndpointSlices and create tipsy-owned ones in their place,
+   since kube-proxy routes by slices, and update the legacy Endpoints to match
+
+A service with a selector is reconciled by the endpoints and EndpointSlice
+controllers, which may undo the misroute within seconds; tipsy warns when it
+finds one. For such a service, --mode selector-swap instead points the
+service's selector at the replacement pods, or with --remove-all at no pods,
+and rollback puts the original selector back.
+
+With --duration, tipsy waits with a countdown and then restores the service
+itself; interrupting or terminating tipsy restores it early.
 
 Examples:
   tipsy misroute --service my-service --remove-all
   tipsy misroute --service my-service --replace-with-selector "app=nginx"
+  tipsy misroute --service my-service --replace-with-selector "app=nginx" --mode selector-swap
+  tipsy misroute --service my-service --remove-percent 50
+  tipsy misroute --service my-service --remove-count 1
+  tipsy misroute --service my-service --mix-with-selector "app=bad" --weight 20
+  tipsy misroute --service my-service --remove-all --duration 2m
   tipsy misroute --service my-service --namespace production --remove-all --dry-run
   tipsy misroute --service my-service --replace-with-selector "tier=backend" --verbose`,
 	Run: func(cmd *cobra.Command, args []string) {
@@ -47,16 +69,18 @@ Examples:
 			return
 		}
 
-		// Validate that either remove-all or replace-with-selector is specified, but not both
-		if !misrouteRemoveAll && misrouteReplaceWithSelector == "" {
-			utils.Error("either --remove-all or --replace-with-selector must be specified")
+		opts, err := misrouteOptions(misrouteRemoveAll, misrouteReplaceWithSelector, misrouteRemovePercent, misrouteRemoveCount,
+			misrouteMixWithSelector, misrouteWeight, misrouteMode)
+		if err != nil {
+			utils.Error(err.Error())
 			cmd.Help()
 			return
 		}
 
-		if misrouteRemoveAll && misrouteReplaceWithSelector != "" {
-			utils.Error("--remove-all and --replace-with-selector cannot be used together")
-			cmd.Help()
+		// Parse duration
+		durationParsed, err := time.ParseDuration(misrouteDuration)
+		if err != nil {
+			utils.Error(fmt.Sprintf("Invalid duration format '%s': %v", misrouteDuration, err))
 			return
 		}
 
@@ -70,22 +94,36 @@ Examples:
 		}
 
 		// Create Kubernetes client
-		client, err := k8s.NewClient(config.GlobalConfig.Kubeconfig)
+		client, err := newClient()
 		if err != nil {
 			utils.Error(fmt.Sprintf("Failed to create Kubernetes client: %v", err))
 			return
 		}
 
-		// Execute the misroute operation
-		backupPath, err := chaos.MisrouteService(client, misrouteService, targetNamespace, misrouteReplaceWithSelector, misrouteRemoveAll, config.GlobalConfig.DryRun)
+		// Catch interrupts before injecting, so one that arrives before the hold starts still rolls back
+		signals, release := catchSignalsForHold(durationParsed)
+		defer release()
+
+		// Execute the misroute operation; the backup of the original endpoints is keyed by the action's ID
+		actionID := state.NewActionID()
+		var backupPath string
+		var swap *chaos.SelectorSwapResult
+		if misrouteMode == chaos.MisrouteModeSelectorSwap {
+			swap, err = chaos.SwapServiceSelector(client, misrouteService, targetNamespace, misrouteReplaceWithSelector, actionID, config.GlobalConfig.DryRun)
+		} else {
+			backupPath, err = chaos.MisrouteService(client, misrouteService, targetNamespace, opts, config.GlobalConfig.DryRun,
+				actionID, state.CurrentCluster())
+		}
 		if err != nil {
 			utils.Error(fmt.Sprintf("Failed to misroute service: %v", err))
 			return
 		}
 
 		// Save state for the misroute operation
+		var actions []state.ChaosAction
 		if !config.GlobalConfig.DryRun {
 			action := state.ChaosAction{
+				ID:        actionID,
 				Type:      "misroute",
 				TargetPod: misrouteService, // Using service name as target
 				Namespace: targetNamespace,
@@ -94,18 +132,109 @@ Examples:
 					"service":                misrouteService,
 					"remove_all":            fmt.Sprintf("%t", misrouteRemoveAll),
 					"replace_with_selector": misrouteReplaceWithSelector,
-					"backupPath":            backupPath,
+					"mode":                  misrouteMode,
+					"duration":              misrouteDuration,
 				},
 			}
+			if opts.RemovePercent > 0 {
+				action.Metadata["remove_percent"] = fmt.Sprintf("%d", opts.RemovePercent)
+			}
+			if opts.RemoveCount > 0 {
+				action.Metadata["remove_count"] = fmt.Sprintf("%d", opts.RemoveCount)
+			}
+			if opts.MixSelector != "" {
+				action.Metadata["mix_with_selector"] = opts.MixSelector
+				action.Metadata["weight"] = fmt.Sprintf("%d", opts.Weight)
+			}
+			if swap != nil {
+				action.Metadata["originalSelector"] = swap.Original
+				action.Metadata["swappedSelector"] = swap.Swapped
+			} else {
+				action.Metadata["backupPath"] = backupPath
+			}
 			if err := state.SaveAction(action); err != nil {
-				utils.Warn(fmt.Sprintf("Failed to save state for service '%s': %v", misrouteService, err))
+				// Without a record 'tipsy rollback' cannot find the fault, so never leave it unannounced
+				if durationParsed <= 0 {
+					utils.Error(fmt.Sprintf("Failed to save state for service '%s': %v; the service is still misrouted and 'tipsy rollback' cannot restore it (%s)",
+						misrouteService, err, misrouteRestoreHint(swap, backupPath)))
+					return
+				}
+				utils.Warn(fmt.Sprintf("Failed to save state for service '%s': %v; it will still be restored when the hold ends, but not by 'tipsy rollback' (%s)",
+					misrouteService, err, misrouteRestoreHint(swap, backupPath)))
 			}
+			actions = append(actions, action)
 		}
 
 		utils.Info("Service misrouting operation completed successfully")
+
+		holdFault(client, signals, actions, durationParsed)
 	},
 }
 
+// misrouteRestoreHint tells the user where the original routing of a misrouted service is kept,
+// for when tipsy could not record the misroute
+func misrouteRestoreHint(swap *chaos.SelectorSwapResult, backupPath string) string {
+	if swap != nil {
+		return fmt.Sprintf("its original selector was '%s'", swap.Original)
+	}
+	return fmt.Sprintf("its original endpoints are backed up in %s", backupPath)
+}
+
+// defaultMixWeight is the weight of pods mixed in without --weight
+const defaultMixWeight = 50
+
+// misrouteOptions validates the flags choosing how to misroute, exactly one of which must be set
+func misrouteOptions(removeAll bool, replaceSelector string, removePercent, removeCount int, mixSelector string, weight int, mode string) (chaos.MisrouteOptions, error) {
+	opts := chaos.MisrouteOptions{
+		RemoveAll:       removeAll,
+		ReplaceSelector: replaceSelector,
+		RemovePercent:   removePercent,
+		RemoveCount:     removeCount,
+		MixSelector:     mixSelector,
+		Weight:          weight,
+	}
+
+	chosen := 0
+	for _, set := range []bool{removeAll, replaceSelector != "", removePercent != 0, removeCount != 0, mixSelector != ""} {
+		if set {
+			chosen++
+		}
+	}
+	if chosen == 0 {
+		return opts, fmt.Errorf("one of --remove-all, --replace-with-selector, --remove-percent, --remove-count or --mix-with-selector must be specified")
+	}
+	if chosen > 1 {
+		return opts, fmt.Errorf("--remove-all, --replace-with-selector, --remove-percent, --remove-count and --mix-with-selector cannot be used together")
+	}
+
+	if removePercent < 0 || removePercent > 100 {
+		return opts, fmt.Errorf("--remove-percent must be between 1 and 100, got %d", removePercent)
+	}
+	if removeCount < 0 {
+		return opts, fmt.Errorf("--remove-count must be positive, got %d", removeCount)
+	}
+
+	if weight != 0 && mixSelector == "" {
+		return opts, fmt.Errorf("--weight requires --mix-with-selector")
+	}
+	if mixSelector != "" {
+		if weight == 0 {
+			opts.Weight = defaultMixWeight
+		} else if weight < 1 || weight > 99 {
+			return opts, fmt.Errorf("--weight must be between 1 and 99, got %d", weight)
+		}
+	}
+
+	if mode != chaos.MisrouteModeEndpoints && mode != chaos.MisrouteModeSelectorSwap {
+		return opts, fmt.Errorf("invalid --mode '%s', must be %s or %s", mode, chaos.MisrouteModeEndpoints, chaos.MisrouteModeSelectorSwap)
+	}
+	if mode == chaos.MisrouteModeSelectorSwap && !removeAll && replaceSelector == "" {
+		return opts, fmt.Errorf("--mode %s works with --remove-all or --replace-with-selector only", chaos.MisrouteModeSelectorSwap)
+	}
+
+	return opts, nil
+}
+
 func init() {
 	rootCmd.AddCommand(misrouteCmd)
 
@@ -114,6 +243,13 @@ func init() {
 	misrouteCmd.Flags().StringVar(&misrouteNamespace, "namespace", "", "Kubernetes namespace to operate in (optional, defaults to global namespace or 'default')")
 	misrouteCmd.Flags().BoolVar(&misrouteRemoveAll, "remove-all", false, "Remove all endpoints from the service")
 	misrouteCmd.Flags().StringVar(&misrouteReplaceWithSelector, "replace-with-selector", "", "Replace endpoints with those from pods matching this selector")
+	misrouteCmd.Flags().StringVar(&misrouteMode, "mode", chaos.MisrouteModeEndpoints, "How to misroute: endpoints rewrites the endpoints, selector-swap patches the service's selector")
+
+	misrouteCmd.Flags().IntVar(&misrouteRemovePercent, "remove-percent", 0, "Remove this percentage (1-100) of the service's ready addresses, rounded up")
+	misrouteCmd.Flags().IntVar(&misrouteRemoveCount, "remove-count", 0, "Remove this many of the service's ready addresses")
+	misrouteCmd.Flags().StringVar(&misrouteMixWithSelector, "mix-with-selector", "", "Add pods matching this selector alongside the service's ready addresses")
+	misrouteCmd.Flags().IntVar(&misrouteWeight, "weight", 0, "Percentage (1-99) of the ready addresses the --mix-with-selector pods make up (default 50)")
+	misrouteCmd.Flags().StringVar(&misrouteDuration, "duration", "0s", "How long to keep the service misrouted before restoring it (e.g., '30s', '5m'); 0 keeps it until 'tipsy rollback'")
 
 	// Mark service as required
 	misrouteCmd.MarkFlagRequired("service")
@@ -1,85 +1,129 @@
 package cmd
 
 import (
+	"strings"
 	"testing"
 
+	"github.com/isurusiri/tipsy/internal/chaos"
 	"github.com/isurusiri/tipsy/internal/config"
 )
 
 func TestMisrouteCmd_FlagValidation(t *testing.T) {
 	tests := []struct {
 		name                    string
-		service                 string
 		removeAll               bool
 		replaceWithSelector     string
+		removePercent           int
+		removeCount             int
+		mixWithSelector         string
+		weight                  int
+		mode                    string
+		expectedWeight          int
 		expectedValidationError string
 	}{
 		{
-			name:                    "valid with remove-all",
-			service:                 "test-service",
-			removeAll:               true,
-			replaceWithSelector:     "",
-			expectedValidationError: "",
+			name:      "valid with remove-all",
+			removeAll: true,
+			mode:      "endpoints",
 		},
 		{
-			name:                    "valid with replace-with-selector",
-			service:                 "test-service",
-			removeAll:               false,
-			replaceWithSelector:     "app=nginx",
-			expectedValidationError: "",
+			name:                "valid with replace-with-selector",
+			replaceWithSelector: "app=nginx",
+			mode:                "endpoints",
+		},
+		{
+			name:          "valid with remove-percent",
+			removePercent: 50,
+			mode:          "endpoints",
+		},
+		{
+			name:        "valid with remove-count",
+			removeCount: 1,
+			mode:        "endpoints",
+		},
+		{
+			name:            "valid with mix-with-selector and weight",
+			mixWithSelector: "app=bad",
+			weight:          20,
+			mode:            "endpoints",
+			expectedWeight:  20,
+		},
+		{
+			name:            "mix-with-selector defaults the weight",
+			mixWithSelector: "app=bad",
+			mode:            "endpoints",
+			expectedWeight:  50,
 		},
 		{
 			name:                    "invalid - both flags set",
-			service:                 "test-service",
 			removeAll:               true,
 			replaceWithSelector:     "app=nginx",
-			expectedValidationError: "--remove-all and --replace-with-selector cannot be used together",
+			mode:                    "endpoints",
+			expectedValidationError: "cannot be used together",
+		},
+		{
+			name:                    "invalid - remove-count with remove-percent",
+			removePercent:           10,
+			removeCount:             1,
+			mode:                    "endpoints",
+			expectedValidationError: "cannot be used together",
 		},
 		{
 			name:                    "invalid - neither flag set",
-			service:                 "test-service",
-			removeAll:               false,
-			replaceWithSelector:     "",
-			expectedValidationError: "either --remove-all or --replace-with-selector must be specified",
+			mode:                    "endpoints",
+			expectedValidationError: "must be specified",
 		},
 		{
-			name:                    "invalid - empty service",
-			service:                 "",
+			name:                    "invalid - remove-percent out of range",
+			removePercent:           150,
+			mode:                    "endpoints",
+			expectedValidationError: "--remove-percent must be between 1 and 100",
+		},
+		{
+			name:                    "invalid - weight out of range",
+			mixWithSelector:         "app=bad",
+			weight:                  100,
+			mode:                    "endpoints",
+			expectedValidationError: "--weight must be between 1 and 99",
+		},
+		{
+			name:                    "invalid - weight without mix-with-selector",
 			removeAll:               true,
-			replaceWithSelector:     "",
-			expectedValidationError: "--service flag is required",
+			weight:                  20,
+			mode:                    "endpoints",
+			expectedValidationError: "--weight requires --mix-with-selector",
+		},
+		{
+			name:                    "invalid - unknown mode",
+			removeAll:               true,
+			mode:                    "dns",
+			expectedValidationError: "invalid --mode",
+		},
+		{
+			name:                    "invalid - selector-swap with remove-count",
+			removeCount:             1,
+			mode:                    "selector-swap",
+			expectedValidationError: "--mode selector-swap works with --remove-all or --replace-with-selector only",
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			// Reset global config
-			config.GlobalConfig = config.Config{
-				DryRun:  true,
-				Verbose: false,
-			}
-
-			// Test the validation logic directly
-			var validationError string
-
-			// Check service flag
-			if tt.service == "" {
-				validationError = "--service flag is required"
-			} else if !tt.removeAll && tt.replaceWithSelector == "" {
-				validationError = "either --remove-all or --replace-with-selector must be specified"
-			} else if tt.removeAll && tt.replaceWithSelector != "" {
-				validationError = "--remove-all and --replace-with-selector cannot be used together"
-			}
+			opts, err := misrouteOptions(tt.removeAll, tt.replaceWithSelector, tt.removePercent, tt.removeCount,
+				tt.mixWithSelector, tt.weight, tt.mode)
 
 			// Check expected validation error
 			if tt.expectedValidationError != "" {
-				if validationError != tt.expectedValidationError {
-					t.Errorf("Expected validation error '%s', got '%s'", tt.expectedValidationError, validationError)
-				}
-			} else {
-				if validationError != "" {
-					t.Errorf("Unexpected validation error: %s", validationError)
+				if err == nil || !strings.Contains(err.Error(), tt.expectedValidationError) {
+					t.Errorf("Expected validation error '%s', got %v", tt.expectedValidationError, err)
 				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected validation error: %v", err)
+			}
+			if opts.Weight != tt.expectedWeight {
+				t.Errorf("Expected weight %d, got %d", tt.expectedWeight, opts.Weight)
 			}
 		})
 	}
@@ -190,3 +234,44 @@ func TestMisrouteCmd_GlobalConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestMisrouteCmd_ModeFlag(t *testing.T) {
+	misrouteCommand := getCommand("misroute")
+	if misrouteCommand == nil {
+		t.Fatal("misroute command not found in root command")
+	}
+
+	mode := misrouteCommand.Flag("mode")
+	if mode == nil {
+		t.Fatal("misroute command missing --mode flag")
+	}
+	if mode.DefValue != "endpoints" {
+		t.Errorf("Expected --mode to default to endpoints, got %s", mode.DefValue)
+	}
+}
+
+func TestMisrouteCmd_DurationFlag(t *testing.T) {
+	misrouteCommand := getCommand("misroute")
+	if misrouteCommand == nil {
+		t.Fatal("misroute command not found in root command")
+	}
+
+	duration := misrouteCommand.Flag("duration")
+	if duration == nil {
+		t.Fatal("misroute command missing --duration flag")
+	}
+	if duration.DefValue != "0s" {
+		t.Errorf("Expected misroute to default to no automatic restore, got %s", duration.DefValue)
+	}
+}
+
+func TestMisrouteRestoreHint(t *testing.T) {
+	swap := &chaos.SelectorSwapResult{Original: "app=web", Swapped: "app=decoy"}
+	if hint := misrouteRestoreHint(swap, ""); !strings.Contains(hint, "app=web") {
+		t.Errorf("Expected the original selector in the hint, got %q", hint)
+	}
+
+	if hint := misrouteRestoreHint(nil, "/tmp/web.json"); !strings.Contains(hint, "/tmp/web.json") {
+		t.Errorf("Expected the backup path in the hint, got %q", hint)
+	}
+}
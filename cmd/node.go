@@ -0,0 +1,202 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/isurusiri/tipsy/internal/chaos"
+	"github.com/isurusiri/tipsy/internal/config"
+	"github.com/isurusiri/tipsy/internal/state"
+	"github.com/isurusiri/tipsy/internal/utils"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+)
+
+var (
+	nodeChaosNode     string
+	nodeChaosSelector string
+	nodeChaosDuration string
+	nodeChaosTaint    string
+)
+
+// nodeCmd represents the node command group
+var nodeCmd = &cobra.Command{
+	Use:   "node",
+	Short: "Disrupt nodes by cordoning, draining or tainting them",
+	Long: `Disrupt nodes by cordoning, draining or tainting them.
+
+Each subcommand records the node's prior Unschedulable flag and taints so that
+'tipsy rollback' restores the node exactly as it was. With --duration the
+change is rolled back automatically once the duration has passed.
+
+Examples:
+  tipsy node cordon --node worker-1 --duration 5m
+  tipsy node drain --node-selector "pool=spot"
+  tipsy node taint --node worker-2 --taint "dedicated=chaos:NoExecute" --duration 2m`,
+}
+
+// nodeCordonCmd represents the node cordon command
+var nodeCordonCmd = &cobra.Command{
+	Use:   "cordon",
+	Short: "Mark nodes as unschedulable",
+	Run: func(cmd *cobra.Command, args []string) {
+		runNodeChaos(cmd, "cordon")
+	},
+}
+
+// nodeDrainCmd represents the node drain command
+var nodeDrainCmd = &cobra.Command{
+	Use:   "drain",
+	Short: "Cordon nodes and evict their pods",
+	Long: `Cordon nodes and evict their pods through the Eviction API.
+
+Pods in system namespaces, DaemonSet and static pods, and pods labelled
+tipsy.io/exclude=true are left in place. Rollback uncordons the node; evicted
+pods are rescheduled by their controllers.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		runNodeChaos(cmd, "drain")
+	},
+}
+
+// nodeTaintCmd represents the node taint command
+var nodeTaintCmd = &cobra.Command{
+	Use:   "taint",
+	Short: "Add a taint to nodes",
+	Run: func(cmd *cobra.Command, args []string) {
+		runNodeChaos(cmd, "taint")
+	},
+}
+
+// runNodeChaos applies a node operation and records the prior node state for rollback
+func runNodeChaos(cmd *cobra.Command, operation string) {
+	// Print configuration if verbose mode is enabled
+	PrintConfig()
+
+	// Validate that exactly one of --node or --node-selector is specified
+	if nodeChaosNode == "" && nodeChaosSelector == "" {
+		utils.Error("either --node or --node-selector must be specified")
+		cmd.Help()
+		return
+	}
+
+	if nodeChaosNode != "" && nodeChaosSelector != "" {
+		utils.Error("--node and --node-selector cannot be used together")
+		cmd.Help()
+		return
+	}
+
+	// Parse duration
+	durationParsed, err := time.ParseDuration(nodeChaosDuration)
+	if err != nil {
+		utils.Error(fmt.Sprintf("Invalid duration format '%s': %v", nodeChaosDuration, err))
+		return
+	}
+
+	var taint corev1.Taint
+	if operation == "taint" {
+		taint, err = chaos.ParseTaint(nodeChaosTaint)
+		if err != nil {
+			utils.Error(err.Error())
+			return
+		}
+	}
+
+	// Create Kubernetes client
+	client, err := newClient()
+	if err != nil {
+		utils.Error(fmt.Sprintf("Failed to create Kubernetes client: %v", err))
+		return
+	}
+
+	nodeNames, err := chaos.ResolveNodes(client, nodeChaosNode, nodeChaosSelector)
+	if err != nil {
+		utils.Error(fmt.Sprintf("Failed to resolve nodes: %v", err))
+		return
+	}
+
+	if len(nodeNames) == 0 {
+		utils.Warn(fmt.Sprintf("No nodes found matching selector '%s'", nodeChaosSelector))
+		return
+	}
+
+	// Catch interrupts before injecting, so one that arrives before the hold starts still rolls back
+	signals, release := catchSignalsForHold(durationParsed)
+	defer release()
+
+	// Execute the node operation
+	var snapshots []chaos.NodeSnapshot
+	switch operation {
+	case "cordon":
+		snapshots, err = chaos.CordonNodes(client, nodeNames, config.GlobalConfig.DryRun)
+	case "drain":
+		snapshots, err = chaos.DrainNodes(client, nodeNames, config.GlobalConfig.DryRun)
+	case "taint":
+		snapshots, err = chaos.TaintNodes(client, nodeNames, taint, config.GlobalConfig.DryRun)
+	}
+	if err != nil {
+		utils.Error(fmt.Sprintf("Failed to %s nodes: %v", operation, err))
+	}
+
+	// Save state for each changed node, even after a partial failure
+	var actions []state.ChaosAction
+	if !config.GlobalConfig.DryRun {
+		timestamp := time.Now().UTC().Format(time.RFC3339)
+		for _, snapshot := range snapshots {
+			metadata := map[string]string{
+				"duration": nodeChaosDuration,
+			}
+			if nodeChaosSelector != "" {
+				metadata["nodeSelector"] = nodeChaosSelector
+			}
+			if operation == "taint" {
+				metadata["taint"] = nodeChaosTaint
+			}
+
+			action := nodeAction(operation, snapshot, timestamp, metadata)
+			if err := state.SaveAction(action); err != nil {
+				utils.Warn(fmt.Sprintf("Failed to save state for node '%s': %v", snapshot.Name, err))
+				continue
+			}
+			actions = append(actions, action)
+		}
+	}
+
+	if err == nil {
+		utils.Info(fmt.Sprintf("Node %s operation completed successfully", operation))
+	}
+
+	// Hold and roll back whatever was changed, even after a partial failure
+	holdFault(client, signals, actions, durationParsed)
+}
+
+// nodeAction builds the state record for a node change, keeping whether the node was
+// already cordoned so rollback only undoes what tipsy changed
+// Taints need no snapshot: rollback removes just the taint recorded in the metadata
+func nodeAction(actionType string, snapshot chaos.NodeSnapshot, timestamp string, metadata map[string]string) state.ChaosAction {
+	metadata["node"] = snapshot.Name
+	metadata["originalUnschedulable"] = fmt.Sprintf("%t", snapshot.Unschedulable)
+
+	return state.ChaosAction{
+		ID:        state.NewActionID(),
+		Type:      actionType,
+		TargetPod: snapshot.Name, // Using node name as target
+		TargetUID: string(snapshot.UID),
+		Timestamp: timestamp,
+		Metadata:  metadata,
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(nodeCmd)
+	nodeCmd.AddCommand(nodeCordonCmd)
+	nodeCmd.AddCommand(nodeDrainCmd)
+	nodeCmd.AddCommand(nodeTaintCmd)
+
+	// Flags shared by every node subcommand
+	nodeCmd.PersistentFlags().StringVar(&nodeChaosNode, "node", "", "Name of the node to disrupt")
+	nodeCmd.PersistentFlags().StringVar(&nodeChaosSelector, "node-selector", "", "Label selector for the nodes to disrupt")
+	nodeCmd.PersistentFlags().StringVar(&nodeChaosDuration, "duration", "0s", "How long to keep the change before rolling it back (e.g., '30s', '5m'); 0 keeps it until 'tipsy rollback'")
+
+	// Local flags for the taint subcommand
+	nodeTaintCmd.Flags().StringVar(&nodeChaosTaint, "taint", chaos.DefaultChaosTaint, "Taint to add, in key=value:Effect form")
+}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/isurusiri/tipsy/internal/chaos"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestNodeCmd(t *testing.T) {
+	// Test that the node command group is registered with its subcommands
+	nodeGroup := getCommand("node")
+	if nodeGroup == nil {
+		t.Fatal("node command not found in root command")
+	}
+
+	for _, name := range []string{"cordon", "drain", "taint"} {
+		found := false
+		for _, sub := range nodeGroup.Commands() {
+			if sub.Name() == name {
+				found = true
+				for _, flag := range []string{"node", "node-selector", "duration"} {
+					if sub.Flag(flag) == nil {
+						t.Errorf("node %s command missing --%s flag", name, flag)
+					}
+				}
+			}
+		}
+		if !found {
+			t.Errorf("node %s command not found", name)
+		}
+	}
+
+	if nodeTaintCmd.Flag("taint") == nil {
+		t.Error("node taint command missing --taint flag")
+	}
+}
+
+func TestNodeAction(t *testing.T) {
+	snapshot := chaos.NodeSnapshot{
+		Name:          "worker-1",
+		Unschedulable: false,
+		Taints:        []corev1.Taint{{Key: "gpu", Value: "true", Effect: corev1.TaintEffectNoSchedule}},
+	}
+
+	action := nodeAction("taint", snapshot, "2023-01-01T00:00:00Z", map[string]string{"duration": "5m"})
+
+	if action.Type != "taint" || action.TargetPod != "worker-1" || action.Namespace != "" {
+		t.Errorf("Unexpected action: %+v", action)
+	}
+	if action.Metadata["originalUnschedulable"] != "false" {
+		t.Errorf("Expected originalUnschedulable 'false', got '%s'", action.Metadata["originalUnschedulable"])
+	}
+	if action.Metadata["duration"] != "5m" {
+		t.Errorf("Expected shared metadata to be kept, got %v", action.Metadata)
+	}
+	if _, exists := action.Metadata["originalTaints"]; exists {
+		t.Error("Expected no taint snapshot; rollback only removes the taint it added")
+	}
+}
+
+// Helper function to get a top-level command by name for testing
+func getCommand(name string) *cobra.Command {
+	for _, cmd := range rootCmd.Commands() {
+		if cmd.Name() == name {
+			return cmd
+		}
+	}
+	return nil
+}
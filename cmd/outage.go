@@ -0,0 +1,251 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/isurusiri/tipsy/internal/chaos"
+	"github.com/isurusiri/tipsy/internal/config"
+	"github.com/isurusiri/tipsy/internal/state"
+	"github.com/isurusiri/tipsy/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+var (
+	zoneOutageZone        string
+	zoneOutageTopologyKey string
+	zoneOutageMode        string
+	zoneOutageDuration    string
+	zoneOutageCordon      bool
+
+	nodeOutageNode     string
+	nodeOutageMode     string
+	nodeOutageDuration string
+	nodeOutageCordon   bool
+)
+
+// zoneOutageCmd represents the zone-outage command
+var zoneOutageCmd = &cobra.Command{
+	Use:   "zone-outage",
+	Short: "Simulate the loss of an availability zone",
+	Long: `Simulate the loss of a whole availability zone.
+
+This command will:
+1. Find every node whose topology label matches the zone
+2. Optionally cordon those nodes so replacements cannot land back in the zone
+3. Find every pod scheduled on those nodes across all namespaces, skipping
+   system namespaces, DaemonSet and static pods, and pods labelled tipsy.io/exclude=true
+4. Kill the pods, or isolate them from the network with 100% packet loss
+5. Uncordon the nodes once the duration has passed, or earlier if tipsy is
+   interrupted
+
+Examples:
+  tipsy zone-outage --zone us-east-1a
+  tipsy zone-outage --zone us-east-1a --mode isolate --duration 2m --cordon
+  tipsy zone-outage --zone rack-3 --topology-key example.com/rack --dry-run`,
+	Run: func(cmd *cobra.Command, args []string) {
+		// Print configuration if verbose mode is enabled
+		PrintConfig()
+
+		// Validate required flags
+		if zoneOutageZone == "" {
+			utils.Error("--zone flag is required")
+			cmd.Help()
+			return
+		}
+
+		opts, err := outageOptions(zoneOutageMode, zoneOutageDuration, zoneOutageCordon)
+		if err != nil {
+			utils.Error(err.Error())
+			cmd.Help()
+			return
+		}
+
+		// Create Kubernetes client
+		client, err := newClient()
+		if err != nil {
+			utils.Error(fmt.Sprintf("Failed to create Kubernetes client: %v", err))
+			return
+		}
+
+		// Catch interrupts before injecting, so one that arrives before the hold starts still rolls back
+		signals, release := catchSignalsForHold(opts.Duration)
+		defer release()
+
+		// Execute the zone outage
+		result, err := chaos.ZoneOutage(client, zoneOutageZone, zoneOutageTopologyKey, opts, config.GlobalConfig.DryRun)
+		if err != nil {
+			utils.Error(fmt.Sprintf("Failed to simulate zone outage: %v", err))
+		}
+
+		// Save state for everything the outage touched, even after a partial failure
+		var cordons []state.ChaosAction
+		if !config.GlobalConfig.DryRun && result != nil {
+			cordons = saveOutageState(result, opts, map[string]string{
+				"outage":      "zone",
+				"zone":        zoneOutageZone,
+				"topologyKey": zoneOutageTopologyKey,
+			})
+		}
+
+		if err == nil {
+			utils.Info("Zone outage operation completed successfully")
+		}
+
+		// Keep the nodes cordoned for as long as the outage lasts
+		holdFault(client, signals, cordons, opts.Duration)
+	},
+}
+
+// nodeOutageCmd represents the node-outage command
+var nodeOutageCmd = &cobra.Command{
+	Use:   "node-outage",
+	Short: "Simulate the loss of a single node",
+	Long: `Simulate the loss of a single node.
+
+This command will:
+1. Optionally cordon the node so replacements are scheduled elsewhere
+2. Find every pod scheduled on the node across all namespaces, skipping
+   system namespaces, DaemonSet and static pods, and pods labelled tipsy.io/exclude=true
+3. Kill the pods, or isolate them from the network with 100% packet loss
+4. Uncordon the node once the duration has passed, or earlier if tipsy is
+   interrupted
+
+Examples:
+  tipsy node-outage --node worker-1
+  tipsy node-outage --node worker-1 --mode isolate --duration 1m --cordon
+  tipsy node-outage --node worker-2 --dry-run --verbose`,
+	Run: func(cmd *cobra.Command, args []string) {
+		// Print configuration if verbose mode is enabled
+		PrintConfig()
+
+		// Validate required flags
+		if nodeOutageNode == "" {
+			utils.Error("--node flag is required")
+			cmd.Help()
+			return
+		}
+
+		opts, err := outageOptions(nodeOutageMode, nodeOutageDuration, nodeOutageCordon)
+		if err != nil {
+			utils.Error(err.Error())
+			cmd.Help()
+			return
+		}
+
+		// Create Kubernetes client
+		client, err := newClient()
+		if err != nil {
+			utils.Error(fmt.Sprintf("Failed to create Kubernetes client: %v", err))
+			return
+		}
+
+		// Catch interrupts before injecting, so one that arrives before the hold starts still rolls back
+		signals, release := catchSignalsForHold(opts.Duration)
+		defer release()
+
+		// Execute the node outage
+		result, err := chaos.NodeOutage(client, []string{nodeOutageNode}, opts, config.GlobalConfig.DryRun)
+		if err != nil {
+			utils.Error(fmt.Sprintf("Failed to simulate node outage: %v", err))
+		}
+
+		// Save state for everything the outage touched, even after a partial failure
+		var cordons []state.ChaosAction
+		if !config.GlobalConfig.DryRun && result != nil {
+			cordons = saveOutageState(result, opts, map[string]string{
+				"outage": "node",
+			})
+		}
+
+		if err == nil {
+			utils.Info("Node outage operation completed successfully")
+		}
+
+		// Keep the node cordoned for as long as the outage lasts
+		holdFault(client, signals, cordons, opts.Duration)
+	},
+}
+
+// outageOptions validates the shared outage flags
+func outageOptions(mode, duration string, cordon bool) (chaos.OutageOptions, error) {
+	if mode != chaos.OutageModeKill && mode != chaos.OutageModeIsolate {
+		return chaos.OutageOptions{}, fmt.Errorf("--mode must be either '%s' or '%s'", chaos.OutageModeKill, chaos.OutageModeIsolate)
+	}
+
+	durationParsed, err := time.ParseDuration(duration)
+	if err != nil {
+		return chaos.OutageOptions{}, fmt.Errorf("invalid duration format '%s': %v", duration, err)
+	}
+
+	return chaos.OutageOptions{Mode: mode, Duration: durationParsed, Cordon: cordon}, nil
+}
+
+// saveOutageState records a rollback-able action for every pod and node an outage touched
+// Returns the saved cordon actions, which the outage holds and then rolls back
+func saveOutageState(result *chaos.OutageResult, opts chaos.OutageOptions, metadata map[string]string) []state.ChaosAction {
+	timestamp := time.Now().UTC().Format(time.RFC3339)
+
+	var cordons []state.ChaosAction
+	for _, snapshot := range result.CordonedNodes {
+		nodeMetadata := outageMetadata(metadata, snapshot.Name)
+		nodeMetadata["duration"] = opts.Duration.String()
+		action := nodeAction("cordon", snapshot, timestamp, nodeMetadata)
+		if err := state.SaveAction(action); err != nil {
+			utils.Warn(fmt.Sprintf("Failed to save state for node '%s': %v", snapshot.Name, err))
+		} else {
+			cordons = append(cordons, action)
+		}
+	}
+
+	for _, pod := range result.Pods {
+		action := withPodIdentity(state.ChaosAction{
+			Type:      "kill",
+			TargetPod: pod.Name,
+			Namespace: pod.Namespace,
+			Timestamp: timestamp,
+			Metadata:  outageMetadata(metadata, pod.Node),
+		}, pod)
+		if opts.Mode == chaos.OutageModeIsolate {
+			action.Type = "packetloss"
+			action.Metadata["loss"] = "100%"
+			action.Metadata["duration"] = opts.Duration.String()
+		}
+		if err := state.SaveAction(action); err != nil {
+			utils.Warn(fmt.Sprintf("Failed to save state for pod '%s': %v", pod.Name, err))
+		}
+	}
+
+	return cordons
+}
+
+// outageMetadata copies the shared outage metadata and adds the node name
+func outageMetadata(shared map[string]string, nodeName string) map[string]string {
+	metadata := map[string]string{"node": nodeName}
+	for key, value := range shared {
+		metadata[key] = value
+	}
+	return metadata
+}
+
+func init() {
+	rootCmd.AddCommand(zoneOutageCmd)
+	rootCmd.AddCommand(nodeOutageCmd)
+
+	// Local flags for the zone-outage command
+	zoneOutageCmd.Flags().StringVar(&zoneOutageZone, "zone", "", "Zone to take down, matched against the topology label (required)")
+	zoneOutageCmd.Flags().StringVar(&zoneOutageTopologyKey, "topology-key", chaos.DefaultTopologyKey, "Node label that holds the zone")
+	zoneOutageCmd.Flags().StringVar(&zoneOutageMode, "mode", chaos.OutageModeKill, "What to do with affected pods: 'kill' or 'isolate'")
+	zoneOutageCmd.Flags().StringVar(&zoneOutageDuration, "duration", "60s", "How long the outage lasts: pods stay isolated in 'isolate' mode and nodes stay cordoned with --cordon (e.g., '30s', '1m', '5m')")
+	zoneOutageCmd.Flags().BoolVar(&zoneOutageCordon, "cordon", false, "Cordon the zone's nodes so replacement pods cannot be scheduled there")
+
+	// Local flags for the node-outage command
+	nodeOutageCmd.Flags().StringVar(&nodeOutageNode, "node", "", "Node to take down (required)")
+	nodeOutageCmd.Flags().StringVar(&nodeOutageMode, "mode", chaos.OutageModeKill, "What to do with affected pods: 'kill' or 'isolate'")
+	nodeOutageCmd.Flags().StringVar(&nodeOutageDuration, "duration", "60s", "How long the outage lasts: pods stay isolated in 'isolate' mode and the node stays cordoned with --cordon (e.g., '30s', '1m', '5m')")
+	nodeOutageCmd.Flags().BoolVar(&nodeOutageCordon, "cordon", false, "Cordon the node so replacement pods cannot be scheduled there")
+
+	// Mark required flags
+	zoneOutageCmd.MarkFlagRequired("zone")
+	nodeOutageCmd.MarkFlagRequired("node")
+}
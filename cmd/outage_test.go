@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/isurusiri/tipsy/internal/chaos"
+	"github.com/isurusiri/tipsy/internal/config"
+	"github.com/isurusiri/tipsy/internal/state"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestOutageCmds(t *testing.T) {
+	// Test that the outage commands are properly registered with their flags
+	expectedFlags := map[string][]string{
+		"zone-outage": {"zone", "topology-key", "mode", "duration", "cordon"},
+		"node-outage": {"node", "mode", "duration", "cordon"},
+	}
+
+	for name, flags := range expectedFlags {
+		var found bool
+		for _, cmd := range rootCmd.Commands() {
+			if cmd.Name() != name {
+				continue
+			}
+			found = true
+			for _, flag := range flags {
+				if cmd.Flag(flag) == nil {
+					t.Errorf("%s command missing --%s flag", name, flag)
+				}
+			}
+		}
+		if !found {
+			t.Errorf("%s command not found in root command", name)
+		}
+	}
+}
+
+func TestOutageOptions(t *testing.T) {
+	tests := []struct {
+		name        string
+		mode        string
+		duration    string
+		expectError bool
+	}{
+		{name: "kill mode", mode: chaos.OutageModeKill, duration: "60s"},
+		{name: "isolate mode", mode: chaos.OutageModeIsolate, duration: "2m"},
+		{name: "invalid mode", mode: "drain", duration: "60s", expectError: true},
+		{name: "invalid duration", mode: chaos.OutageModeIsolate, duration: "soon", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts, err := outageOptions(tt.mode, tt.duration, true)
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if opts.Mode != tt.mode || !opts.Cordon {
+				t.Errorf("Unexpected options: %+v", opts)
+			}
+			expected, _ := time.ParseDuration(tt.duration)
+			if opts.Duration != expected {
+				t.Errorf("Expected duration %s, got %s", expected, opts.Duration)
+			}
+		})
+	}
+}
+
+func TestOutageUncordonsAfterDuration(t *testing.T) {
+	// Disable color for testing
+	originalNoColor := color.NoColor
+	color.NoColor = true
+	defer func() {
+		color.NoColor = originalNoColor
+	}()
+
+	// Reset global config for testing
+	config.GlobalConfig = config.Config{}
+
+	t.Cleanup(state.ReloadStateFilePath)
+	t.Setenv("TIPSY_STATE_FILE", filepath.Join(t.TempDir(), "state.json"))
+	state.ReloadStateFilePath()
+
+	client := fake.NewSimpleClientset(&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "worker-1"}})
+	opts := chaos.OutageOptions{Mode: chaos.OutageModeKill, Duration: 10 * time.Millisecond, Cordon: true}
+	signals, release := catchSignalsForHold(opts.Duration)
+	defer release()
+	result, err := chaos.NodeOutage(client, []string{"worker-1"}, opts, false)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	cordons := saveOutageState(result, opts, map[string]string{"outage": "node"})
+	if len(cordons) != 1 || cordons[0].Type != "cordon" || cordons[0].Metadata["duration"] != "10ms" {
+		t.Fatalf("Expected one cordon action, got %+v", cordons)
+	}
+
+	holdFault(client, signals, cordons, opts.Duration)
+
+	node, _ := client.CoreV1().Nodes().Get(context.TODO(), "worker-1", metav1.GetOptions{})
+	if node.Spec.Unschedulable {
+		t.Error("Expected the node to be uncordoned once the outage is over")
+	}
+	if actions, _ := state.LoadActions(); len(actions) != 0 {
+		t.Errorf("Expected the cordon action to leave state, got %+v", actions)
+	}
+}
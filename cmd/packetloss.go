@@ -6,17 +6,18 @@ import (
 
 	"github.com/isurusiri/tipsy/internal/chaos"
 	"github.com/isurusiri/tipsy/internal/config"
-	"github.com/isurusiri/tipsy/internal/k8s"
 	"github.com/isurusiri/tipsy/internal/state"
 	"github.com/isurusiri/tipsy/internal/utils"
 	"github.com/spf13/cobra"
 )
 
 var (
-	packetLossSelector  string
-	packetLossNamespace string
-	loss                string
-	packetLossDuration  string
+	packetLossSelector    string
+	packetLossNamespace   string
+	loss                  string
+	packetLossDuration    string
+	packetLossLeaderLease string
+	packetLossOrdinals    ordinalFlags
 )
 
 // packetLossCmd represents the packetloss command
@@ -30,28 +31,30 @@ This command will:
 2. Add ephemeral containers to inject network packet loss using tc netem
 3. The packet loss will be applied for the specified duration
 
+With --leader-lease, only the pod holding the Lease is targeted and tipsy
+reports how long it takes for a new holder to take over.
+
 Examples:
   tipsy packetloss --selector "app=nginx" --loss "30%" --duration "30s"
   tipsy packetloss --selector "environment=staging" --namespace production --loss "50%" --duration "1m"
-  tipsy packetloss --selector "tier=frontend" --dry-run --verbose`,
+  tipsy packetloss --selector "tier=frontend" --dry-run --verbose
+  tipsy packetloss --leader-lease kube-system/my-controller --loss "100%" --duration "1m"`,
 	Run: func(cmd *cobra.Command, args []string) {
 		// Print configuration if verbose mode is enabled
 		PrintConfig()
 
 		// Validate required flags
-		if packetLossSelector == "" {
-			utils.Error("--selector flag is required")
+		if packetLossSelector == "" && packetLossLeaderLease == "" {
+			utils.Error("either --selector or --leader-lease must be specified")
 			cmd.Help()
 			return
 		}
 
 		// Use global namespace if not specified locally
-		targetNamespace := packetLossNamespace
-		if targetNamespace == "" {
-			targetNamespace = config.GlobalConfig.Namespace
-		}
-		if targetNamespace == "" {
-			targetNamespace = "default"
+		targetNamespace, err := targetNamespaceFor(packetLossNamespace, packetLossLeaderLease)
+		if err != nil {
+			utils.Error(err.Error())
+			return
 		}
 
 		// Parse duration
@@ -62,14 +65,22 @@ Examples:
 		}
 
 		// Create Kubernetes client
-		client, err := k8s.NewClient(config.GlobalConfig.Kubeconfig)
+		client, err := newClient()
 		if err != nil {
 			utils.Error(fmt.Sprintf("Failed to create Kubernetes client: %v", err))
 			return
 		}
 
+		// Record the current leader so the failover can be timed
+		watch, err := startLeaderWatch(client, packetLossLeaderLease)
+		if err != nil {
+			utils.Error(fmt.Sprintf("Failed to read leader lease: %v", err))
+			return
+		}
+
 		// Execute the packet loss injection
-		affectedPods, err := chaos.InjectPacketLoss(client, targetNamespace, packetLossSelector, loss, durationParsed, config.GlobalConfig.DryRun)
+		target := packetLossOrdinals.apply(chaos.PodTarget{Namespace: targetNamespace, Selector: packetLossSelector, LeaderLease: packetLossLeaderLease})
+		affectedPods, err := chaos.InjectPacketLossForTarget(client, target, loss, durationParsed, config.GlobalConfig.DryRun)
 		if err != nil {
 			utils.Error(fmt.Sprintf("Failed to inject packet loss: %v", err))
 			return
@@ -77,24 +88,29 @@ Examples:
 
 		// Save state for each affected pod
 		if !config.GlobalConfig.DryRun {
-			for _, podName := range affectedPods {
-				action := state.ChaosAction{
+			for _, pod := range affectedPods {
+				action := withPodIdentity(state.ChaosAction{
 					Type:      "packetloss",
-					TargetPod: podName,
+					TargetPod: pod.Name,
 					Namespace: targetNamespace,
 					Timestamp: time.Now().UTC().Format(time.RFC3339),
-					Metadata: map[string]string{
+					Metadata: watch.leaderMetadata(map[string]string{
 						"loss":     loss,
 						"duration": packetLossDuration,
 						"selector": packetLossSelector,
-					},
-				}
+					}),
+				}, pod)
 				if err := state.SaveAction(action); err != nil {
-					utils.Warn(fmt.Sprintf("Failed to save state for pod '%s': %v", podName, err))
+					utils.Warn(fmt.Sprintf("Failed to save state for pod '%s': %v", pod.Name, err))
 				}
 			}
 		}
 
+		// An isolated or slowed leader may lose its Lease while the fault is active
+		if len(affectedPods) > 0 {
+			watch.wait(client, durationParsed)
+		}
+
 		utils.Info("Packet loss injection operation completed successfully")
 	},
 }
@@ -103,11 +119,10 @@ func init() {
 	rootCmd.AddCommand(packetLossCmd)
 
 	// Local flags for the packetloss command
-	packetLossCmd.Flags().StringVar(&packetLossSelector, "selector", "", "Kubernetes label selector (required)")
+	packetLossCmd.Flags().StringVar(&packetLossSelector, "selector", "", "Kubernetes label selector (required unless --leader-lease is set)")
 	packetLossCmd.Flags().StringVar(&packetLossNamespace, "namespace", "", "Kubernetes namespace to operate in (optional, defaults to global namespace or 'default')")
 	packetLossCmd.Flags().StringVar(&loss, "loss", "30%", "Network packet loss percentage to inject (e.g., '30%', '50%', '10%')")
 	packetLossCmd.Flags().StringVar(&packetLossDuration, "duration", "30s", "How long to keep packet loss active (e.g., '30s', '1m', '5m')")
-
-	// Mark selector as required
-	packetLossCmd.MarkFlagRequired("selector")
+	packetLossCmd.Flags().StringVar(&packetLossLeaderLease, "leader-lease", "", "Target only the pod holding this coordination Lease, as namespace/name")
+	packetLossOrdinals.register(packetLossCmd)
 }
@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/isurusiri/tipsy/internal/chaos"
+	"github.com/isurusiri/tipsy/internal/config"
+	"github.com/isurusiri/tipsy/internal/state"
+	"github.com/isurusiri/tipsy/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+var (
+	quotaNamespace string
+	quotaPods      int64
+	quotaCPU       string
+	quotaMemory    string
+	quotaDuration  string
+)
+
+// quotaSqueezeCmd represents the quota-squeeze command
+var quotaSqueezeCmd = &cobra.Command{
+	Use:   "quota-squeeze",
+	Short: "Exhaust namespace capacity with a ResourceQuota",
+	Long: `Exhaust namespace capacity by creating a tipsy-labelled ResourceQuota.
+
+This command will:
+1. Create a ResourceQuota in the namespace that blocks new pods, or caps the
+   total CPU and memory that pods may request
+2. Keep the quota in place for the given duration so rollouts and autoscalers
+   run into it
+3. Delete the quota once the duration has passed
+
+Pods that are already running are not affected. Without --cpu or --memory the
+quota blocks new pods entirely.
+
+Examples:
+  tipsy quota-squeeze --namespace checkout
+  tipsy quota-squeeze --namespace checkout --cpu 500m --memory 512Mi --duration 5m
+  tipsy quota-squeeze --namespace checkout --pods 3 --dry-run`,
+	Run: func(cmd *cobra.Command, args []string) {
+		// Print configuration if verbose mode is enabled
+		PrintConfig()
+
+		// Use global namespace if not specified locally
+		targetNamespace := quotaNamespace
+		if targetNamespace == "" {
+			targetNamespace = config.GlobalConfig.Namespace
+		}
+		if targetNamespace == "" {
+			targetNamespace = "default"
+		}
+
+		// Block new pods unless only CPU or memory limits were asked for
+		limits := chaos.QuotaLimits{CPU: quotaCPU, Memory: quotaMemory}
+		if cmd.Flags().Changed("pods") || (quotaCPU == "" && quotaMemory == "") {
+			limits.Pods = &quotaPods
+		}
+
+		// Parse duration
+		durationParsed, err := time.ParseDuration(quotaDuration)
+		if err != nil {
+			utils.Error(fmt.Sprintf("Invalid duration format '%s': %v", quotaDuration, err))
+			return
+		}
+
+		// Create Kubernetes client
+		client, err := newClient()
+		if err != nil {
+			utils.Error(fmt.Sprintf("Failed to create Kubernetes client: %v", err))
+			return
+		}
+
+		// Catch interrupts before injecting, so one that arrives before the hold starts still rolls back
+		signals, release := catchSignalsForHold(durationParsed)
+		defer release()
+
+		// Execute the quota squeeze
+		quotaName, err := chaos.QuotaSqueeze(client, targetNamespace, limits, config.GlobalConfig.DryRun)
+		if err != nil {
+			utils.Error(fmt.Sprintf("Failed to squeeze quota: %v", err))
+			return
+		}
+
+		// Save state for the quota squeeze
+		var actions []state.ChaosAction
+		if !config.GlobalConfig.DryRun {
+			action := state.ChaosAction{
+				ID:        state.NewActionID(),
+				Type:      "quota-squeeze",
+				TargetPod: quotaName, // Using quota name as target
+				Namespace: targetNamespace,
+				Timestamp: time.Now().UTC().Format(time.RFC3339),
+				Metadata: map[string]string{
+					"cpu":      quotaCPU,
+					"memory":   quotaMemory,
+					"duration": quotaDuration,
+				},
+			}
+			if limits.Pods != nil {
+				action.Metadata["pods"] = fmt.Sprintf("%d", *limits.Pods)
+			}
+			if err := state.SaveAction(action); err != nil {
+				utils.Warn(fmt.Sprintf("Failed to save state for quota '%s': %v", quotaName, err))
+			} else {
+				actions = append(actions, action)
+			}
+		}
+
+		utils.Info("Quota squeeze operation completed successfully")
+
+		holdFault(client, signals, actions, durationParsed)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(quotaSqueezeCmd)
+
+	// Local flags for the quota-squeeze command
+	quotaSqueezeCmd.Flags().StringVar(&quotaNamespace, "namespace", "", "Kubernetes namespace to operate in (optional, defaults to global namespace or 'default')")
+	quotaSqueezeCmd.Flags().Int64Var(&quotaPods, "pods", 0, "Maximum number of pods in the namespace; 0 blocks new pods")
+	quotaSqueezeCmd.Flags().StringVar(&quotaCPU, "cpu", "", "Maximum total CPU requests in the namespace (e.g., '500m', '2')")
+	quotaSqueezeCmd.Flags().StringVar(&quotaMemory, "memory", "", "Maximum total memory requests in the namespace (e.g., '512Mi', '2Gi')")
+	quotaSqueezeCmd.Flags().StringVar(&quotaDuration, "duration", "60s", "How long to keep the quota in place (e.g., '30s', '5m'); 0 keeps it until 'tipsy rollback'")
+}
@@ -0,0 +1,23 @@
+package cmd
+
+import (
+	"testing"
+)
+
+func TestQuotaSqueezeCmd(t *testing.T) {
+	// Test that the quota-squeeze command is registered with the expected flags
+	quotaCommand := getCommand("quota-squeeze")
+	if quotaCommand == nil {
+		t.Fatal("quota-squeeze command not found in root command")
+	}
+
+	for _, flag := range []string{"namespace", "pods", "cpu", "memory", "duration"} {
+		if quotaCommand.Flag(flag) == nil {
+			t.Errorf("quota-squeeze command missing --%s flag", flag)
+		}
+	}
+
+	if quotaCommand.Flag("pods").DefValue != "0" {
+		t.Errorf("Expected --pods to default to blocking new pods, got %s", quotaCommand.Flag("pods").DefValue)
+	}
+}
@@ -0,0 +1,147 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/isurusiri/tipsy/internal/chaos"
+	"github.com/isurusiri/tipsy/internal/config"
+	"github.com/isurusiri/tipsy/internal/rollback"
+	"github.com/isurusiri/tipsy/internal/state"
+	"github.com/isurusiri/tipsy/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+var (
+	readinessSelector  string
+	readinessNamespace string
+	readinessPeriod    string
+	readinessDuration  string
+	readinessOrdinals  ordinalFlags
+)
+
+// readinessFlapCmd represents the readiness-flap command
+var readinessFlapCmd = &cobra.Command{
+	Use:   "readiness-flap",
+	Short: "Flap pod readiness using a tipsy-owned readiness gate",
+	Long: `Flap pods between ready and not-ready so the endpoints controller keeps
+removing and re-adding them.
+
+Only pods that declare a readiness gate for the tipsy condition are affected:
+
+  spec:
+    readinessGates:
+      - conditionType: "tipsy.io/ready"
+
+This command will:
+1. List running pods matching the provided label selector that declare the readiness gate,
+   narrowed to the given StatefulSet ordinals if any
+2. Toggle the tipsy.io/ready pod condition between False and True every period
+3. Set the condition back to True once the duration has passed, or earlier if
+   tipsy is interrupted
+
+Examples:
+  tipsy readiness-flap --selector "app=api" --period 10s --duration 2m
+  tipsy readiness-flap --selector "app=api" --namespace production --period 5s --duration 1m
+  tipsy readiness-flap --selector "app=db" --ordinal 0 --period 5s --duration 1m
+  tipsy readiness-flap --selector "tier=frontend" --dry-run`,
+	Run: func(cmd *cobra.Command, args []string) {
+		// Print configuration if verbose mode is enabled
+		PrintConfig()
+
+		// Validate required flags
+		if readinessSelector == "" {
+			utils.Error("--selector flag is required")
+			cmd.Help()
+			return
+		}
+
+		// Use global namespace if not specified locally
+		targetNamespace := readinessNamespace
+		if targetNamespace == "" {
+			targetNamespace = config.GlobalConfig.Namespace
+		}
+		if targetNamespace == "" {
+			targetNamespace = "default"
+		}
+
+		// Parse period and duration
+		periodParsed, err := time.ParseDuration(readinessPeriod)
+		if err != nil {
+			utils.Error(fmt.Sprintf("Invalid period format '%s': %v", readinessPeriod, err))
+			return
+		}
+
+		durationParsed, err := time.ParseDuration(readinessDuration)
+		if err != nil {
+			utils.Error(fmt.Sprintf("Invalid duration format '%s': %v", readinessDuration, err))
+			return
+		}
+
+		// Create Kubernetes client
+		client, err := newClient()
+		if err != nil {
+			utils.Error(fmt.Sprintf("Failed to create Kubernetes client: %v", err))
+			return
+		}
+
+		target := readinessOrdinals.apply(chaos.PodTarget{Namespace: targetNamespace, Selector: readinessSelector})
+		pods, err := chaos.FindFlappablePods(client, target, config.GlobalConfig.DryRun)
+		if err != nil {
+			utils.Error(fmt.Sprintf("Failed to find pods to flap: %v", err))
+			return
+		}
+
+		// Save state before flapping so an interrupted run can still be rolled back
+		var actions []state.ChaosAction
+		if !config.GlobalConfig.DryRun {
+			for _, pod := range pods {
+				action := withPodIdentity(state.ChaosAction{
+					ID:        state.NewActionID(),
+					Type:      "readiness-flap",
+					TargetPod: pod.Name,
+					Namespace: targetNamespace,
+					Timestamp: time.Now().UTC().Format(time.RFC3339),
+					Metadata: map[string]string{
+						"period":   readinessPeriod,
+						"duration": readinessDuration,
+						"selector": readinessSelector,
+					},
+				}, pod)
+				if err := state.SaveAction(action); err != nil {
+					utils.Warn(fmt.Sprintf("Failed to save state for pod '%s': %v", pod.Name, err))
+				} else {
+					actions = append(actions, action)
+				}
+			}
+		}
+
+		// Catch interrupts until readiness is restored, so none can leave pods not-ready
+		stop, release := catchHoldSignals()
+		defer release()
+
+		err = chaos.FlapReadiness(client, targetNamespace, chaos.PodNames(pods), periodParsed, durationParsed, stop, config.GlobalConfig.DryRun)
+		if err != nil {
+			utils.Error(fmt.Sprintf("Failed to flap readiness: %v", err))
+			return
+		}
+
+		// Leave every pod ready once flapping is over
+		if _, failedActions := rollback.RollbackActions(client, actions, false, false); len(failedActions) > 0 {
+			utils.Warn(fmt.Sprintf("Failed to restore readiness of %d pod(s), run 'tipsy rollback' to retry", len(failedActions)))
+		}
+
+		utils.Info("Readiness flap operation completed successfully")
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(readinessFlapCmd)
+
+	// Local flags for the readiness-flap command
+	readinessFlapCmd.Flags().StringVar(&readinessSelector, "selector", "", "Kubernetes label selector (required)")
+	readinessFlapCmd.Flags().StringVar(&readinessNamespace, "namespace", "", "Kubernetes namespace to operate in (optional, defaults to global namespace or 'default')")
+	readinessFlapCmd.Flags().StringVar(&readinessPeriod, "period", "10s", "How often to toggle readiness (e.g., '5s', '10s')")
+	readinessFlapCmd.Flags().StringVar(&readinessDuration, "duration", "2m", "How long to keep flapping (e.g., '30s', '2m')")
+	readinessOrdinals.register(readinessFlapCmd)
+}
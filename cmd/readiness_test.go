@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"testing"
+)
+
+func TestReadinessFlapCmd(t *testing.T) {
+	// Test that the readiness-flap command is registered with the expected flags
+	readinessCommand := getCommand("readiness-flap")
+	if readinessCommand == nil {
+		t.Fatal("readiness-flap command not found in root command")
+	}
+
+	expectedDefaults := map[string]string{
+		"selector":       "",
+		"namespace":      "",
+		"period":         "10s",
+		"duration":       "2m",
+		"ordinal":        "[]",
+		"except-ordinal": "[]",
+	}
+
+	for flag, defValue := range expectedDefaults {
+		f := readinessCommand.Flag(flag)
+		if f == nil {
+			t.Errorf("readiness-flap command missing --%s flag", flag)
+			continue
+		}
+		if f.DefValue != defValue {
+			t.Errorf("Expected --%s to default to '%s', got '%s'", flag, defValue, f.DefValue)
+		}
+	}
+}
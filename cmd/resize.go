@@ -0,0 +1,147 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/isurusiri/tipsy/internal/chaos"
+	"github.com/isurusiri/tipsy/internal/config"
+	"github.com/isurusiri/tipsy/internal/state"
+	"github.com/isurusiri/tipsy/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+var (
+	resizeSelector  string
+	resizeNamespace string
+	resizeCPU       string
+	resizeMemory    string
+	resizeDuration  string
+	resizeOrdinals  ordinalFlags
+)
+
+// resizeSqueezeCmd represents the resize-squeeze command
+var resizeSqueezeCmd = &cobra.Command{
+	Use:   "resize-squeeze",
+	Short: "Shrink container limits in place using the pod resize subresource",
+	Long: `Shrink the CPU and memory limits of running containers in place, without restarts.
+
+This command will:
+1. List running pods matching the provided label selector, narrowed to the
+   given StatefulSet ordinals if any
+2. Record each container's original resources
+3. Lower the containers' limits through the pod resize subresource, lowering
+   requests too where they would exceed the new limits; limits that are missing
+   or already at or below the target are left alone
+4. Resize the containers back to their original resources once the duration has passed
+
+Requires a cluster with in-place pod vertical scaling enabled. Containers whose
+resize policy is RestartContainer will be restarted by the kubelet.
+
+Examples:
+  tipsy resize-squeeze --selector "app=api" --cpu 100m --duration 2m
+  tipsy resize-squeeze --selector "app=api" --cpu 100m --memory 128Mi --duration 5m
+  tipsy resize-squeeze --selector "app=db" --except-ordinal 0 --cpu 100m --duration 2m
+  tipsy resize-squeeze --selector "tier=backend" --memory 64Mi --dry-run`,
+	Run: func(cmd *cobra.Command, args []string) {
+		// Print configuration if verbose mode is enabled
+		PrintConfig()
+
+		// Validate required flags
+		if resizeSelector == "" {
+			utils.Error("--selector flag is required")
+			cmd.Help()
+			return
+		}
+
+		if resizeCPU == "" && resizeMemory == "" {
+			utils.Error("at least one of --cpu or --memory must be specified")
+			cmd.Help()
+			return
+		}
+
+		// Use global namespace if not specified locally
+		targetNamespace := resizeNamespace
+		if targetNamespace == "" {
+			targetNamespace = config.GlobalConfig.Namespace
+		}
+		if targetNamespace == "" {
+			targetNamespace = "default"
+		}
+
+		// Parse duration
+		durationParsed, err := time.ParseDuration(resizeDuration)
+		if err != nil {
+			utils.Error(fmt.Sprintf("Invalid duration format '%s': %v", resizeDuration, err))
+			return
+		}
+
+		// Create Kubernetes client
+		client, err := newClient()
+		if err != nil {
+			utils.Error(fmt.Sprintf("Failed to create Kubernetes client: %v", err))
+			return
+		}
+
+		// Catch interrupts before injecting, so one that arrives before the hold starts still rolls back
+		signals, release := catchSignalsForHold(durationParsed)
+		defer release()
+
+		// Execute the resize squeeze
+		target := resizeOrdinals.apply(chaos.PodTarget{Namespace: targetNamespace, Selector: resizeSelector})
+		results, err := chaos.ResizeSqueeze(client, target, resizeCPU, resizeMemory, config.GlobalConfig.DryRun)
+		if err != nil {
+			utils.Error(fmt.Sprintf("Failed to squeeze pod resources: %v", err))
+			return
+		}
+
+		// Save state for each resized pod
+		var actions []state.ChaosAction
+		if !config.GlobalConfig.DryRun {
+			for _, result := range results {
+				original, err := json.Marshal(result.Original)
+				if err != nil {
+					utils.Warn(fmt.Sprintf("Failed to encode original resources for pod '%s': %v", result.Pod.Name, err))
+					continue
+				}
+
+				action := withPodIdentity(state.ChaosAction{
+					ID:        state.NewActionID(),
+					Type:      "resize-squeeze",
+					TargetPod: result.Pod.Name,
+					Namespace: targetNamespace,
+					Timestamp: time.Now().UTC().Format(time.RFC3339),
+					Metadata: map[string]string{
+						"cpu":               resizeCPU,
+						"memory":            resizeMemory,
+						"duration":          resizeDuration,
+						"selector":          resizeSelector,
+						"originalResources": string(original),
+					},
+				}, result.Pod)
+				if err := state.SaveAction(action); err != nil {
+					utils.Warn(fmt.Sprintf("Failed to save state for pod '%s': %v", result.Pod.Name, err))
+				} else {
+					actions = append(actions, action)
+				}
+			}
+		}
+
+		utils.Info("Resize squeeze operation completed successfully")
+
+		holdFault(client, signals, actions, durationParsed)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(resizeSqueezeCmd)
+
+	// Local flags for the resize-squeeze command
+	resizeSqueezeCmd.Flags().StringVar(&resizeSelector, "selector", "", "Kubernetes label selector (required)")
+	resizeSqueezeCmd.Flags().StringVar(&resizeNamespace, "namespace", "", "Kubernetes namespace to operate in (optional, defaults to global namespace or 'default')")
+	resizeSqueezeCmd.Flags().StringVar(&resizeCPU, "cpu", "", "CPU limit to squeeze each container to (e.g., '100m')")
+	resizeSqueezeCmd.Flags().StringVar(&resizeMemory, "memory", "", "Memory limit to squeeze each container to (e.g., '128Mi')")
+	resizeSqueezeCmd.Flags().StringVar(&resizeDuration, "duration", "60s", "How long to keep the reduced limits (e.g., '30s', '5m'); 0 keeps them until 'tipsy rollback'")
+	resizeOrdinals.register(resizeSqueezeCmd)
+}
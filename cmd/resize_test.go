@@ -0,0 +1,19 @@
+package cmd
+
+import (
+	"testing"
+)
+
+func TestResizeSqueezeCmd(t *testing.T) {
+	// Test that the resize-squeeze command is registered with the expected flags
+	resizeCommand := getCommand("resize-squeeze")
+	if resizeCommand == nil {
+		t.Fatal("resize-squeeze command not found in root command")
+	}
+
+	for _, flag := range []string{"selector", "namespace", "cpu", "memory", "duration", "ordinal", "except-ordinal"} {
+		if resizeCommand.Flag(flag) == nil {
+			t.Errorf("resize-squeeze command missing --%s flag", flag)
+		}
+	}
+}
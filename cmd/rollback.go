@@ -4,7 +4,6 @@ import (
 	"fmt"
 
 	"github.com/isurusiri/tipsy/internal/config"
-	"github.com/isurusiri/tipsy/internal/k8s"
 	"github.com/isurusiri/tipsy/internal/rollback"
 	"github.com/isurusiri/tipsy/internal/utils"
 	"github.com/spf13/cobra"
@@ -14,6 +13,10 @@ var (
 	rollbackDryRun bool
 	rollbackType   string
 	rollbackPod    string
+	rollbackID     string
+	rollbackForce  bool
+
+	rollbackAllClusters bool
 )
 
 // rollbackCmd represents the rollback command
@@ -23,24 +26,49 @@ var rollbackCmd = &cobra.Command{
 	Long: `Rollback chaos actions that were previously applied.
 
 This command will:
-1. Read all actions from ~/.tipsy/state.json
+1. Read all actions from the configured state store: ~/.tipsy/state.json, or the
+   tipsy-state ConfigMap with --state-backend configmap
 2. Revert each action based on its type:
    - latency/packetloss: Remove ephemeral containers and clean up tc netem
    - cpustress: Remove ephemeral containers
-   - misroute: Restore original service endpoints from backup
-3. Remove successfully rolled back actions from state.json
+   - misroute: Restore original service endpoints and slices from backup once the last
+     misroute of the service is rolled back, or the original selector of a
+     selector-swapped service, unless they have changed since (--force restores
+     them anyway)
+   - cordon/drain: Uncordon the node if tipsy cordoned it
+   - taint: Remove the taint tipsy added, leaving other taints in place
+   - scale: Restore the original replica count and any pinned HPA bounds
+   - config-fault: Restore the original ConfigMap or Secret from backup, unless it has
+     changed or been recreated since (--force restores it anyway)
+   - quota-squeeze: Delete the tipsy-created ResourceQuota
+   - resize-squeeze: Resize the pod's containers back to their original resources
+   - readiness-flap: Leave the pod's tipsy readiness condition True
+   Only actions recorded against the cluster tipsy is connected to are rolled back,
+   unless --all-clusters is given
+3. Remove successfully rolled back actions from the state store; failed ones stay,
+   marked rollback-failed with a record of each attempt; every attempt is also
+   kept in the history shown by 'tipsy history'
 
 Examples:
   tipsy rollback                    # Rollback all actions
   tipsy rollback --type latency     # Rollback only latency actions
   tipsy rollback --pod my-pod       # Rollback actions for specific pod
+  tipsy rollback --id 3f9a1c2b7d4e  # Rollback a single action, as shown by 'tipsy list'
+  tipsy rollback --all-clusters     # Rollback actions recorded against any cluster
+  tipsy rollback --force            # Restore misrouted services and config even if they changed since
   tipsy rollback --dry-run          # Show what would be rolled back without executing`,
 	Run: func(cmd *cobra.Command, args []string) {
 		// Print configuration if verbose mode is enabled
 		PrintConfig()
 
+		if rollbackID != "" && (rollbackType != "" || rollbackPod != "") {
+			utils.Error("--id cannot be combined with --type or --pod")
+			cmd.Help()
+			return
+		}
+
 		// Create Kubernetes client
-		client, err := k8s.NewClient(config.GlobalConfig.Kubeconfig)
+		client, err := newClient()
 		if err != nil {
 			utils.Error(fmt.Sprintf("Failed to create Kubernetes client: %v", err))
 			return
@@ -53,7 +81,11 @@ Examples:
 		}
 
 		// Execute rollback
-		err = rollback.RollbackAll(client, dryRun, rollbackType, rollbackPod)
+		if rollbackID != "" {
+			err = rollback.RollbackByID(client, rollbackID, dryRun, rollbackForce, clusterScope(rollbackAllClusters))
+		} else {
+			err = rollback.RollbackAll(client, dryRun, rollbackForce, rollbackType, rollbackPod, clusterScope(rollbackAllClusters))
+		}
 		if err != nil {
 			utils.Error(fmt.Sprintf("Failed to rollback actions: %v", err))
 			return
@@ -68,6 +100,9 @@ func init() {
 
 	// Local flags for the rollback command
 	rollbackCmd.Flags().BoolVar(&rollbackDryRun, "dry-run", false, "Show what would be rolled back without executing")
-	rollbackCmd.Flags().StringVar(&rollbackType, "type", "", "Rollback only actions of specific type (latency, packetloss, cpustress, misroute)")
+	rollbackCmd.Flags().StringVar(&rollbackType, "type", "", "Rollback only actions of specific type (latency, packetloss, cpustress, misroute, cordon, drain, taint, scale, config-fault, quota-squeeze, resize-squeeze, readiness-flap)")
 	rollbackCmd.Flags().StringVar(&rollbackPod, "pod", "", "Rollback only actions for specific pod")
+	rollbackCmd.Flags().StringVar(&rollbackID, "id", "", "Rollback only the action with this ID (see 'tipsy list')")
+	rollbackCmd.Flags().BoolVar(&rollbackForce, "force", false, "Restore misrouted endpoints and selectors, and faulted ConfigMaps and Secrets, even if they have changed since the fault")
+	rollbackCmd.Flags().BoolVar(&rollbackAllClusters, "all-clusters", false, "Rollback actions recorded against any cluster, not just the current one")
 }
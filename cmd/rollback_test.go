@@ -43,6 +43,16 @@ func TestRollbackCmdFlags(t *testing.T) {
 	if rollbackCmd.Flag("pod") == nil {
 		t.Error("rollback command missing --pod flag")
 	}
+
+	// Test id flag
+	if rollbackCmd.Flag("id") == nil {
+		t.Error("rollback command missing --id flag")
+	}
+
+	// Test all-clusters flag
+	if rollbackCmd.Flag("all-clusters") == nil {
+		t.Error("rollback command missing --all-clusters flag")
+	}
 }
 
 func TestRollbackCmdWithEmptyState(t *testing.T) {
@@ -5,6 +5,8 @@ import (
 	"os"
 
 	"github.com/isurusiri/tipsy/internal/config"
+	"github.com/isurusiri/tipsy/internal/k8s"
+	"github.com/isurusiri/tipsy/internal/state"
 	"github.com/spf13/cobra"
 )
 
@@ -18,6 +20,9 @@ It lets you inject real-world failures—like pod kills, network latency, and CP
 using ephemeral containers and native Kubernetes APIs. No sidecars. No CRDs.
 
 Safe, scriptable, and built for CI/CD pipelines and production-grade testing.`,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		return configureStateStore()
+	},
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
@@ -35,6 +40,8 @@ func init() {
 	rootCmd.PersistentFlags().StringVar(&config.GlobalConfig.Namespace, "namespace", "", "Kubernetes namespace to target")
 	rootCmd.PersistentFlags().BoolVar(&config.GlobalConfig.DryRun, "dry-run", false, "if true, simulate actions without taking effect")
 	rootCmd.PersistentFlags().BoolVar(&config.GlobalConfig.Verbose, "verbose", false, "enable verbose logging")
+	rootCmd.PersistentFlags().StringVar(&config.GlobalConfig.StateBackend, "state-backend", config.StateBackendFile, "where to record chaos actions (file or configmap)")
+	rootCmd.PersistentFlags().StringVar(&config.GlobalConfig.StateNamespace, "state-namespace", "default", "namespace of the tipsy-state ConfigMap when --state-backend=configmap")
 
 	// Cobra also supports local flags, which will only run
 	// when this action is called directly.
@@ -49,5 +56,27 @@ func PrintConfig() {
 		fmt.Printf("  Namespace: %s\n", config.GlobalConfig.Namespace)
 		fmt.Printf("  Dry Run: %t\n", config.GlobalConfig.DryRun)
 		fmt.Printf("  Verbose: %t\n", config.GlobalConfig.Verbose)
+		fmt.Printf("  State Backend: %s\n", config.GlobalConfig.StateBackend)
 	}
-} 
\ No newline at end of file
+} 
+
+// configureStateStore points the state package at the backend selected by --state-backend
+func configureStateStore() error {
+	switch config.GlobalConfig.StateBackend {
+	case "", config.StateBackendFile:
+		state.SetStore(state.NewFileStore())
+	case config.StateBackendConfigMap:
+		if config.GlobalConfig.StateNamespace == "" {
+			return fmt.Errorf("--state-namespace is required with --state-backend=%s", config.StateBackendConfigMap)
+		}
+		client, err := k8s.NewClient(config.GlobalConfig.Kubeconfig)
+		if err != nil {
+			return fmt.Errorf("failed to create Kubernetes client for the state backend: %w", err)
+		}
+		state.SetStore(state.NewConfigMapStore(client, config.GlobalConfig.StateNamespace))
+	default:
+		return fmt.Errorf("invalid state backend '%s': must be '%s' or '%s'",
+			config.GlobalConfig.StateBackend, config.StateBackendFile, config.StateBackendConfigMap)
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/isurusiri/tipsy/internal/config"
@@ -157,3 +158,28 @@ func TestPrintConfig(t *testing.T) {
 
 	PrintConfig()
 }
+
+func TestConfigureStateStore(t *testing.T) {
+	originalConfig := config.GlobalConfig
+	t.Cleanup(func() {
+		config.GlobalConfig = originalConfig
+		configureStateStore()
+	})
+
+	for _, backend := range []string{"", config.StateBackendFile} {
+		config.GlobalConfig = config.Config{StateBackend: backend}
+		if err := configureStateStore(); err != nil {
+			t.Errorf("Expected backend '%s' to be accepted, got %v", backend, err)
+		}
+	}
+
+	config.GlobalConfig = config.Config{StateBackend: "etcd"}
+	if err := configureStateStore(); err == nil || !strings.Contains(err.Error(), "invalid state backend") {
+		t.Errorf("Expected an error for an unknown backend, got %v", err)
+	}
+
+	config.GlobalConfig = config.Config{StateBackend: config.StateBackendConfigMap}
+	if err := configureStateStore(); err == nil || !strings.Contains(err.Error(), "--state-namespace") {
+		t.Errorf("Expected an error for a configmap backend without a namespace, got %v", err)
+	}
+}
@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/isurusiri/tipsy/internal/chaos"
+	"github.com/isurusiri/tipsy/internal/config"
+	"github.com/isurusiri/tipsy/internal/state"
+	"github.com/isurusiri/tipsy/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+var (
+	scaleDeployment  string
+	scaleStatefulSet string
+	scaleNamespace   string
+	scaleTo          int32
+	scaleBy          string
+	scalePinHPA      bool
+	scaleDuration    string
+)
+
+// scaleCmd represents the scale command
+var scaleCmd = &cobra.Command{
+	Use:   "scale",
+	Short: "Reduce capacity by scaling a Deployment or StatefulSet",
+	Long: `Reduce capacity by changing the replica count of a Deployment or StatefulSet.
+
+This command will:
+1. Fetch the workload and record its original replica count
+2. Detect any HorizontalPodAutoscaler targeting the workload and warn that it
+   will fight the change, or pin its bounds to the new count with --pin-hpa
+3. Scale the workload to the new replica count
+4. With --duration, restore the original replica count once the duration has passed
+
+Examples:
+  tipsy scale --deployment checkout --to 1 --duration 5m
+  tipsy scale --deployment checkout --by -50% --pin-hpa
+  tipsy scale --statefulset kafka --by -1 --namespace streaming --dry-run`,
+	Run: func(cmd *cobra.Command, args []string) {
+		// Print configuration if verbose mode is enabled
+		PrintConfig()
+
+		// Validate that exactly one workload is specified
+		if scaleDeployment == "" && scaleStatefulSet == "" {
+			utils.Error("either --deployment or --statefulset must be specified")
+			cmd.Help()
+			return
+		}
+
+		if scaleDeployment != "" && scaleStatefulSet != "" {
+			utils.Error("--deployment and --statefulset cannot be used together")
+			cmd.Help()
+			return
+		}
+
+		// Validate that exactly one replica change is specified
+		toSet := cmd.Flags().Changed("to")
+		if !toSet && scaleBy == "" {
+			utils.Error("either --to or --by must be specified")
+			cmd.Help()
+			return
+		}
+
+		if toSet && scaleBy != "" {
+			utils.Error("--to and --by cannot be used together")
+			cmd.Help()
+			return
+		}
+
+		kind, name := chaos.KindDeployment, scaleDeployment
+		if scaleStatefulSet != "" {
+			kind, name = chaos.KindStatefulSet, scaleStatefulSet
+		}
+
+		// Use global namespace if not specified locally
+		targetNamespace := scaleNamespace
+		if targetNamespace == "" {
+			targetNamespace = config.GlobalConfig.Namespace
+		}
+		if targetNamespace == "" {
+			targetNamespace = "default"
+		}
+
+		// Parse duration
+		durationParsed, err := time.ParseDuration(scaleDuration)
+		if err != nil {
+			utils.Error(fmt.Sprintf("Invalid duration format '%s': %v", scaleDuration, err))
+			return
+		}
+
+		// Create Kubernetes client
+		client, err := newClient()
+		if err != nil {
+			utils.Error(fmt.Sprintf("Failed to create Kubernetes client: %v", err))
+			return
+		}
+
+		// Catch interrupts before injecting, so one that arrives before the hold starts still rolls back
+		signals, release := catchSignalsForHold(durationParsed)
+		defer release()
+
+		// Execute the scale operation
+		result, err := chaos.ScaleWorkload(client, targetNamespace, kind, name, scaleTo, toSet, scaleBy, scalePinHPA, config.GlobalConfig.DryRun)
+		if err != nil && result == nil {
+			utils.Error(fmt.Sprintf("Failed to scale %s: %v", kind, err))
+			return
+		}
+
+		// Save state for the scale operation, including a partially applied one such as a pinned HPA
+		var actions []state.ChaosAction
+		if !config.GlobalConfig.DryRun {
+			action := state.ChaosAction{
+				ID:        state.NewActionID(),
+				Type:      "scale",
+				TargetPod: name, // Using workload name as target
+				TargetUID: string(result.UID),
+				Namespace: targetNamespace,
+				Timestamp: time.Now().UTC().Format(time.RFC3339),
+				Metadata: map[string]string{
+					"kind":             kind,
+					"originalReplicas": fmt.Sprintf("%d", result.OriginalReplicas),
+					"replicas":         fmt.Sprintf("%d", result.NewReplicas),
+					"duration":         scaleDuration,
+				},
+			}
+			if result.HPA != nil {
+				action.Metadata["hpa"] = result.HPA.Name
+				action.Metadata["hpaMinReplicas"] = fmt.Sprintf("%d", result.HPA.MinReplicas)
+				action.Metadata["hpaMaxReplicas"] = fmt.Sprintf("%d", result.HPA.MaxReplicas)
+				action.Metadata["hpaPinned"] = fmt.Sprintf("%t", result.HPA.Pinned)
+			}
+			if saveErr := state.SaveAction(action); saveErr != nil {
+				utils.Warn(fmt.Sprintf("Failed to save state for %s '%s': %v", kind, name, saveErr))
+			} else {
+				actions = append(actions, action)
+			}
+		}
+
+		if err != nil {
+			utils.Error(fmt.Sprintf("Failed to scale %s: %v", kind, err))
+			return
+		}
+
+		utils.Info("Scale operation completed successfully")
+
+		holdFault(client, signals, actions, durationParsed)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(scaleCmd)
+
+	// Local flags for the scale command
+	scaleCmd.Flags().StringVar(&scaleDeployment, "deployment", "", "Name of the Deployment to scale")
+	scaleCmd.Flags().StringVar(&scaleStatefulSet, "statefulset", "", "Name of the StatefulSet to scale")
+	scaleCmd.Flags().StringVar(&scaleNamespace, "namespace", "", "Kubernetes namespace to operate in (optional, defaults to global namespace or 'default')")
+	scaleCmd.Flags().Int32Var(&scaleTo, "to", 0, "Absolute replica count to scale to")
+	scaleCmd.Flags().StringVar(&scaleBy, "by", "", "Relative replica change, as a count or percentage (e.g., '-1', '-50%')")
+	scaleCmd.Flags().BoolVar(&scalePinHPA, "pin-hpa", false, "Pin the bounds of a HorizontalPodAutoscaler targeting the workload to the new replica count")
+	scaleCmd.Flags().StringVar(&scaleDuration, "duration", "0s", "How long to keep the new replica count before restoring it (e.g., '30s', '5m'); 0 keeps it until 'tipsy rollback'")
+}
@@ -0,0 +1,23 @@
+package cmd
+
+import (
+	"testing"
+)
+
+func TestScaleCmd(t *testing.T) {
+	// Test that the scale command is registered with the expected flags
+	scaleCommand := getCommand("scale")
+	if scaleCommand == nil {
+		t.Fatal("scale command not found in root command")
+	}
+
+	for _, flag := range []string{"deployment", "statefulset", "namespace", "to", "by", "pin-hpa", "duration"} {
+		if scaleCommand.Flag(flag) == nil {
+			t.Errorf("scale command missing --%s flag", flag)
+		}
+	}
+
+	if scaleCommand.Flag("duration").DefValue != "0s" {
+		t.Errorf("Expected scale to default to no automatic restore, got %s", scaleCommand.Flag("duration").DefValue)
+	}
+}
@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/isurusiri/tipsy/internal/state"
+	"github.com/isurusiri/tipsy/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+var (
+	stateExportFile    string
+	stateImportReplace bool
+)
+
+// stateCmd groups the commands that manage recorded state itself
+var stateCmd = &cobra.Command{
+	Use:   "state",
+	Short: "Migrate, export and import recorded state",
+	Long: `Manage the state tipsy records its chaos actions in.
+
+State is versioned: older state is upgraded as it is read, and 'tipsy state migrate'
+rewrites it in the current format. Export and import move state between machines
+and between backends.
+
+Examples:
+  tipsy state migrate                                   # Rewrite state in the current format
+  tipsy state export -f state-backup.json               # Save state to a file
+  tipsy state import state-backup.json                  # Add the actions in a file to state
+  tipsy state export | tipsy state import --state-backend configmap -   # Move state to a ConfigMap`,
+}
+
+// stateMigrateCmd rewrites state in the current format
+var stateMigrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Rewrite recorded state in the current format",
+	Run: func(cmd *cobra.Command, args []string) {
+		// Print configuration if verbose mode is enabled
+		PrintConfig()
+
+		if err := state.Migrate(); err != nil {
+			utils.Error(fmt.Sprintf("Failed to migrate state: %v", err))
+			return
+		}
+
+		utils.Info(fmt.Sprintf("State migrated to version %d", state.CurrentVersion))
+	},
+}
+
+// stateExportCmd writes recorded state to a file or stdout
+var stateExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export recorded state",
+	Long: `Export the recorded chaos actions as versioned JSON, to stdout or to the file
+given with --file, for 'tipsy state import' on another machine or backend.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		// Print configuration if verbose mode is enabled
+		PrintConfig()
+
+		data, err := state.Export()
+		if err != nil {
+			utils.Error(fmt.Sprintf("Failed to export state: %v", err))
+			return
+		}
+
+		if stateExportFile == "" {
+			fmt.Fprintln(cmd.OutOrStdout(), string(data))
+			return
+		}
+
+		if err := os.WriteFile(stateExportFile, append(data, '\n'), 0644); err != nil {
+			utils.Error(fmt.Sprintf("Failed to write export file: %v", err))
+			return
+		}
+		utils.Info(fmt.Sprintf("State exported to %s", stateExportFile))
+	},
+}
+
+// stateImportCmd adds the actions in exported state to recorded state
+var stateImportCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "Import exported state",
+	Long: `Import chaos actions exported with 'tipsy state export', or copied from a state
+file of any version. Use - to read from stdin.
+
+Actions already recorded are left as they are; --replace discards recorded state first.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		// Print configuration if verbose mode is enabled
+		PrintConfig()
+
+		data, err := readImportFile(cmd, args[0])
+		if err != nil {
+			utils.Error(fmt.Sprintf("Failed to read import file: %v", err))
+			return
+		}
+
+		count, err := state.Import(data, stateImportReplace)
+		if err != nil {
+			utils.Error(fmt.Sprintf("Failed to import state: %v", err))
+			return
+		}
+
+		utils.Info(fmt.Sprintf("Imported %d action(s)", count))
+	},
+}
+
+// readImportFile reads the file to import, or stdin for "-"
+func readImportFile(cmd *cobra.Command, path string) ([]byte, error) {
+	if path == "-" {
+		return io.ReadAll(cmd.InOrStdin())
+	}
+	return os.ReadFile(path)
+}
+
+func init() {
+	rootCmd.AddCommand(stateCmd)
+	stateCmd.AddCommand(stateMigrateCmd)
+	stateCmd.AddCommand(stateExportCmd)
+	stateCmd.AddCommand(stateImportCmd)
+
+	// Local flags for the state subcommands
+	stateExportCmd.Flags().StringVarP(&stateExportFile, "file", "f", "", "Write the export to this file instead of stdout")
+	stateImportCmd.Flags().BoolVar(&stateImportReplace, "replace", false, "Discard recorded state before importing")
+}
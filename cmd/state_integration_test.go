@@ -482,12 +482,19 @@ func TestStateFileFormat(t *testing.T) {
 		t.Fatalf("Failed to read state file: %v", err)
 	}
 
-	// Verify it's valid JSON
-	var loadedActions []state.ChaosAction
-	err = json.Unmarshal(content, &loadedActions)
+	// Verify it's valid JSON, wrapped in a versioned envelope
+	var envelope struct {
+		Version int                 `json:"version"`
+		Actions []state.ChaosAction `json:"actions"`
+	}
+	err = json.Unmarshal(content, &envelope)
 	if err != nil {
 		t.Fatalf("State file contains invalid JSON: %v", err)
 	}
+	loadedActions := envelope.Actions
+	if envelope.Version != state.CurrentVersion {
+		t.Errorf("Expected state version %d, got %d", state.CurrentVersion, envelope.Version)
+	}
 
 	// Verify all actions were saved
 	if len(loadedActions) != len(actions) {
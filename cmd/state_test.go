@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"testing"
+)
+
+func TestStateCmdSubcommands(t *testing.T) {
+	command := getCommand("state")
+	if command == nil {
+		t.Fatal("state command not found in root command")
+	}
+
+	for _, name := range []string{"migrate", "export", "import"} {
+		found := false
+		for _, sub := range command.Commands() {
+			if sub.Name() == name {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("state command missing %s subcommand", name)
+		}
+	}
+
+	if stateExportCmd.Flag("file") == nil {
+		t.Error("state export command missing --file flag")
+	}
+	if stateImportCmd.Flag("replace") == nil {
+		t.Error("state import command missing --replace flag")
+	}
+}
@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/isurusiri/tipsy/internal/config"
+	"github.com/isurusiri/tipsy/internal/state"
+	"github.com/isurusiri/tipsy/internal/status"
+	"github.com/isurusiri/tipsy/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+var (
+	statusType      string
+	statusNamespace string
+	statusOutput    string
+
+	statusAllClusters bool
+)
+
+// statusCmd represents the status command
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Check whether recorded chaos actions are still in force",
+	Long: `Check each chaos action recorded in the configured state store against the
+cluster. Actions live in ~/.tipsy/state.json, or in the tipsy-state ConfigMap with
+--state-backend configmap.
+
+A record in state does not mean the fault is still in place: injectors exit on
+their own, pods get replaced and controllers reconcile changes away. Each action
+is reported as one of:
+
+  active    the fault is still in force
+  expired   the fault ran its course: its duration has passed or its injector has exited
+  drifted   the target still exists but no longer carries the fault
+  orphaned  the target is gone or has been replaced by a new object with the same name
+
+Only actions recorded against the cluster tipsy is connected to are checked,
+unless --all-clusters is given.
+
+Examples:
+  tipsy status                           # Check every action recorded against this cluster
+  tipsy status --type latency            # Check only latency actions
+  tipsy status --namespace production    # Check actions in a namespace
+  tipsy status --all-clusters            # Check actions recorded against any cluster
+  tipsy status -o json                   # Print the results as JSON`,
+	Run: func(cmd *cobra.Command, args []string) {
+		// Print configuration if verbose mode is enabled
+		PrintConfig()
+
+		if statusOutput != outputTable && statusOutput != outputJSON {
+			utils.Error(fmt.Sprintf("Invalid output format '%s': must be '%s' or '%s'", statusOutput, outputTable, outputJSON))
+			cmd.Help()
+			return
+		}
+
+		// Use global namespace if not specified locally
+		filterNamespace := statusNamespace
+		if filterNamespace == "" {
+			filterNamespace = config.GlobalConfig.Namespace
+		}
+
+		actions, err := state.LoadActions()
+		if err != nil {
+			utils.Error(fmt.Sprintf("Failed to load actions from state: %v", err))
+			return
+		}
+
+		actions = filterListedActions(actions, statusType, "", filterNamespace)
+
+		// Create Kubernetes client
+		client, err := newClient()
+		if err != nil {
+			utils.Error(fmt.Sprintf("Failed to create Kubernetes client: %v", err))
+			return
+		}
+
+		// Only actions recorded against this cluster can be checked against it
+		actions, skipped := state.FilterByCluster(actions, clusterScope(statusAllClusters))
+		if skipped > 0 && statusOutput == outputTable {
+			utils.Info(fmt.Sprintf("Skipping %d action(s) recorded against other clusters; use --all-clusters to include them", skipped))
+		}
+
+		results := status.CheckAll(client, actions, time.Now())
+
+		if statusOutput == outputJSON {
+			err = writeStatusJSON(cmd.OutOrStdout(), results)
+		} else {
+			err = writeStatusTable(cmd.OutOrStdout(), results)
+		}
+		if err != nil {
+			utils.Error(fmt.Sprintf("Failed to print status: %v", err))
+		}
+	},
+}
+
+// writeStatusJSON prints status results as a pretty-printed JSON array
+func writeStatusJSON(out io.Writer, results []status.Result) error {
+	data, err := json.MarshalIndent(results, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal status to JSON: %w", err)
+	}
+
+	_, err = fmt.Fprintln(out, string(data))
+	return err
+}
+
+// writeStatusTable prints status results as an aligned table, one row per action
+func writeStatusTable(out io.Writer, results []status.Result) error {
+	if len(results) == 0 {
+		_, err := fmt.Fprintln(out, "No actions recorded")
+		return err
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "ID\tTYPE\tTARGET\tNAMESPACE\tSTATE\tDETAIL")
+	for _, result := range results {
+		action := result.Action
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			orDash(action.ID), action.Type, action.TargetPod, orDash(action.Namespace), result.State, result.Detail)
+	}
+
+	return w.Flush()
+}
+
+func init() {
+	rootCmd.AddCommand(statusCmd)
+
+	// Local flags for the status command
+	statusCmd.Flags().StringVar(&statusType, "type", "", "Check only actions of specific type")
+	statusCmd.Flags().StringVar(&statusNamespace, "namespace", "", "Check only actions in this namespace (optional, defaults to global namespace or all namespaces)")
+	statusCmd.Flags().StringVarP(&statusOutput, "output", "o", outputTable, "Output format (table or json)")
+	statusCmd.Flags().BoolVar(&statusAllClusters, "all-clusters", false, "Check actions recorded against any cluster, not just the current one")
+}
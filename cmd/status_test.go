@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/isurusiri/tipsy/internal/state"
+	"github.com/isurusiri/tipsy/internal/status"
+)
+
+func TestStatusCmdFlags(t *testing.T) {
+	command := getCommand("status")
+	if command == nil {
+		t.Fatal("status command not found in root command")
+	}
+
+	for _, flag := range []string{"type", "namespace", "output", "all-clusters"} {
+		if command.Flag(flag) == nil {
+			t.Errorf("status command missing --%s flag", flag)
+		}
+	}
+}
+
+func TestWriteStatus(t *testing.T) {
+	results := []status.Result{
+		{
+			Action: state.ChaosAction{ID: "aaa111", Type: "latency", TargetPod: "web-1", Namespace: "default"},
+			State:  status.StateActive,
+			Detail: "injector 'latency-injector-1' is running",
+		},
+		{
+			Action: state.ChaosAction{Type: "cordon", TargetPod: "node-1"},
+			State:  status.StateOrphaned,
+			Detail: "node no longer exists",
+		},
+	}
+
+	var out bytes.Buffer
+	if err := writeStatusTable(&out, results); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Expected a header and 2 rows, got:\n%s", out.String())
+	}
+	if !strings.HasPrefix(strings.Join(strings.Fields(lines[1]), " "), "aaa111 latency web-1 default active") {
+		t.Errorf("Unexpected row: %s", lines[1])
+	}
+	if !strings.HasPrefix(strings.Join(strings.Fields(lines[2]), " "), "- cordon node-1 - orphaned") {
+		t.Errorf("Unexpected row: %s", lines[2])
+	}
+
+	out.Reset()
+	if err := writeStatusJSON(&out, results); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var decoded []status.Result
+	if err := json.Unmarshal(out.Bytes(), &decoded); err != nil {
+		t.Fatalf("Output is not valid JSON: %v", err)
+	}
+	if len(decoded) != 2 || decoded[1].State != status.StateOrphaned {
+		t.Errorf("Unexpected results: %+v", decoded)
+	}
+}
@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/isurusiri/tipsy/internal/chaos"
+	"github.com/isurusiri/tipsy/internal/state"
+	"github.com/spf13/cobra"
+)
+
+// ordinalFlags holds the StatefulSet ordinal flags shared by pod faults
+type ordinalFlags struct {
+	ordinals       []int
+	exceptOrdinals []int
+}
+
+// register adds the ordinal flags to a command
+func (f *ordinalFlags) register(cmd *cobra.Command) {
+	cmd.Flags().IntSliceVar(&f.ordinals, "ordinal", nil, "Target only StatefulSet pods with these ordinals (e.g., '0' for the primary, '1,2')")
+	cmd.Flags().IntSliceVar(&f.exceptOrdinals, "except-ordinal", nil, "Target every StatefulSet pod except these ordinals (e.g., '0' for all but the primary)")
+}
+
+// apply narrows a pod target to the requested ordinals
+func (f *ordinalFlags) apply(target chaos.PodTarget) chaos.PodTarget {
+	target.Ordinals = f.ordinals
+	target.ExceptOrdinals = f.exceptOrdinals
+	return target
+}
+
+// withPodIdentity records the UID and controller of an affected pod on its action, so rollback
+// can tell the original pod from a replacement with the same name
+func withPodIdentity(action state.ChaosAction, pod chaos.PodRef) state.ChaosAction {
+	action.TargetUID = string(pod.UID)
+	if pod.Owner != nil {
+		action.Owner = &state.OwnerRef{Kind: pod.Owner.Kind, Name: pod.Owner.Name, UID: string(pod.Owner.UID)}
+	}
+	if pod.Ordinal >= 0 {
+		if action.Metadata == nil {
+			action.Metadata = map[string]string{}
+		}
+		action.Metadata["ordinal"] = fmt.Sprintf("%d", pod.Ordinal)
+	}
+	return action
+}
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/isurusiri/tipsy/internal/chaos"
+	"github.com/isurusiri/tipsy/internal/state"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestWithPodIdentity(t *testing.T) {
+	pod := chaos.PodRef{
+		Name:    "db-0",
+		UID:     "uid-1",
+		Owner:   &metav1.OwnerReference{Kind: "StatefulSet", Name: "db", UID: "uid-db"},
+		Ordinal: 0,
+	}
+	action := withPodIdentity(state.ChaosAction{Type: "kill", TargetPod: "db-0", Metadata: map[string]string{"selector": "app=db"}}, pod)
+	if action.TargetUID != "uid-1" || action.Metadata["ordinal"] != "0" || action.Metadata["selector"] != "app=db" {
+		t.Errorf("Unexpected action for a StatefulSet pod: %+v", action)
+	}
+	if action.Owner == nil || action.Owner.Kind != "StatefulSet" || action.Owner.Name != "db" || action.Owner.UID != "uid-db" {
+		t.Errorf("Expected the StatefulSet owner to be recorded, got %+v", action.Owner)
+	}
+
+	action = withPodIdentity(state.ChaosAction{Type: "kill", TargetPod: "web-abc", Metadata: map[string]string{}}, chaos.PodRef{Name: "web-abc", UID: "uid-2", Ordinal: -1})
+	if _, exists := action.Metadata["ordinal"]; exists || action.Owner != nil {
+		t.Errorf("Expected no ordinal or owner for a bare pod, got %+v", action)
+	}
+}
+
+func TestOrdinalFlags(t *testing.T) {
+	for _, name := range []string{"kill", "latency", "packetloss", "cpustress"} {
+		command := getCommand(name)
+		if command == nil {
+			t.Fatalf("%s command not found in root command", name)
+		}
+		for _, flag := range []string{"ordinal", "except-ordinal"} {
+			if command.Flag(flag) == nil {
+				t.Errorf("%s command missing --%s flag", name, flag)
+			}
+		}
+	}
+
+	flags := ordinalFlags{ordinals: []int{0}, exceptOrdinals: []int{2}}
+	target := flags.apply(chaos.PodTarget{Namespace: "default", Selector: "app=db"})
+	if len(target.Ordinals) != 1 || target.Ordinals[0] != 0 || len(target.ExceptOrdinals) != 1 || target.ExceptOrdinals[0] != 2 {
+		t.Errorf("Unexpected target: %+v", target)
+	}
+}
@@ -0,0 +1,237 @@
+package chaos
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/isurusiri/tipsy/internal/utils"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Config object kinds that can be mutated
+const (
+	KindConfigMap = "ConfigMap"
+	KindSecret    = "Secret"
+)
+
+// ConfigFaultResult describes a mutation made to a ConfigMap or Secret
+type ConfigFaultResult struct {
+	Kind       string
+	Name       string
+	UID        types.UID
+	BackupPath string   // file holding the original object
+	Checksum   string   // checksum of the object's data as tipsy left it
+	Changed    []string // keys that were set or deleted
+}
+
+// ParseKeyValues parses "key=value" pairs as given to --set
+func ParseKeyValues(pairs []string) (map[string]string, error) {
+	values := make(map[string]string)
+	for _, pair := range pairs {
+		key, value, found := strings.Cut(pair, "=")
+		if !found || key == "" {
+			return nil, fmt.Errorf("invalid key/value '%s': expected key=value", pair)
+		}
+		values[key] = value
+	}
+	return values, nil
+}
+
+// MutateConfig sets and deletes keys in a ConfigMap or Secret after saving the
+// original object to disk for rollback
+func MutateConfig(client kubernetes.Interface, namespace, kind, name string, set map[string]string, deleteKeys []string, dryRun bool) (*ConfigFaultResult, error) {
+	utils.Info(fmt.Sprintf("Starting config fault for %s '%s' in namespace '%s'", kind, name, namespace))
+
+	if kind != KindConfigMap && kind != KindSecret {
+		return nil, fmt.Errorf("unsupported config kind: %s", kind)
+	}
+
+	// In dry-run mode, simulate the operation without making API calls
+	if dryRun {
+		utils.DryRun(fmt.Sprintf("Would fetch %s '%s' in namespace '%s'", kind, name, namespace))
+		utils.DryRun(fmt.Sprintf("Would save original %s for rollback", kind))
+		for _, key := range sortedKeys(set) {
+			utils.DryRun(fmt.Sprintf("Would set key '%s'", key))
+		}
+		for _, key := range deleteKeys {
+			utils.DryRun(fmt.Sprintf("Would delete key '%s'", key))
+		}
+		return &ConfigFaultResult{Kind: kind, Name: name}, nil
+	}
+
+	if kind == KindConfigMap {
+		return mutateConfigMap(client, namespace, name, set, deleteKeys)
+	}
+	return mutateSecret(client, namespace, name, set, deleteKeys)
+}
+
+// mutateConfigMap applies a config fault to a ConfigMap
+func mutateConfigMap(client kubernetes.Interface, namespace, name string, set map[string]string, deleteKeys []string) (*ConfigFaultResult, error) {
+	configMap, err := client.CoreV1().ConfigMaps(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get configmap '%s': %w", name, err)
+	}
+
+	// Refuse to continue without a backup, since the original values could not be restored
+	backupPath, err := saveOriginalConfig(configMap, KindConfigMap, name, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	modified := configMap.DeepCopy()
+	if modified.Data == nil {
+		modified.Data = make(map[string]string)
+	}
+
+	result := &ConfigFaultResult{Kind: KindConfigMap, Name: name, UID: configMap.UID, BackupPath: backupPath}
+	for _, key := range sortedKeys(set) {
+		modified.Data[key] = set[key]
+		result.Changed = append(result.Changed, key)
+		utils.Info(fmt.Sprintf("  Set key: %s", key))
+	}
+	for _, key := range deleteKeys {
+		_, inData := modified.Data[key]
+		_, inBinaryData := modified.BinaryData[key]
+		if !inData && !inBinaryData {
+			utils.Warn(fmt.Sprintf("Key '%s' not found in configmap '%s'", key, name))
+			continue
+		}
+		delete(modified.Data, key)
+		delete(modified.BinaryData, key)
+		result.Changed = append(result.Changed, key)
+		utils.Info(fmt.Sprintf("  Deleted key: %s", key))
+	}
+
+	updated, err := client.CoreV1().ConfigMaps(namespace).Update(context.TODO(), modified, metav1.UpdateOptions{})
+	if err != nil {
+		os.Remove(backupPath)
+		return nil, fmt.Errorf("failed to update configmap '%s': %w", name, err)
+	}
+
+	result.Checksum = ConfigMapChecksum(updated)
+	utils.Info(fmt.Sprintf("Successfully mutated configmap '%s'", name))
+	return result, nil
+}
+
+// mutateSecret applies a config fault to a Secret
+// Values given to --set are plain text and are stored as the secret's decoded data
+func mutateSecret(client kubernetes.Interface, namespace, name string, set map[string]string, deleteKeys []string) (*ConfigFaultResult, error) {
+	secret, err := client.CoreV1().Secrets(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get secret '%s': %w", name, err)
+	}
+
+	// Refuse to continue without a backup, since the original values could not be restored
+	backupPath, err := saveOriginalConfig(secret, KindSecret, name, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	modified := secret.DeepCopy()
+	if modified.Data == nil {
+		modified.Data = make(map[string][]byte)
+	}
+
+	result := &ConfigFaultResult{Kind: KindSecret, Name: name, UID: secret.UID, BackupPath: backupPath}
+	for _, key := range sortedKeys(set) {
+		modified.Data[key] = []byte(set[key])
+		result.Changed = append(result.Changed, key)
+		utils.Info(fmt.Sprintf("  Set key: %s", key))
+	}
+	for _, key := range deleteKeys {
+		if _, exists := modified.Data[key]; !exists {
+			utils.Warn(fmt.Sprintf("Key '%s' not found in secret '%s'", key, name))
+			continue
+		}
+		delete(modified.Data, key)
+		result.Changed = append(result.Changed, key)
+		utils.Info(fmt.Sprintf("  Deleted key: %s", key))
+	}
+
+	updated, err := client.CoreV1().Secrets(namespace).Update(context.TODO(), modified, metav1.UpdateOptions{})
+	if err != nil {
+		os.Remove(backupPath)
+		return nil, fmt.Errorf("failed to update secret '%s': %w", name, err)
+	}
+
+	result.Checksum = SecretChecksum(updated)
+	utils.Info(fmt.Sprintf("Successfully mutated secret '%s'", name))
+	return result, nil
+}
+
+// ConfigMapChecksum returns a checksum of a ConfigMap's data, used to detect
+// changes made by someone else after a config fault was injected
+func ConfigMapChecksum(configMap *corev1.ConfigMap) string {
+	return checksum(struct {
+		Data       map[string]string `json:"data,omitempty"`
+		BinaryData map[string][]byte `json:"binaryData,omitempty"`
+	}{configMap.Data, configMap.BinaryData})
+}
+
+// SecretChecksum returns a checksum of a Secret's data, used to detect
+// changes made by someone else after a config fault was injected
+func SecretChecksum(secret *corev1.Secret) string {
+	return checksum(struct {
+		Data map[string][]byte `json:"data,omitempty"`
+	}{secret.Data})
+}
+
+// checksum hashes the JSON encoding of v; map keys are encoded in sorted order
+func checksum(v interface{}) string {
+	data, _ := json.Marshal(v)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// saveOriginalConfig saves the original ConfigMap or Secret to disk for rollback
+// Each fault gets its own timestamped backup so repeated faults do not overwrite earlier originals
+func saveOriginalConfig(obj interface{}, kind, name, namespace string) (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get user home directory: %w", err)
+	}
+
+	rollbackDir := filepath.Join(homeDir, ".tipsy", "rollback")
+	err = os.MkdirAll(rollbackDir, 0755)
+	if err != nil {
+		return "", fmt.Errorf("failed to create rollback directory: %w", err)
+	}
+
+	filename := fmt.Sprintf("%s_%s_%s_%d.json", strings.ToLower(kind), name, namespace, time.Now().UnixNano())
+	backupPath := filepath.Join(rollbackDir, filename)
+
+	data, err := json.MarshalIndent(obj, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal %s to JSON: %w", strings.ToLower(kind), err)
+	}
+
+	// Secrets hold credentials, so keep backups readable by the owner only
+	err = os.WriteFile(backupPath, data, 0600)
+	if err != nil {
+		return "", fmt.Errorf("failed to write %s backup to file: %w", strings.ToLower(kind), err)
+	}
+
+	utils.Info(fmt.Sprintf("Saved original %s to %s for rollback", strings.ToLower(kind), backupPath))
+	return backupPath, nil
+}
+
+// sortedKeys returns the keys of a map in sorted order
+func sortedKeys(values map[string]string) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,150 @@
+package chaos
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/fatih/color"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestParseKeyValues(t *testing.T) {
+	values, err := ParseKeyValues([]string{"log_level=debug", "url=http://a=b", "empty="})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if values["log_level"] != "debug" || values["url"] != "http://a=b" || values["empty"] != "" {
+		t.Errorf("Unexpected parsed values: %v", values)
+	}
+
+	for _, invalid := range []string{"novalue", "=value"} {
+		if _, err := ParseKeyValues([]string{invalid}); err == nil {
+			t.Errorf("Expected error for '%s'", invalid)
+		}
+	}
+}
+
+func TestMutateConfig_ConfigMap(t *testing.T) {
+	// Disable color for testing
+	originalNoColor := color.NoColor
+	color.NoColor = true
+	defer func() {
+		color.NoColor = originalNoColor
+	}()
+
+	// Keep backups out of the real home directory
+	t.Setenv("HOME", t.TempDir())
+
+	client := fake.NewSimpleClientset(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "app-config", Namespace: "default"},
+		Data:       map[string]string{"log_level": "info", "database_url": "postgres://db"},
+	})
+
+	result, err := MutateConfig(client, "default", KindConfigMap, "app-config",
+		map[string]string{"log_level": "garbage"}, []string{"database_url", "missing"}, false)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(result.Changed) != 2 {
+		t.Errorf("Expected 2 changed keys, got %v", result.Changed)
+	}
+
+	configMap, _ := client.CoreV1().ConfigMaps("default").Get(context.TODO(), "app-config", metav1.GetOptions{})
+	if configMap.Data["log_level"] != "garbage" {
+		t.Errorf("Expected log_level to be set, got '%s'", configMap.Data["log_level"])
+	}
+	if _, exists := configMap.Data["database_url"]; exists {
+		t.Error("Expected database_url to be deleted")
+	}
+	if result.Checksum != ConfigMapChecksum(configMap) {
+		t.Error("Expected checksum to match the mutated configmap")
+	}
+
+	// The backup holds the original object
+	data, err := os.ReadFile(result.BackupPath)
+	if err != nil {
+		t.Fatalf("Failed to read backup: %v", err)
+	}
+	var original corev1.ConfigMap
+	if err := json.Unmarshal(data, &original); err != nil {
+		t.Fatalf("Failed to unmarshal backup: %v", err)
+	}
+	if original.Data["log_level"] != "info" || original.Data["database_url"] != "postgres://db" {
+		t.Errorf("Expected backup to hold the original data, got %v", original.Data)
+	}
+}
+
+func TestMutateConfig_Secret(t *testing.T) {
+	// Disable color for testing
+	originalNoColor := color.NoColor
+	color.NoColor = true
+	defer func() {
+		color.NoColor = originalNoColor
+	}()
+
+	t.Setenv("HOME", t.TempDir())
+
+	client := fake.NewSimpleClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "db-credentials", Namespace: "default"},
+		Data:       map[string][]byte{"password": []byte("hunter2")},
+	})
+
+	result, err := MutateConfig(client, "default", KindSecret, "db-credentials",
+		map[string]string{"password": "rotated"}, nil, false)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	secret, _ := client.CoreV1().Secrets("default").Get(context.TODO(), "db-credentials", metav1.GetOptions{})
+	if string(secret.Data["password"]) != "rotated" {
+		t.Errorf("Expected password to be rotated, got '%s'", secret.Data["password"])
+	}
+
+	// Secret backups must not be readable by other users
+	info, err := os.Stat(result.BackupPath)
+	if err != nil {
+		t.Fatalf("Failed to stat backup: %v", err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("Expected backup permissions 0600, got %o", info.Mode().Perm())
+	}
+}
+
+func TestMutateConfig_Errors(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	client := fake.NewSimpleClientset()
+
+	if _, err := MutateConfig(client, "default", "Pod", "web", nil, []string{"a"}, false); err == nil {
+		t.Error("Expected error for unsupported kind")
+	}
+
+	if _, err := MutateConfig(client, "default", KindConfigMap, "missing", nil, []string{"a"}, false); err == nil {
+		t.Error("Expected error for missing configmap")
+	}
+}
+
+func TestMutateConfig_DryRun(t *testing.T) {
+	client := fake.NewSimpleClientset(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "app-config", Namespace: "default"},
+		Data:       map[string]string{"log_level": "info"},
+	})
+
+	result, err := MutateConfig(client, "default", KindConfigMap, "app-config", map[string]string{"log_level": "garbage"}, nil, true)
+	if err != nil {
+		t.Fatalf("Unexpected error in dry-run mode: %v", err)
+	}
+	if result.BackupPath != "" {
+		t.Error("Expected no backup in dry-run mode")
+	}
+
+	configMap, _ := client.CoreV1().ConfigMaps("default").Get(context.TODO(), "app-config", metav1.GetOptions{})
+	if configMap.Data["log_level"] != "info" {
+		t.Error("Expected configmap to be unchanged in dry-run mode")
+	}
+}
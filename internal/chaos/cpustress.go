@@ -17,37 +17,46 @@ import (
 // InjectCPUStress injects CPU load into pods using ephemeral containers
 // Returns the list of pod names that were affected by the CPU stress injection
 func InjectCPUStress(client kubernetes.Interface, namespace, selector, method string, duration time.Duration, dryRun bool) ([]string, error) {
-	utils.Info(fmt.Sprintf("Searching for pods with selector '%s' in namespace '%s'", selector, namespace))
+	affectedPods, err := InjectCPUStressForTarget(client, PodTarget{Namespace: namespace, Selector: selector}, method, duration, dryRun)
+	if err != nil {
+		return nil, err
+	}
+	return PodNames(affectedPods), nil
+}
+
+// InjectCPUStressForTarget injects CPU load into the running pods matched by a target
+// Returns the pods that were affected
+func InjectCPUStressForTarget(client kubernetes.Interface, target PodTarget, method string, duration time.Duration, dryRun bool) ([]PodRef, error) {
+	namespace := target.Namespace
+	utils.Info(fmt.Sprintf("Searching for %s", target))
 
 	// In dry-run mode, simulate the operation without making API calls
 	if dryRun {
-		utils.DryRun(fmt.Sprintf("Would search for pods with selector '%s' in namespace '%s'", selector, namespace))
+		utils.DryRun(fmt.Sprintf("Would search for %s", target))
 		utils.DryRun(fmt.Sprintf("Would inject CPU stress to all running pods matching selector"))
 		utils.DryRun(fmt.Sprintf("Would add ephemeral containers with method: %s", method))
 		utils.DryRun(fmt.Sprintf("Would run CPU stress for duration: %s", duration))
 		// Return empty slice for dry-run as we can't determine actual pod names
-		return []string{}, nil
+		return []PodRef{}, nil
 	}
 
-	// List pods matching the selector
-	pods, err := client.CoreV1().Pods(namespace).List(context.TODO(), metav1.ListOptions{
-		LabelSelector: selector,
-	})
+	// List pods matching the target
+	pods, err := SelectPods(client, target)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list pods: %w", err)
+		return nil, err
 	}
 
-	if len(pods.Items) == 0 {
-		utils.Warn(fmt.Sprintf("No pods found matching selector '%s' in namespace '%s'", selector, namespace))
-		return []string{}, nil
+	if len(pods) == 0 {
+		utils.Warn(fmt.Sprintf("No pods found for %s", target))
+		return []PodRef{}, nil
 	}
 
-	utils.Info(fmt.Sprintf("Found %d pod(s) matching selector", len(pods.Items)))
+	utils.Info(fmt.Sprintf("Found %d pod(s) matching selector", len(pods)))
 
-	var affectedPods []string
+	var affectedPods []PodRef
 
 	// Process each pod
-	for _, pod := range pods.Items {
+	for _, pod := range pods {
 		if pod.Status.Phase != corev1.PodRunning {
 			utils.Warn(fmt.Sprintf("Skipping pod '%s' - not in Running state (current: %s)", pod.Name, pod.Status.Phase))
 			continue
@@ -59,7 +68,7 @@ func InjectCPUStress(client kubernetes.Interface, namespace, selector, method st
 			// Continue with other pods even if one fails
 		} else {
 			// Only add to affected pods if the injection was successful
-			affectedPods = append(affectedPods, pod.Name)
+			affectedPods = append(affectedPods, newPodRef(pod))
 		}
 	}
 
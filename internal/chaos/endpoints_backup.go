@@ -0,0 +1,229 @@
+package chaos
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/isurusiri/tipsy/internal/state"
+	"github.com/isurusiri/tipsy/internal/utils"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+)
+
+// EndpointsBackupVersion is the version of the endpoints backup format written by this tipsy
+// Version 2 added Slices, and covers them with the checksum
+const EndpointsBackupVersion = 2
+
+// EndpointsBackup holds a service's Endpoints and EndpointSlices as they were before a misroute
+// Each misroute action gets its own backup, stacked per service, so misrouting a service
+// twice never overwrites the true original: that is always the oldest backup in the stack
+type EndpointsBackup struct {
+	Version   int               `json:"version"` // zero for a backup written before backups were versioned
+	ActionID  string            `json:"actionID"`
+	Service   string            `json:"service"`
+	Namespace string            `json:"namespace"`
+	Cluster   *state.ClusterRef `json:"cluster,omitempty"`
+	CreatedAt time.Time         `json:"createdAt"`
+	Checksum  string            `json:"checksum"` // SHA-256 of the JSON encoding of Endpoints, and of Slices from version 2
+	Endpoints corev1.Endpoints  `json:"endpoints"`
+
+	// Slices are the EndpointSlices the misroute detached from the service
+	Slices []discoveryv1.EndpointSlice `json:"slices,omitempty"`
+
+	// Applied is the EndpointsChecksum of the endpoints as tipsy last left them, so a rollback
+	// can tell whether anyone else has changed them since; empty if it was never recorded
+	Applied string `json:"applied,omitempty"`
+}
+
+// EndpointsChecksum returns a checksum of an Endpoints' subsets, used to detect
+// changes made by someone else after a service was misrouted
+// No subsets checksum alike, however they are encoded
+func EndpointsChecksum(endpoints *corev1.Endpoints) string {
+	if len(endpoints.Subsets) == 0 {
+		return checksum(nil)
+	}
+	return checksum(endpoints.Subsets)
+}
+
+// endpointsBackupDir returns the directory holding the backup stack of a service
+func endpointsBackupDir(svcName, namespace string) (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get user home directory: %w", err)
+	}
+	return filepath.Join(homeDir, ".tipsy", "rollback", "endpoints", fmt.Sprintf("%s_%s", namespace, svcName)), nil
+}
+
+// saveEndpointsBackup pushes a backup of the endpoints and slices onto the service's stack
+// Backup files are named by creation time, so the stack sorts oldest first
+func saveEndpointsBackup(endpoints *corev1.Endpoints, slices []discoveryv1.EndpointSlice, svcName, namespace, actionID string, cluster *state.ClusterRef) (string, error) {
+	dir, err := endpointsBackupDir(svcName, namespace)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create rollback directory: %w", err)
+	}
+
+	createdAt := time.Now().UTC()
+	backup := EndpointsBackup{
+		Version:   EndpointsBackupVersion,
+		ActionID:  actionID,
+		Service:   svcName,
+		Namespace: namespace,
+		Cluster:   cluster,
+		CreatedAt: createdAt,
+		Endpoints: *endpoints,
+		Slices:    slices,
+	}
+
+	backupPath := filepath.Join(dir, fmt.Sprintf("%020d_%s.json", createdAt.UnixNano(), actionID))
+	if err := writeEndpointsBackup(backupPath, backup); err != nil {
+		return "", err
+	}
+
+	utils.Info(fmt.Sprintf("Saved original endpoints to %s for rollback", backupPath))
+	return backupPath, nil
+}
+
+// writeEndpointsBackup writes a backup with a fresh checksum
+func writeEndpointsBackup(path string, backup EndpointsBackup) error {
+	backup.Checksum = backupChecksum(backup)
+
+	data, err := json.MarshalIndent(backup, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal endpoints to JSON: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write endpoints to file: %w", err)
+	}
+	return nil
+}
+
+// ReadEndpointsBackup reads a backup and verifies its checksum
+// A backup written before backups were versioned holds just the Endpoints; it is returned
+// with Version zero and nothing to verify
+func ReadEndpointsBackup(path string) (*EndpointsBackup, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read backup file %s: %w", path, err)
+	}
+
+	var backup EndpointsBackup
+	if err := json.Unmarshal(data, &backup); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal backup file %s: %w", path, err)
+	}
+
+	if backup.Version == 0 {
+		var endpoints corev1.Endpoints
+		if err := json.Unmarshal(data, &endpoints); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal backup endpoints: %w", err)
+		}
+		return &EndpointsBackup{Service: endpoints.Name, Namespace: endpoints.Namespace, Endpoints: endpoints}, nil
+	}
+
+	if backup.Version > EndpointsBackupVersion {
+		return nil, fmt.Errorf("backup file %s has version %d, newer than this tipsy understands", path, backup.Version)
+	}
+	if sum := backupChecksum(backup); sum != backup.Checksum {
+		return nil, fmt.Errorf("backup file %s fails its checksum; it may have been modified or truncated", path)
+	}
+
+	return &backup, nil
+}
+
+// backupChecksum returns the checksum of a backup's contents, as covered by its version
+func backupChecksum(backup EndpointsBackup) string {
+	if backup.Version < 2 {
+		return checksum(backup.Endpoints)
+	}
+	return checksum(struct {
+		Endpoints corev1.Endpoints            `json:"endpoints"`
+		Slices    []discoveryv1.EndpointSlice `json:"slices,omitempty"`
+	}{backup.Endpoints, backup.Slices})
+}
+
+// EndpointsBackupStack lists the backups stacked alongside the given one, oldest first
+func EndpointsBackupStack(path string) ([]string, error) {
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read backup directory: %w", err)
+	}
+
+	stack := []string{}
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".json") {
+			stack = append(stack, filepath.Join(filepath.Dir(path), entry.Name()))
+		}
+	}
+	sort.Strings(stack)
+	return stack, nil
+}
+
+// recordAppliedEndpoints records in a backup the endpoints its misroute left behind
+func recordAppliedEndpoints(path string, endpoints *corev1.Endpoints) error {
+	backup, err := ReadEndpointsBackup(path)
+	if err != nil {
+		return err
+	}
+
+	backup.Applied = EndpointsChecksum(endpoints)
+	return writeEndpointsBackup(path, *backup)
+}
+
+// DropEndpointsBackup removes a backup from its stack without restoring it, for an action
+// rolled back while other misroutes of the same service remain
+// If it is the oldest backup, the next one inherits its Endpoints and Slices, so the oldest
+// backup keeps holding the true original; if it is the newest, the one before inherits its
+// Applied checksum, so the newest backup keeps describing the endpoints as tipsy left them
+func DropEndpointsBackup(path string) error {
+	stack, err := EndpointsBackupStack(path)
+	if err != nil {
+		return err
+	}
+
+	if len(stack) > 1 && (stack[0] == path || stack[len(stack)-1] == path) {
+		dropped, err := ReadEndpointsBackup(path)
+		if err != nil {
+			return err
+		}
+
+		heir := stack[1]
+		if stack[0] != path {
+			heir = stack[len(stack)-2]
+		}
+		inheriting, err := ReadEndpointsBackup(heir)
+		if err != nil {
+			return err
+		}
+
+		if stack[0] == path {
+			inheriting.Endpoints = dropped.Endpoints
+			inheriting.Slices = dropped.Slices
+		} else {
+			inheriting.Applied = dropped.Applied
+		}
+		if err := writeEndpointsBackup(heir, *inheriting); err != nil {
+			return err
+		}
+	}
+
+	return RemoveEndpointsBackup(path)
+}
+
+// RemoveEndpointsBackup deletes a backup, along with its stack directory once that is empty
+func RemoveEndpointsBackup(path string) error {
+	if err := os.Remove(path); err != nil {
+		return fmt.Errorf("failed to remove backup file %s: %w", path, err)
+	}
+
+	// Fails, harmlessly, while other backups remain
+	os.Remove(filepath.Dir(path))
+	return nil
+}
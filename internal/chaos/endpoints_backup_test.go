@@ -0,0 +1,210 @@
+package chaos
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/isurusiri/tipsy/internal/state"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// misrouteFixture returns a service and its endpoints with a single ready address
+func misrouteFixture() (*corev1.Service, *corev1.Endpoints) {
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Spec:       corev1.ServiceSpec{Ports: []corev1.ServicePort{{Name: "http", Port: 80, Protocol: corev1.ProtocolTCP}}},
+	}
+	endpoints := &corev1.Endpoints{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Subsets: []corev1.EndpointSubset{{
+			Addresses: []corev1.EndpointAddress{{IP: "10.0.0.1"}},
+			Ports:     []corev1.EndpointPort{{Name: "http", Port: 80, Protocol: corev1.ProtocolTCP}},
+		}},
+	}
+	return service, endpoints
+}
+
+func TestMisrouteServiceStacksBackups(t *testing.T) {
+	// Disable color for testing
+	originalNoColor := color.NoColor
+	color.NoColor = true
+	defer func() {
+		color.NoColor = originalNoColor
+	}()
+
+	// Keep backups out of the real home directory
+	t.Setenv("HOME", t.TempDir())
+
+	service, endpoints := misrouteFixture()
+	client := fake.NewSimpleClientset(service, endpoints)
+	cluster := &state.ClusterRef{Server: "https://staging:6443", UID: "staging-uid"}
+
+	// Misroute the same service twice; the second backup holds already-misrouted endpoints
+	firstPath, err := MisrouteService(client, "web", "default", MisrouteOptions{RemoveAll: true}, false, "first", cluster)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	secondPath, err := MisrouteService(client, "web", "default", MisrouteOptions{RemoveAll: true}, false, "second", cluster)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if firstPath == secondPath {
+		t.Fatal("Expected each misroute to get its own backup")
+	}
+
+	stack, err := EndpointsBackupStack(secondPath)
+	if err != nil {
+		t.Fatalf("Failed to list backups: %v", err)
+	}
+	if len(stack) != 2 || stack[0] != firstPath || stack[1] != secondPath {
+		t.Fatalf("Expected the backups stacked oldest first, got %v", stack)
+	}
+
+	first, err := ReadEndpointsBackup(firstPath)
+	if err != nil {
+		t.Fatalf("Failed to read backup: %v", err)
+	}
+	if first.ActionID != "first" || first.Version != EndpointsBackupVersion || *first.Cluster != *cluster {
+		t.Errorf("Unexpected backup: %+v", first)
+	}
+	if len(first.Endpoints.Subsets) != 1 {
+		t.Errorf("Expected the oldest backup to hold the original endpoints, got %+v", first.Endpoints.Subsets)
+	}
+	if first.Applied != EndpointsChecksum(&corev1.Endpoints{}) {
+		t.Errorf("Expected the backup to record the misrouted endpoints, got %q", first.Applied)
+	}
+
+	// Dropping the oldest hands the original on to the next backup
+	if err := DropEndpointsBackup(firstPath); err != nil {
+		t.Fatalf("Failed to drop backup: %v", err)
+	}
+	second, err := ReadEndpointsBackup(secondPath)
+	if err != nil {
+		t.Fatalf("Failed to read backup: %v", err)
+	}
+	if len(second.Endpoints.Subsets) != 1 || second.ActionID != "second" {
+		t.Errorf("Expected the remaining backup to inherit the original endpoints, got %+v", second)
+	}
+	if _, err := os.Stat(firstPath); !os.IsNotExist(err) {
+		t.Error("Expected the dropped backup to be removed")
+	}
+}
+
+func TestReadEndpointsBackupChecksum(t *testing.T) {
+	// Disable color for testing
+	originalNoColor := color.NoColor
+	color.NoColor = true
+	defer func() {
+		color.NoColor = originalNoColor
+	}()
+
+	// Keep backups out of the real home directory
+	t.Setenv("HOME", t.TempDir())
+
+	_, endpoints := misrouteFixture()
+	path, err := saveEndpointsBackup(endpoints, nil, "web", "default", "abc123", nil)
+	if err != nil {
+		t.Fatalf("Failed to save backup: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read backup: %v", err)
+	}
+	tampered := strings.Replace(string(data), "10.0.0.1", "10.0.0.9", 1)
+	if err := os.WriteFile(path, []byte(tampered), 0644); err != nil {
+		t.Fatalf("Failed to write backup: %v", err)
+	}
+
+	if _, err := ReadEndpointsBackup(path); err == nil || !strings.Contains(err.Error(), "checksum") {
+		t.Errorf("Expected a checksum error for a modified backup, got %v", err)
+	}
+}
+
+func TestMisrouteServiceUpdateFailureRemovesBackup(t *testing.T) {
+	// Disable color for testing
+	originalNoColor := color.NoColor
+	color.NoColor = true
+	defer func() {
+		color.NoColor = originalNoColor
+	}()
+
+	// Keep backups out of the real home directory
+	t.Setenv("HOME", t.TempDir())
+
+	service, endpoints := misrouteFixture()
+	client := fake.NewSimpleClientset(service, endpoints)
+	client.PrependReactor("update", "endpoints", func(k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.New("endpoints controller is fighting back")
+	})
+
+	if _, err := MisrouteService(client, "web", "default", MisrouteOptions{RemoveAll: true}, false, "abc123", nil); err == nil {
+		t.Fatal("Expected an error when the endpoints cannot be updated")
+	}
+
+	dir, err := endpointsBackupDir("web", "default")
+	if err != nil {
+		t.Fatalf("Failed to get backup directory: %v", err)
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Error("Expected no backup to be left behind")
+	}
+}
+
+func TestDropEndpointsBackupNewestHandsOnApplied(t *testing.T) {
+	// Disable color for testing
+	originalNoColor := color.NoColor
+	color.NoColor = true
+	defer func() {
+		color.NoColor = originalNoColor
+	}()
+
+	// Keep backups out of the real home directory
+	t.Setenv("HOME", t.TempDir())
+
+	service, endpoints := misrouteFixture()
+	client := fake.NewSimpleClientset(service, endpoints)
+
+	// Misroute the service away from everything, then to a decoy pod
+	firstPath, err := MisrouteService(client, "web", "default", MisrouteOptions{RemoveAll: true}, false, "first", nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	client.CoreV1().Pods("default").Create(context.TODO(), &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "decoy", Namespace: "default", Labels: map[string]string{"app": "decoy"}},
+		Status:     corev1.PodStatus{Phase: corev1.PodRunning, PodIP: "10.0.0.9"},
+	}, metav1.CreateOptions{})
+	secondPath, err := MisrouteService(client, "web", "default", MisrouteOptions{ReplaceSelector: "app=decoy"}, false, "second", nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	second, err := ReadEndpointsBackup(secondPath)
+	if err != nil {
+		t.Fatalf("Failed to read backup: %v", err)
+	}
+
+	if second.Applied == EndpointsChecksum(&corev1.Endpoints{}) {
+		t.Fatal("Expected the second misroute to leave different endpoints behind")
+	}
+
+	// Dropping the newest hands what it wrote on to the one before
+	if err := DropEndpointsBackup(secondPath); err != nil {
+		t.Fatalf("Failed to drop backup: %v", err)
+	}
+	first, err := ReadEndpointsBackup(firstPath)
+	if err != nil {
+		t.Fatalf("Failed to read backup: %v", err)
+	}
+	if first.Applied != second.Applied {
+		t.Errorf("Expected the remaining backup to inherit the newest checksum %q, got %q", second.Applied, first.Applied)
+	}
+}
@@ -0,0 +1,180 @@
+package chaos
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"github.com/isurusiri/tipsy/internal/utils"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// listServiceSlices lists the EndpointSlices of a service, split into those tipsy created and
+// the rest, which belong to the EndpointSlice controllers or whoever else manages them
+// A cluster without the discovery.k8s.io/v1 API has no slices
+func listServiceSlices(client kubernetes.Interface, svcName, namespace string) (tipsy, managed []discoveryv1.EndpointSlice, err error) {
+	slices, err := client.DiscoveryV1().EndpointSlices(namespace).List(context.TODO(), metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", discoveryv1.LabelServiceName, svcName),
+	})
+	if apierrors.IsNotFound(err) {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list endpointslices for service '%s': %w", svcName, err)
+	}
+
+	for _, slice := range slices.Items {
+		if slice.Labels[discoveryv1.LabelManagedBy] == ManagedByValue {
+			tipsy = append(tipsy, slice)
+		} else {
+			managed = append(managed, slice)
+		}
+	}
+	return tipsy, managed, nil
+}
+
+// misrouteEndpointSlices detaches a service's EndpointSlices and replaces them with
+// tipsy-owned slices carrying the given subsets, since kube-proxy and most dataplanes
+// route by slices rather than by the legacy Endpoints
+// Slices left by an earlier misroute of the service are replaced too
+func misrouteEndpointSlices(client kubernetes.Interface, svcName, namespace, actionID string, subsets []corev1.EndpointSubset) error {
+	tipsy, managed, err := listServiceSlices(client, svcName, namespace)
+	if err != nil {
+		return err
+	}
+
+	for _, slice := range append(tipsy, managed...) {
+		err := client.DiscoveryV1().EndpointSlices(namespace).Delete(context.TODO(), slice.Name, metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to detach endpointslice '%s': %w", slice.Name, err)
+		}
+	}
+
+	for i, slice := range buildEndpointSlices(svcName, namespace, actionID, subsets) {
+		if _, err := client.DiscoveryV1().EndpointSlices(namespace).Create(context.TODO(), &slice, metav1.CreateOptions{}); err != nil {
+			return fmt.Errorf("failed to create endpointslice %d for service '%s': %w", i, svcName, err)
+		}
+	}
+
+	utils.Info(fmt.Sprintf("Replaced %d endpointslice(s) of service '%s' with tipsy-owned ones", len(tipsy)+len(managed), svcName))
+	return nil
+}
+
+// RestoreEndpointSlices removes the tipsy-owned slices of a backed up service and recreates
+// the slices the backup detached
+// Slices are only recreated if no others have appeared meanwhile: for a service with a
+// selector the EndpointSlice controller recreates its own, which must not be duplicated
+func RestoreEndpointSlices(client kubernetes.Interface, backup *EndpointsBackup) error {
+	tipsy, managed, err := listServiceSlices(client, backup.Service, backup.Namespace)
+	if err != nil {
+		return err
+	}
+
+	for _, slice := range tipsy {
+		err := client.DiscoveryV1().EndpointSlices(backup.Namespace).Delete(context.TODO(), slice.Name, metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete endpointslice '%s': %w", slice.Name, err)
+		}
+	}
+
+	if len(managed) > 0 {
+		if len(backup.Slices) > 0 {
+			utils.Info(fmt.Sprintf("Service '%s' already has %d endpointslice(s) again; not recreating the backed up ones", backup.Service, len(managed)))
+		}
+		return nil
+	}
+
+	for _, original := range backup.Slices {
+		slice := original.DeepCopy()
+		slice.ResourceVersion = ""
+		slice.UID = ""
+		_, err := client.DiscoveryV1().EndpointSlices(backup.Namespace).Create(context.TODO(), slice, metav1.CreateOptions{})
+		if err != nil && !apierrors.IsAlreadyExists(err) {
+			return fmt.Errorf("failed to recreate endpointslice '%s': %w", slice.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// buildEndpointSlices converts endpoint subsets into tipsy-owned EndpointSlices, one per
+// subset and address family
+func buildEndpointSlices(svcName, namespace, actionID string, subsets []corev1.EndpointSubset) []discoveryv1.EndpointSlice {
+	slices := []discoveryv1.EndpointSlice{}
+
+	for _, subset := range subsets {
+		ports := []discoveryv1.EndpointPort{}
+		for _, port := range subset.Ports {
+			ports = append(ports, discoveryv1.EndpointPort{
+				Name:        &port.Name,
+				Port:        &port.Port,
+				Protocol:    &port.Protocol,
+				AppProtocol: port.AppProtocol,
+			})
+		}
+
+		byFamily := map[discoveryv1.AddressType][]discoveryv1.Endpoint{}
+		for _, address := range subset.Addresses {
+			byFamily[addressType(address.IP)] = append(byFamily[addressType(address.IP)], sliceEndpoint(address, true))
+		}
+		for _, address := range subset.NotReadyAddresses {
+			byFamily[addressType(address.IP)] = append(byFamily[addressType(address.IP)], sliceEndpoint(address, false))
+		}
+
+		// Sorted so slice names are stable across runs
+		for _, family := range []discoveryv1.AddressType{discoveryv1.AddressTypeIPv4, discoveryv1.AddressTypeIPv6} {
+			endpoints, exists := byFamily[family]
+			if !exists {
+				continue
+			}
+
+			slices = append(slices, discoveryv1.EndpointSlice{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      fmt.Sprintf("%s-tipsy-%s-%d", svcName, actionID, len(slices)),
+					Namespace: namespace,
+					Labels: map[string]string{
+						discoveryv1.LabelServiceName: svcName,
+						discoveryv1.LabelManagedBy:   ManagedByValue,
+						ManagedByLabel:               ManagedByValue,
+					},
+				},
+				AddressType: family,
+				Endpoints:   endpoints,
+				Ports:       ports,
+			})
+		}
+	}
+
+	return slices
+}
+
+// sliceEndpoint converts an endpoint address into an EndpointSlice endpoint
+func sliceEndpoint(address corev1.EndpointAddress, ready bool) discoveryv1.Endpoint {
+	return discoveryv1.Endpoint{
+		Addresses:  []string{address.IP},
+		Conditions: discoveryv1.EndpointConditions{Ready: &ready},
+		Hostname:   nonEmpty(address.Hostname),
+		NodeName:   address.NodeName,
+		TargetRef:  address.TargetRef,
+	}
+}
+
+// addressType returns the EndpointSlice address type of an IP
+func addressType(ip string) discoveryv1.AddressType {
+	if parsed := net.ParseIP(ip); parsed != nil && parsed.To4() == nil {
+		return discoveryv1.AddressTypeIPv6
+	}
+	return discoveryv1.AddressTypeIPv4
+}
+
+// nonEmpty returns a pointer to s, or nil if it is empty
+func nonEmpty(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
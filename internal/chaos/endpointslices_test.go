@@ -0,0 +1,164 @@
+package chaos
+
+import (
+	"context"
+	"testing"
+
+	"github.com/fatih/color"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// managedSliceFixture returns an EndpointSlice of the web service as the controller would create it
+func managedSliceFixture(name string) *discoveryv1.EndpointSlice {
+	ready := true
+	return &discoveryv1.EndpointSlice{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+			Labels: map[string]string{
+				discoveryv1.LabelServiceName: "web",
+				discoveryv1.LabelManagedBy:   "endpointslice-controller.k8s.io",
+			},
+		},
+		AddressType: discoveryv1.AddressTypeIPv4,
+		Endpoints:   []discoveryv1.Endpoint{{Addresses: []string{"10.0.0.1"}, Conditions: discoveryv1.EndpointConditions{Ready: &ready}}},
+	}
+}
+
+// listSlices returns the names of the web service's slices and the addresses they hold
+func listSlices(t *testing.T, client *fake.Clientset) map[string][]string {
+	t.Helper()
+
+	slices, err := client.DiscoveryV1().EndpointSlices("default").List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("Failed to list endpointslices: %v", err)
+	}
+
+	found := map[string][]string{}
+	for _, slice := range slices.Items {
+		for _, endpoint := range slice.Endpoints {
+			found[slice.Name] = append(found[slice.Name], endpoint.Addresses...)
+		}
+		if _, exists := found[slice.Name]; !exists {
+			found[slice.Name] = []string{}
+		}
+	}
+	return found
+}
+
+func TestMisrouteServiceReplacesEndpointSlices(t *testing.T) {
+	// Disable color for testing
+	originalNoColor := color.NoColor
+	color.NoColor = true
+	defer func() {
+		color.NoColor = originalNoColor
+	}()
+
+	// Keep backups out of the real home directory
+	t.Setenv("HOME", t.TempDir())
+
+	service, endpoints := misrouteFixture()
+	decoy := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "decoy", Namespace: "default", Labels: map[string]string{"app": "decoy"}},
+		Status:     corev1.PodStatus{Phase: corev1.PodRunning, PodIP: "10.0.0.9"},
+	}
+	client := fake.NewSimpleClientset(service, endpoints, decoy, managedSliceFixture("web-abcde"))
+
+	backupPath, err := MisrouteService(client, "web", "default", MisrouteOptions{ReplaceSelector: "app=decoy"}, false, "abc123", nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// The controller's slice is detached and a tipsy-owned one routes to the decoy
+	slices := listSlices(t, client)
+	if _, exists := slices["web-abcde"]; exists {
+		t.Error("Expected the managed endpointslice to be detached")
+	}
+	if addresses := slices["web-tipsy-abc123-0"]; len(addresses) != 1 || addresses[0] != "10.0.0.9" {
+		t.Errorf("Expected a tipsy-owned endpointslice routing to the decoy, got %v", slices)
+	}
+
+	backup, err := ReadEndpointsBackup(backupPath)
+	if err != nil {
+		t.Fatalf("Failed to read backup: %v", err)
+	}
+	if len(backup.Slices) != 1 || backup.Slices[0].Name != "web-abcde" {
+		t.Fatalf("Expected the detached endpointslice in the backup, got %+v", backup.Slices)
+	}
+
+	// Restoring removes the tipsy-owned slice and brings back the original
+	if err := RestoreEndpointSlices(client, backup); err != nil {
+		t.Fatalf("Failed to restore endpointslices: %v", err)
+	}
+	slices = listSlices(t, client)
+	if len(slices) != 1 || len(slices["web-abcde"]) != 1 {
+		t.Errorf("Expected only the original endpointslice, got %v", slices)
+	}
+}
+
+func TestRestoreEndpointSlicesLeavesRecreatedSlices(t *testing.T) {
+	// Disable color for testing
+	originalNoColor := color.NoColor
+	color.NoColor = true
+	defer func() {
+		color.NoColor = originalNoColor
+	}()
+
+	// Keep backups out of the real home directory
+	t.Setenv("HOME", t.TempDir())
+
+	service, endpoints := misrouteFixture()
+	client := fake.NewSimpleClientset(service, endpoints, managedSliceFixture("web-abcde"))
+
+	backupPath, err := MisrouteService(client, "web", "default", MisrouteOptions{RemoveAll: true}, false, "abc123", nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	backup, err := ReadEndpointsBackup(backupPath)
+	if err != nil {
+		t.Fatalf("Failed to read backup: %v", err)
+	}
+
+	// The controller reconciles the service and creates a slice of its own
+	if _, err := client.DiscoveryV1().EndpointSlices("default").Create(context.TODO(), managedSliceFixture("web-fghij"), metav1.CreateOptions{}); err != nil {
+		t.Fatalf("Failed to create endpointslice: %v", err)
+	}
+
+	if err := RestoreEndpointSlices(client, backup); err != nil {
+		t.Fatalf("Failed to restore endpointslices: %v", err)
+	}
+	slices := listSlices(t, client)
+	if len(slices) != 1 || slices["web-fghij"] == nil {
+		t.Errorf("Expected only the controller's new endpointslice, got %v", slices)
+	}
+}
+
+func TestBuildEndpointSlicesSplitsAddressFamilies(t *testing.T) {
+	subsets := []corev1.EndpointSubset{{
+		Addresses:         []corev1.EndpointAddress{{IP: "10.0.0.1"}, {IP: "fd00::1"}},
+		NotReadyAddresses: []corev1.EndpointAddress{{IP: "10.0.0.2"}},
+		Ports:             []corev1.EndpointPort{{Name: "http", Port: 8080, Protocol: corev1.ProtocolTCP}},
+	}}
+
+	slices := buildEndpointSlices("web", "default", "abc123", subsets)
+	if len(slices) != 2 {
+		t.Fatalf("Expected one slice per address family, got %d", len(slices))
+	}
+
+	ipv4 := slices[0]
+	if ipv4.AddressType != discoveryv1.AddressTypeIPv4 || len(ipv4.Endpoints) != 2 {
+		t.Fatalf("Expected two IPv4 endpoints first, got %+v", ipv4)
+	}
+	if !*ipv4.Endpoints[0].Conditions.Ready || *ipv4.Endpoints[1].Conditions.Ready {
+		t.Error("Expected readiness to carry over from the subset")
+	}
+	if slices[1].AddressType != discoveryv1.AddressTypeIPv6 || slices[1].Endpoints[0].Addresses[0] != "fd00::1" {
+		t.Errorf("Expected the IPv6 endpoint in a slice of its own, got %+v", slices[1])
+	}
+	if *ipv4.Ports[0].Port != 8080 || ipv4.Labels[discoveryv1.LabelManagedBy] != ManagedByValue {
+		t.Errorf("Unexpected slice: %+v", ipv4)
+	}
+}
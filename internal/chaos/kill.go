@@ -14,60 +14,70 @@ import (
 // KillPods deletes pods based on a label selector
 // Returns the list of pod names that were killed
 func KillPods(client kubernetes.Interface, namespace, selector string, count int, dryRun bool) ([]string, error) {
-	utils.Info(fmt.Sprintf("Searching for pods with selector '%s' in namespace '%s'", selector, namespace))
+	killedPods, err := KillPodsForTarget(client, PodTarget{Namespace: namespace, Selector: selector}, count, dryRun)
+	if err != nil {
+		return nil, err
+	}
+	return PodNames(killedPods), nil
+}
+
+// KillPodsForTarget deletes pods matched by a target
+// Returns the pods that were killed
+func KillPodsForTarget(client kubernetes.Interface, target PodTarget, count int, dryRun bool) ([]PodRef, error) {
+	namespace := target.Namespace
+	utils.Info(fmt.Sprintf("Searching for %s", target))
 
 	// In dry-run mode, simulate the operation without making API calls
 	if dryRun {
-		utils.DryRun(fmt.Sprintf("Would search for pods with selector '%s' in namespace '%s'", selector, namespace))
+		utils.DryRun(fmt.Sprintf("Would search for %s", target))
 		utils.DryRun(fmt.Sprintf("Would randomly select %d pod(s) to delete", count))
-		utils.DryRun(fmt.Sprintf("Would delete %d pod(s) matching selector '%s'", count, selector))
+		utils.DryRun(fmt.Sprintf("Would delete %d of the %s", count, target))
 		// Return empty slice for dry-run as we can't determine actual pod names
-		return []string{}, nil
+		return []PodRef{}, nil
 	}
 
-	// List pods matching the selector
-	pods, err := client.CoreV1().Pods(namespace).List(context.TODO(), metav1.ListOptions{
-		LabelSelector: selector,
-	})
+	// List pods matching the target
+	pods, err := SelectPods(client, target)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list pods: %w", err)
+		return nil, err
 	}
 
-	if len(pods.Items) == 0 {
-		utils.Warn(fmt.Sprintf("No pods found matching selector '%s' in namespace '%s'", selector, namespace))
-		return []string{}, nil
+	if len(pods) == 0 {
+		utils.Warn(fmt.Sprintf("No pods found for %s", target))
+		return []PodRef{}, nil
 	}
 
-	utils.Info(fmt.Sprintf("Found %d pod(s) matching selector", len(pods.Items)))
+	utils.Info(fmt.Sprintf("Found %d pod(s) matching selector", len(pods)))
 
 	// Determine how many pods to kill
 	podsToKill := count
 	if count <= 0 {
 		podsToKill = 0
 		utils.Warn(fmt.Sprintf("Invalid count %d, no pods will be deleted", count))
-	} else if len(pods.Items) < count {
-		podsToKill = len(pods.Items)
-		utils.Warn(fmt.Sprintf("Only %d pods available, limiting kill count to %d", len(pods.Items), podsToKill))
+	} else if len(pods) < count {
+		podsToKill = len(pods)
+		utils.Warn(fmt.Sprintf("Only %d pods available, limiting kill count to %d", len(pods), podsToKill))
 	}
 
 	// Randomly select pods to kill
 	rand.Seed(time.Now().UnixNano())
-	selectedPods := make([]string, 0, podsToKill)
+	selectedPods := make([]PodRef, 0, podsToKill)
 	usedIndices := make(map[int]bool)
 
 	for len(selectedPods) < podsToKill {
-		index := rand.Intn(len(pods.Items))
+		index := rand.Intn(len(pods))
 		if !usedIndices[index] {
 			usedIndices[index] = true
-			selectedPods = append(selectedPods, pods.Items[index].Name)
+			selectedPods = append(selectedPods, newPodRef(pods[index]))
 		}
 	}
 
-	var killedPods []string
+	var killedPods []PodRef
 
 	// Execute the kill operation
 	utils.Info(fmt.Sprintf("Deleting %d pod(s):", len(selectedPods)))
-	for _, podName := range selectedPods {
+	for _, ref := range selectedPods {
+		podName := ref.Name
 		utils.Info(fmt.Sprintf("  Deleting pod: %s", podName))
 		
 		err := client.CoreV1().Pods(namespace).Delete(context.TODO(), podName, metav1.DeleteOptions{})
@@ -76,7 +86,7 @@ func KillPods(client kubernetes.Interface, namespace, selector string, count int
 			// Continue with other pods even if one fails
 		} else {
 			utils.Info(fmt.Sprintf("  Successfully deleted pod: %s", podName))
-			killedPods = append(killedPods, podName)
+			killedPods = append(killedPods, ref)
 		}
 	}
 
@@ -0,0 +1,89 @@
+package chaos
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/isurusiri/tipsy/internal/utils"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// DefaultLeaderTimeout is how long to wait for a new Lease holder after killing the leader
+const DefaultLeaderTimeout = 2 * time.Minute
+
+// leaderPollInterval is how often the Lease is checked while waiting for a new holder
+var leaderPollInterval = 500 * time.Millisecond
+
+// ParseLeaseRef splits a Lease reference of the form namespace/name
+func ParseLeaseRef(ref string) (string, string, error) {
+	namespace, name, found := strings.Cut(ref, "/")
+	if !found || namespace == "" || name == "" {
+		return "", "", fmt.Errorf("invalid lease '%s': expected namespace/name", ref)
+	}
+	return namespace, name, nil
+}
+
+// GetLeaseHolder returns the holderIdentity of a Lease, or "" if nobody holds it
+func GetLeaseHolder(client kubernetes.Interface, ref string) (string, error) {
+	namespace, name, err := ParseLeaseRef(ref)
+	if err != nil {
+		return "", err
+	}
+
+	lease, err := client.CoordinationV1().Leases(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to get lease '%s': %w", ref, err)
+	}
+
+	if lease.Spec.HolderIdentity == nil {
+		return "", nil
+	}
+	return *lease.Spec.HolderIdentity, nil
+}
+
+// ResolveLeaderPod returns the name of the pod holding a Lease
+// client-go leader election uses identities of the form <hostname>_<uuid>, and a pod's
+// hostname is its name; pod names cannot contain '_', so anything after it is dropped
+func ResolveLeaderPod(client kubernetes.Interface, ref string) (string, error) {
+	holder, err := GetLeaseHolder(client, ref)
+	if err != nil {
+		return "", err
+	}
+
+	if holder == "" {
+		return "", fmt.Errorf("lease '%s' has no holder", ref)
+	}
+
+	podName, _, _ := strings.Cut(holder, "_")
+	utils.Info(fmt.Sprintf("Lease '%s' is held by '%s' (pod '%s')", ref, holder, podName))
+	return podName, nil
+}
+
+// WaitForNewLeader polls a Lease until someone other than previousHolder holds it
+// Returns the new holder and how long the handover took, measured from start
+func WaitForNewLeader(client kubernetes.Interface, ref, previousHolder string, start time.Time, timeout time.Duration) (string, time.Duration, error) {
+	utils.Info(fmt.Sprintf("Waiting up to %s for a new holder of Lease '%s'", timeout, ref))
+
+	deadline := start.Add(timeout)
+	for {
+		holder, err := GetLeaseHolder(client, ref)
+		if err != nil {
+			return "", 0, err
+		}
+
+		if holder != "" && holder != previousHolder {
+			elapsed := time.Since(start)
+			utils.Info(fmt.Sprintf("New leader '%s' acquired Lease '%s' after %s", holder, ref, elapsed.Round(time.Millisecond)))
+			return holder, elapsed, nil
+		}
+
+		if time.Now().After(deadline) {
+			return "", 0, fmt.Errorf("no new holder of lease '%s' after %s", ref, timeout)
+		}
+
+		time.Sleep(leaderPollInterval)
+	}
+}
@@ -0,0 +1,122 @@
+package chaos
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/fatih/color"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// Helper function to create a Lease held by the given identity
+func createTestLease(namespace, name, holder string) *coordinationv1.Lease {
+	lease := &coordinationv1.Lease{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+	if holder != "" {
+		lease.Spec.HolderIdentity = &holder
+	}
+	return lease
+}
+
+func TestParseLeaseRef(t *testing.T) {
+	namespace, name, err := ParseLeaseRef("kube-system/my-controller")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if namespace != "kube-system" || name != "my-controller" {
+		t.Errorf("Expected kube-system/my-controller, got %s/%s", namespace, name)
+	}
+
+	for _, invalid := range []string{"my-controller", "/my-controller", "kube-system/"} {
+		if _, _, err := ParseLeaseRef(invalid); err == nil {
+			t.Errorf("Expected error for '%s'", invalid)
+		}
+	}
+}
+
+func TestResolveLeaderPod(t *testing.T) {
+	// Disable color for testing
+	originalNoColor := color.NoColor
+	color.NoColor = true
+	defer func() {
+		color.NoColor = originalNoColor
+	}()
+
+	testCases := []struct {
+		name        string
+		holder      string
+		expected    string
+		expectError bool
+	}{
+		{name: "client-go identity", holder: "controller-7d9f-abcde_0f3c2a1b-1111-2222-3333-444455556666", expected: "controller-7d9f-abcde"},
+		{name: "plain pod name", holder: "controller-0", expected: "controller-0"},
+		{name: "no holder", holder: "", expectError: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := fake.NewSimpleClientset(createTestLease("default", "my-controller", tc.holder))
+
+			podName, err := ResolveLeaderPod(client, "default/my-controller")
+			if tc.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if podName != tc.expected {
+				t.Errorf("Expected pod '%s', got '%s'", tc.expected, podName)
+			}
+		})
+	}
+
+	// Test missing lease
+	client := fake.NewSimpleClientset()
+	if _, err := ResolveLeaderPod(client, "default/missing"); err == nil {
+		t.Error("Expected error for missing lease")
+	}
+}
+
+func TestWaitForNewLeader(t *testing.T) {
+	// Disable color for testing
+	originalNoColor := color.NoColor
+	color.NoColor = true
+	defer func() {
+		color.NoColor = originalNoColor
+	}()
+
+	originalInterval := leaderPollInterval
+	leaderPollInterval = time.Millisecond
+	defer func() {
+		leaderPollInterval = originalInterval
+	}()
+
+	client := fake.NewSimpleClientset(createTestLease("default", "my-controller", "controller-0"))
+
+	// Nobody takes over the lease
+	if _, _, err := WaitForNewLeader(client, "default/my-controller", "controller-0", time.Now(), 20*time.Millisecond); err == nil {
+		t.Error("Expected timeout when the lease keeps its holder")
+	}
+
+	// Another replica takes over
+	newHolder := "controller-1"
+	lease, _ := client.CoordinationV1().Leases("default").Get(context.TODO(), "my-controller", metav1.GetOptions{})
+	lease.Spec.HolderIdentity = &newHolder
+	client.CoordinationV1().Leases("default").Update(context.TODO(), lease, metav1.UpdateOptions{})
+
+	holder, elapsed, err := WaitForNewLeader(client, "default/my-controller", "controller-0", time.Now(), time.Second)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if holder != "controller-1" {
+		t.Errorf("Expected new holder 'controller-1', got '%s'", holder)
+	}
+	if elapsed < 0 || elapsed > time.Second {
+		t.Errorf("Unexpected failover time: %s", elapsed)
+	}
+}
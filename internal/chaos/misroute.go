@@ -2,34 +2,57 @@ package chaos
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"os"
-	"path/filepath"
 
+	"github.com/isurusiri/tipsy/internal/state"
 	"github.com/isurusiri/tipsy/internal/utils"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/kubernetes"
 )
 
+// MisrouteOptions controls how a service's endpoints are misrouted; exactly one way is set
+type MisrouteOptions struct {
+	RemoveAll       bool   // remove every endpoint
+	ReplaceSelector string // replace the endpoints with pods matching this selector
+	RemovePercent   int    // remove this percentage of the ready addresses, rounded up
+	RemoveCount     int    // remove this many ready addresses
+	MixSelector     string // add ready pods matching this selector alongside the ready addresses
+	Weight          int    // percentage of ready addresses the MixSelector pods make up
+}
+
 // MisrouteService manipulates service endpoints to simulate misrouting
-func MisrouteService(client *kubernetes.Clientset, svcName, namespace, replaceSelector string, removeAll, dryRun bool) (string, error) {
+// Both the legacy Endpoints and the EndpointSlices are misrouted: the service's slices are
+// detached and replaced with tipsy-owned ones, since that is what kube-proxy routes by
+// The original endpoints and slices are pushed onto the service's backup stack under actionID,
+// see EndpointsBackup
+// Returns the path of the backup
+func MisrouteService(client kubernetes.Interface, svcName, namespace string, opts MisrouteOptions, dryRun bool, actionID string, cluster *state.ClusterRef) (string, error) {
+
 	utils.Info(fmt.Sprintf("Starting misroute operation for service '%s' in namespace '%s'", svcName, namespace))
 
 	// In dry-run mode, simulate the operation without making API calls
 	if dryRun {
 		utils.DryRun(fmt.Sprintf("Would fetch service '%s' in namespace '%s'", svcName, namespace))
-		utils.DryRun(fmt.Sprintf("Would fetch current endpoints for service '%s'", svcName))
-		utils.DryRun(fmt.Sprintf("Would save original endpoints for rollback"))
+		utils.DryRun(fmt.Sprintf("Would fetch current endpoints and endpointslices for service '%s'", svcName))
+		utils.DryRun(fmt.Sprintf("Would save original endpoints and endpointslices for rollback"))
 		
-		if removeAll {
+		if opts.RemoveAll {
 			utils.DryRun(fmt.Sprintf("Would remove all endpoint subsets from service '%s' (no traffic routing)", svcName))
-		} else if replaceSelector != "" {
-			utils.DryRun(fmt.Sprintf("Would search for pods with selector '%s' in namespace '%s'", replaceSelector, namespace))
-			utils.DryRun(fmt.Sprintf("Would replace service endpoints with pods matching selector '%s'", replaceSelector))
+		} else if opts.ReplaceSelector != "" {
+			utils.DryRun(fmt.Sprintf("Would search for pods with selector '%s' in namespace '%s'", opts.ReplaceSelector, namespace))
+			utils.DryRun(fmt.Sprintf("Would replace service endpoints with pods matching selector '%s'", opts.ReplaceSelector))
+		} else if opts.RemovePercent > 0 {
+			utils.DryRun(fmt.Sprintf("Would remove %d%% of the ready addresses of service '%s'", opts.RemovePercent, svcName))
+		} else if opts.RemoveCount > 0 {
+			utils.DryRun(fmt.Sprintf("Would remove %d ready address(es) of service '%s'", opts.RemoveCount, svcName))
+		} else if opts.MixSelector != "" {
+			utils.DryRun(fmt.Sprintf("Would search for pods with selector '%s' in namespace '%s'", opts.MixSelector, namespace))
+			utils.DryRun(fmt.Sprintf("Would mix pods matching selector '%s' into service '%s' to take %d%% of its ready addresses", opts.MixSelector, svcName, opts.Weight))
 		}
 		
+		utils.DryRun(fmt.Sprintf("Would replace the endpointslices of service '%s' with tipsy-owned ones", svcName))
 		utils.DryRun(fmt.Sprintf("Would update endpoints for service '%s'", svcName))
 		return "", nil
 	}
@@ -42,40 +65,38 @@ func MisrouteService(client *kubernetes.Clientset, svcName, namespace, replaceSe
 
 	utils.Info(fmt.Sprintf("Found service '%s' with %d ports", svcName, len(service.Spec.Ports)))
 
+	if len(service.Spec.Selector) > 0 {
+		utils.Warn(fmt.Sprintf("Service '%s' has a selector, so the endpoints and EndpointSlice controllers will reconcile its endpoints and may undo the misroute within seconds",
+			svcName))
+	}
+
 	// Fetch the current Endpoints
 	endpoints, err := client.CoreV1().Endpoints(namespace).Get(context.TODO(), svcName, metav1.GetOptions{})
 	if err != nil {
 		return "", fmt.Errorf("failed to get endpoints for service '%s': %w", svcName, err)
 	}
 
-	// Save original endpoints for rollback
-	var backupPath string
-	backupPath, err = saveOriginalEndpoints(endpoints, svcName, namespace)
-	if err != nil {
-		utils.Warn(fmt.Sprintf("Failed to save original endpoints for rollback: %v", err))
-	}
-
 	// Create a copy of the endpoints to modify
 	modifiedEndpoints := endpoints.DeepCopy()
 
-	if removeAll {
+	if opts.RemoveAll {
 		// Remove all endpoint subsets
 		utils.Info("Removing all endpoint subsets")
 		modifiedEndpoints.Subsets = []corev1.EndpointSubset{}
-	} else if replaceSelector != "" {
+	} else if opts.ReplaceSelector != "" {
 		// Replace with pods matching the selector
-		utils.Info(fmt.Sprintf("Replacing endpoints with pods matching selector '%s'", replaceSelector))
+		utils.Info(fmt.Sprintf("Replacing endpoints with pods matching selector '%s'", opts.ReplaceSelector))
 		
 		// List pods matching the selector
 		pods, err := client.CoreV1().Pods(namespace).List(context.TODO(), metav1.ListOptions{
-			LabelSelector: replaceSelector,
+			LabelSelector: opts.ReplaceSelector,
 		})
 		if err != nil {
-			return "", fmt.Errorf("failed to list pods with selector '%s': %w", replaceSelector, err)
+			return "", fmt.Errorf("failed to list pods with selector '%s': %w", opts.ReplaceSelector, err)
 		}
 
 		if len(pods.Items) == 0 {
-			utils.Warn(fmt.Sprintf("No pods found matching selector '%s' in namespace '%s'", replaceSelector, namespace))
+			utils.Warn(fmt.Sprintf("No pods found matching selector '%s' in namespace '%s'", opts.ReplaceSelector, namespace))
 			// Set empty subsets to effectively remove all endpoints
 			modifiedEndpoints.Subsets = []corev1.EndpointSubset{}
 		} else {
@@ -89,12 +110,80 @@ func MisrouteService(client *kubernetes.Clientset, svcName, namespace, replaceSe
 			
 			modifiedEndpoints.Subsets = newSubsets
 		}
+	} else if opts.RemovePercent > 0 || opts.RemoveCount > 0 {
+		// Remove some of the ready addresses
+		count := opts.RemoveCount
+		if opts.RemovePercent > 0 {
+			count = percentOf(countReady(endpoints.Subsets), opts.RemovePercent)
+		}
+		var removed int
+		modifiedEndpoints.Subsets, removed = removeReadyAddresses(endpoints.Subsets, count)
+		if removed < count {
+			utils.Warn(fmt.Sprintf("Service '%s' has only %d ready address(es); removing all of them", svcName, removed))
+		}
+		utils.Info(fmt.Sprintf("Removing %d of %d ready address(es)", removed, countReady(endpoints.Subsets)))
+	} else if opts.MixSelector != "" {
+		// Mix pods matching the selector in with the ready addresses
+		utils.Info(fmt.Sprintf("Mixing pods matching selector '%s' into the endpoints", opts.MixSelector))
+
+		pods, err := client.CoreV1().Pods(namespace).List(context.TODO(), metav1.ListOptions{
+			LabelSelector: opts.MixSelector,
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to list pods with selector '%s': %w", opts.MixSelector, err)
+		}
+
+		mixSubsets, err := buildEndpointSubsetsFromPods(pods.Items, service.Spec.Ports)
+		if err != nil {
+			return "", fmt.Errorf("failed to build endpoint subsets from pods: %w", err)
+		}
+
+		mixed, weight, err := mixEndpointSubsets(endpoints.Subsets, mixSubsets, opts.Weight)
+		if err != nil {
+			return "", fmt.Errorf("failed to mix pods matching selector '%s' into service '%s': %w", opts.MixSelector, svcName, err)
+		}
+		if weight != opts.Weight {
+			utils.Warn(fmt.Sprintf("Mixed-in pods make up %d%% of the ready addresses rather than %d%%, the closest the address counts allow", weight, opts.Weight))
+		}
+		modifiedEndpoints.Subsets = mixed
+	}
+
+	// Fetch the slices to detach; those left by an earlier misroute are already in its backup
+	_, managedSlices, err := listServiceSlices(client, svcName, namespace)
+	if err != nil {
+		return "", err
+	}
+
+	// Refuse to continue without a backup, since the original endpoints could not be restored
+	backupPath, err := saveEndpointsBackup(endpoints, managedSlices, svcName, namespace, actionID, cluster)
+	if err != nil {
+		return "", fmt.Errorf("failed to save original endpoints for rollback: %w", err)
 	}
 
-	// Update the endpoints
-	_, err = client.CoreV1().Endpoints(namespace).Update(context.TODO(), modifiedEndpoints, metav1.UpdateOptions{})
+	// Replace the slices, then update the endpoints
+	err = misrouteEndpointSlices(client, svcName, namespace, actionID, modifiedEndpoints.Subsets)
+	var updated *corev1.Endpoints
+	if err == nil {
+		updated, err = client.CoreV1().Endpoints(namespace).Update(context.TODO(), modifiedEndpoints, metav1.UpdateOptions{})
+		if err != nil {
+			err = fmt.Errorf("failed to update endpoints: %w", err)
+		}
+	}
 	if err != nil {
-		return "", fmt.Errorf("failed to update endpoints: %w", err)
+		// Put back any slices already detached; the backup would then only confuse a later rollback
+		if backup, readErr := ReadEndpointsBackup(backupPath); readErr == nil {
+			if restoreErr := RestoreEndpointSlices(client, backup); restoreErr != nil {
+				utils.Warn(fmt.Sprintf("Failed to restore endpointslices, original kept in %s: %v", backupPath, restoreErr))
+				return "", err
+			}
+		}
+		RemoveEndpointsBackup(backupPath)
+		return "", err
+	}
+
+	// Remember what was written, as stored by the API server, so rollback can spot later changes
+	if err := recordAppliedEndpoints(backupPath, updated); err != nil {
+		utils.Warn(fmt.Sprintf("Failed to record the misrouted endpoints; rollback will not be able to detect later changes: %v", err))
 	}
 
 	utils.Info("Successfully updated service endpoints")
@@ -102,20 +191,51 @@ func MisrouteService(client *kubernetes.Clientset, svcName, namespace, replaceSe
 }
 
 // buildEndpointSubsetsFromPods creates endpoint subsets from pod information
+// Each service port's targetPort is resolved against every pod, as the endpoints controller
+// does: a named targetPort can map to a different number on each pod, so pods are grouped into
+// one subset per set of resolved ports. A pod that does not expose a named targetPort gets no
+// endpoint for that port, and is reported
+// Returns an error if running pods were found but none of them expose any of the service's ports
 func buildEndpointSubsetsFromPods(pods []corev1.Pod, servicePorts []corev1.ServicePort) ([]corev1.EndpointSubset, error) {
 	if len(servicePorts) == 0 {
 		return []corev1.EndpointSubset{}, nil
 	}
 
-	// Group pods by their readiness
-	readyAddresses := []corev1.EndpointAddress{}
-	notReadyAddresses := []corev1.EndpointAddress{}
+	// Pods are grouped by their resolved ports, in the order the groups are first seen
+	type portGroup struct {
+		ports             []corev1.EndpointPort
+		readyAddresses    []corev1.EndpointAddress
+		notReadyAddresses []corev1.EndpointAddress
+	}
+	groups := map[string]*portGroup{}
+	order := []string{}
+	eligible := 0
 
 	for _, pod := range pods {
 		// Skip pods that are not running or have no IP
 		if pod.Status.Phase != corev1.PodRunning || pod.Status.PodIP == "" {
 			continue
 		}
+		eligible++
+
+		// Resolve each service port against the pod's containers
+		ports := []corev1.EndpointPort{}
+		for _, servicePort := range servicePorts {
+			port, err := resolveTargetPort(pod, servicePort)
+			if err != nil {
+				utils.Warn(fmt.Sprintf("Pod '%s' gets no endpoint for service port '%s': %v", pod.Name, servicePortName(servicePort), err))
+				continue
+			}
+			ports = append(ports, corev1.EndpointPort{
+				Name:        servicePort.Name,
+				Port:        port,
+				Protocol:    servicePort.Protocol,
+				AppProtocol: servicePort.AppProtocol,
+			})
+		}
+		if len(ports) == 0 {
+			continue
+		}
 
 		address := corev1.EndpointAddress{
 			IP: pod.Status.PodIP,
@@ -127,6 +247,14 @@ func buildEndpointSubsetsFromPods(pods []corev1.Pod, servicePorts []corev1.Servi
 			},
 		}
 
+		key := fmt.Sprintf("%v", ports)
+		group, exists := groups[key]
+		if !exists {
+			group = &portGroup{ports: ports}
+			groups[key] = group
+			order = append(order, key)
+		}
+
 		// Check if pod is ready
 		isReady := false
 		for _, condition := range pod.Status.Conditions {
@@ -137,75 +265,97 @@ func buildEndpointSubsetsFromPods(pods []corev1.Pod, servicePorts []corev1.Servi
 		}
 
 		if isReady {
-			readyAddresses = append(readyAddresses, address)
+			group.readyAddresses = append(group.readyAddresses, address)
 		} else {
-			notReadyAddresses = append(notReadyAddresses, address)
+			group.notReadyAddresses = append(group.notReadyAddresses, address)
 		}
 	}
 
-	// Build ports from service ports
-	ports := []corev1.EndpointPort{}
-	for _, servicePort := range servicePorts {
-		port := corev1.EndpointPort{
-			Name:     servicePort.Name,
-			Port:     servicePort.Port,
-			Protocol: servicePort.Protocol,
-		}
-		ports = append(ports, port)
+	if eligible > 0 && len(groups) == 0 {
+		return nil, fmt.Errorf("none of the %d running pod(s) expose the service's target ports", eligible)
 	}
 
 	// Create subsets
 	subsets := []corev1.EndpointSubset{}
 
-	// Add ready addresses subset if any
-	if len(readyAddresses) > 0 {
-		subsets = append(subsets, corev1.EndpointSubset{
-			Addresses: readyAddresses,
-			Ports:     ports,
-		})
-	}
+	for _, key := range order {
+		group := groups[key]
 
-	// Add not ready addresses subset if any
-	if len(notReadyAddresses) > 0 {
-		subsets = append(subsets, corev1.EndpointSubset{
-			NotReadyAddresses: notReadyAddresses,
-			Ports:             ports,
-		})
+		// Add ready addresses subset if any
+		if len(group.readyAddresses) > 0 {
+			subsets = append(subsets, corev1.EndpointSubset{
+				Addresses: group.readyAddresses,
+				Ports:     group.ports,
+			})
+		}
+
+		// Add not ready addresses subset if any
+		if len(group.notReadyAddresses) > 0 {
+			subsets = append(subsets, corev1.EndpointSubset{
+				NotReadyAddresses: group.notReadyAddresses,
+				Ports:             group.ports,
+			})
+		}
 	}
 
 	return subsets, nil
 }
 
-// saveOriginalEndpoints saves the original endpoints to disk for rollback
-func saveOriginalEndpoints(endpoints *corev1.Endpoints, svcName, namespace string) (string, error) {
-	// Create rollback directory
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		return "", fmt.Errorf("failed to get user home directory: %w", err)
+// resolveTargetPort works out the port a service port sends traffic to on a pod
+// An unset targetPort means the service port itself, a number is used as it is, and a name is
+// looked up among the pod's container ports of the same protocol
+// A number the pod declares container ports without is still used, since declaring ports is
+// optional, but is reported as the pod may not be listening on it
+func resolveTargetPort(pod corev1.Pod, servicePort corev1.ServicePort) (int32, error) {
+	protocol := servicePort.Protocol
+	if protocol == "" {
+		protocol = corev1.ProtocolTCP
 	}
 
-	rollbackDir := filepath.Join(homeDir, ".tipsy", "rollback")
-	err = os.MkdirAll(rollbackDir, 0755)
-	if err != nil {
-		return "", fmt.Errorf("failed to create rollback directory: %w", err)
+	target := servicePort.TargetPort
+	if target.Type == intstr.String && target.StrVal != "" {
+		for _, container := range pod.Spec.Containers {
+			for _, containerPort := range container.Ports {
+				if containerPort.Name == target.StrVal && containerProtocol(containerPort) == protocol {
+					return containerPort.ContainerPort, nil
+				}
+			}
+		}
+		return 0, fmt.Errorf("no container exposes a %s port named '%s'", protocol, target.StrVal)
 	}
 
-	// Create filename
-	filename := fmt.Sprintf("%s_%s.json", svcName, namespace)
-	filepath := filepath.Join(rollbackDir, filename)
+	port := servicePort.Port
+	if target.Type == intstr.Int && target.IntVal != 0 {
+		port = target.IntVal
+	}
 
-	// Marshal endpoints to JSON
-	data, err := json.MarshalIndent(endpoints, "", "  ")
-	if err != nil {
-		return "", fmt.Errorf("failed to marshal endpoints to JSON: %w", err)
+	declared := false
+	for _, container := range pod.Spec.Containers {
+		for _, containerPort := range container.Ports {
+			if containerPort.ContainerPort == port && containerProtocol(containerPort) == protocol {
+				return port, nil
+			}
+			declared = true
+		}
+	}
+	if declared {
+		utils.Warn(fmt.Sprintf("Pod '%s' does not declare %s container port %d; it may not be listening on it", pod.Name, protocol, port))
 	}
+	return port, nil
+}
 
-	// Write to file
-	err = os.WriteFile(filepath, data, 0644)
-	if err != nil {
-		return "", fmt.Errorf("failed to write endpoints to file: %w", err)
+// containerProtocol returns the protocol of a container port, which defaults to TCP
+func containerProtocol(port corev1.ContainerPort) corev1.Protocol {
+	if port.Protocol == "" {
+		return corev1.ProtocolTCP
 	}
+	return port.Protocol
+}
 
-	utils.Info(fmt.Sprintf("Saved original endpoints to %s for rollback", filepath))
-	return filepath, nil
+// servicePortName names a service port for messages, by name if it has one
+func servicePortName(servicePort corev1.ServicePort) string {
+	if servicePort.Name != "" {
+		return servicePort.Name
+	}
+	return fmt.Sprintf("%d", servicePort.Port)
 }
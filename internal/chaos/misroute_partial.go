@@ -0,0 +1,141 @@
+package chaos
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// countReady returns the number of ready addresses across endpoint subsets
+func countReady(subsets []corev1.EndpointSubset) int {
+	count := 0
+	for _, subset := range subsets {
+		count += len(subset.Addresses)
+	}
+	return count
+}
+
+// percentOf returns percent of total, rounded up so any percentage of a non-empty total is at least one
+func percentOf(total, percent int) int {
+	return (total*percent + 99) / 100
+}
+
+// readyIPs returns the IPs of the ready addresses across endpoint subsets, sorted
+func readyIPs(subsets []corev1.EndpointSubset) []string {
+	ips := []string{}
+	for _, subset := range subsets {
+		for _, address := range subset.Addresses {
+			ips = append(ips, address.IP)
+		}
+	}
+	sort.Strings(ips)
+	return ips
+}
+
+// removeReadyAddresses returns a copy of the subsets without count of their ready addresses,
+// taken in IP order so the same endpoints lose the same addresses every time
+// Subsets left with no addresses at all are dropped
+// Returns the new subsets and the number of addresses removed, which is less than count
+// if there were not that many ready addresses
+func removeReadyAddresses(subsets []corev1.EndpointSubset, count int) ([]corev1.EndpointSubset, int) {
+	ips := readyIPs(subsets)
+	if count > len(ips) {
+		count = len(ips)
+	}
+
+	remove := map[string]bool{}
+	for _, ip := range ips[:count] {
+		remove[ip] = true
+	}
+
+	kept := []corev1.EndpointSubset{}
+	for _, subset := range subsets {
+		subset = *subset.DeepCopy()
+
+		addresses := []corev1.EndpointAddress{}
+		for _, address := range subset.Addresses {
+			if !remove[address.IP] {
+				addresses = append(addresses, address)
+			}
+		}
+		subset.Addresses = addresses
+
+		if len(subset.Addresses) > 0 || len(subset.NotReadyAddresses) > 0 {
+			kept = append(kept, subset)
+		}
+	}
+
+	return kept, count
+}
+
+// mixCounts works out how many of the good addresses to keep and how many of the available
+// mixed-in addresses to add, so the mixed-in ones make up as close to weight percent as the
+// counts allow
+// Every good address is kept unless there are too few mixed-in addresses to reach the weight
+func mixCounts(good, available, weight int) (int, int) {
+	mixed := int(math.Max(1, math.Round(float64(good*weight)/float64(100-weight))))
+	if mixed <= available {
+		return good, mixed
+	}
+
+	keep := int(math.Round(float64(available*(100-weight)) / float64(weight)))
+	return int(math.Min(float64(good), math.Max(1, float64(keep)))), available
+}
+
+// mixEndpointSubsets adds the ready addresses of mixSubsets alongside the ready addresses of
+// subsets, so the mixed-in ones make up weight percent of them, see mixCounts
+// Returns the new subsets and the weight actually reached
+func mixEndpointSubsets(subsets, mixSubsets []corev1.EndpointSubset, weight int) ([]corev1.EndpointSubset, int, error) {
+	good := readyIPs(subsets)
+	if len(good) == 0 {
+		return nil, 0, fmt.Errorf("the service has no ready addresses to mix with")
+	}
+
+	// Pods already behind the service are not mixed in again
+	isGood := map[string]bool{}
+	for _, ip := range good {
+		isGood[ip] = true
+	}
+
+	// Candidates remember their subset, since pods can expose the service's ports on different numbers
+	type candidate struct {
+		address corev1.EndpointAddress
+		subset  int
+	}
+	candidates := []candidate{}
+	for i, subset := range mixSubsets {
+		for _, address := range subset.Addresses {
+			if !isGood[address.IP] {
+				candidates = append(candidates, candidate{address, i})
+			}
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, 0, fmt.Errorf("no ready pods to mix in")
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].address.IP < candidates[j].address.IP
+	})
+
+	keep, mixed := mixCounts(len(good), len(candidates), weight)
+	result, _ := removeReadyAddresses(subsets, len(good)-keep)
+
+	mixedSubsets := map[int]*corev1.EndpointSubset{}
+	order := []int{}
+	for _, c := range candidates[:mixed] {
+		subset, exists := mixedSubsets[c.subset]
+		if !exists {
+			subset = &corev1.EndpointSubset{Ports: mixSubsets[c.subset].Ports}
+			mixedSubsets[c.subset] = subset
+			order = append(order, c.subset)
+		}
+		subset.Addresses = append(subset.Addresses, c.address)
+	}
+	for _, i := range order {
+		result = append(result, *mixedSubsets[i])
+	}
+
+	return result, int(math.Round(float64(100*mixed) / float64(keep+mixed))), nil
+}
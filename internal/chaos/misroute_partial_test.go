@@ -0,0 +1,143 @@
+package chaos
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/fatih/color"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// readySubsets returns a subset with the given number of ready addresses, 10.0.0.1 upwards
+func readySubsets(count int) []corev1.EndpointSubset {
+	subset := corev1.EndpointSubset{
+		NotReadyAddresses: []corev1.EndpointAddress{{IP: "10.0.1.1"}},
+		Ports:             []corev1.EndpointPort{{Name: "http", Port: 8080, Protocol: corev1.ProtocolTCP}},
+	}
+	for i := 1; i <= count; i++ {
+		subset.Addresses = append(subset.Addresses, corev1.EndpointAddress{IP: fmt.Sprintf("10.0.0.%d", i)})
+	}
+	return []corev1.EndpointSubset{subset}
+}
+
+func TestRemoveReadyAddresses(t *testing.T) {
+	subsets := readySubsets(4)
+
+	kept, removed := removeReadyAddresses(subsets, 3)
+	if removed != 3 || countReady(kept) != 1 || kept[0].Addresses[0].IP != "10.0.0.4" {
+		t.Errorf("Expected the first three addresses removed, got %d removed leaving %+v", removed, kept)
+	}
+	if len(kept[0].NotReadyAddresses) != 1 {
+		t.Error("Expected not-ready addresses to be left alone")
+	}
+	if countReady(subsets) != 4 {
+		t.Error("Expected the original subsets to be left unmodified")
+	}
+
+	// Asking for more than there are removes them all
+	kept, removed = removeReadyAddresses(subsets, 10)
+	if removed != 4 || countReady(kept) != 0 {
+		t.Errorf("Expected every ready address removed, got %d removed leaving %+v", removed, kept)
+	}
+}
+
+func TestPercentOf(t *testing.T) {
+	tests := []struct {
+		total, percent, expected int
+	}{
+		{4, 50, 2},
+		{3, 50, 2},
+		{3, 1, 1},
+		{3, 100, 3},
+		{0, 50, 0},
+	}
+
+	for _, tt := range tests {
+		if got := percentOf(tt.total, tt.percent); got != tt.expected {
+			t.Errorf("percentOf(%d, %d) = %d, expected %d", tt.total, tt.percent, got, tt.expected)
+		}
+	}
+}
+
+func TestMixCounts(t *testing.T) {
+	tests := []struct {
+		name                    string
+		good, available, weight int
+		expectedKeep            int
+		expectedMixed           int
+	}{
+		{name: "enough pods to mix in", good: 8, available: 5, weight: 20, expectedKeep: 8, expectedMixed: 2},
+		{name: "at least one mixed in", good: 2, available: 5, weight: 10, expectedKeep: 2, expectedMixed: 1},
+		{name: "too few pods drops good addresses", good: 4, available: 1, weight: 50, expectedKeep: 1, expectedMixed: 1},
+		{name: "at least one good address kept", good: 4, available: 1, weight: 90, expectedKeep: 1, expectedMixed: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keep, mixed := mixCounts(tt.good, tt.available, tt.weight)
+			if keep != tt.expectedKeep || mixed != tt.expectedMixed {
+				t.Errorf("Expected to keep %d and mix in %d, got %d and %d", tt.expectedKeep, tt.expectedMixed, keep, mixed)
+			}
+		})
+	}
+}
+
+func TestMixEndpointSubsets(t *testing.T) {
+	bad := []corev1.EndpointSubset{{
+		Addresses: []corev1.EndpointAddress{{IP: "10.0.9.2"}, {IP: "10.0.9.1"}, {IP: "10.0.0.1"}},
+		Ports:     []corev1.EndpointPort{{Name: "http", Port: 9090, Protocol: corev1.ProtocolTCP}},
+	}}
+
+	mixed, weight, err := mixEndpointSubsets(readySubsets(4), bad, 20)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if weight != 20 || len(mixed) != 2 {
+		t.Fatalf("Expected a 20%% mix in two subsets, got %d%% in %+v", weight, mixed)
+	}
+	if countReady(mixed[:1]) != 4 {
+		t.Errorf("Expected every good address kept, got %+v", mixed[0])
+	}
+	if len(mixed[1].Addresses) != 1 || mixed[1].Addresses[0].IP != "10.0.9.1" || mixed[1].Ports[0].Port != 9090 {
+		t.Errorf("Expected one mixed-in address on the mixed-in pods' ports, got %+v", mixed[1])
+	}
+
+	// Pods already behind the service do not count
+	if _, _, err := mixEndpointSubsets(readySubsets(4), readySubsets(2), 20); err == nil {
+		t.Error("Expected an error when there are no new pods to mix in")
+	}
+	if _, _, err := mixEndpointSubsets(readySubsets(0), bad, 20); err == nil {
+		t.Error("Expected an error when the service has no ready addresses")
+	}
+}
+
+func TestMisrouteServiceRemovePercent(t *testing.T) {
+	// Disable color for testing
+	originalNoColor := color.NoColor
+	color.NoColor = true
+	defer func() {
+		color.NoColor = originalNoColor
+	}()
+
+	// Keep backups out of the real home directory
+	t.Setenv("HOME", t.TempDir())
+
+	service, endpoints := misrouteFixture()
+	endpoints.Subsets = readySubsets(4)
+	client := fake.NewSimpleClientset(service, endpoints)
+
+	if _, err := MisrouteService(client, "web", "default", MisrouteOptions{RemovePercent: 50}, false, "abc123", nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	updated, err := client.CoreV1().Endpoints("default").Get(context.TODO(), "web", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Failed to get endpoints: %v", err)
+	}
+	if countReady(updated.Subsets) != 2 {
+		t.Errorf("Expected half of the ready addresses left, got %+v", updated.Subsets)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/isurusiri/tipsy/internal/config"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 func TestBuildEndpointSubsetsFromPods(t *testing.T) {
@@ -219,3 +220,117 @@ func TestMisrouteService_DryRunComprehensive(t *testing.T) {
 		t.Log("The function should return early when dryRun=true without making API calls")
 	})
 }
+
+// runningPod returns a ready running pod whose single container exposes the given ports
+func runningPod(name, ip string, ports ...corev1.ContainerPort) corev1.Pod {
+	return corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Ports: ports}}},
+		Status: corev1.PodStatus{
+			Phase:      corev1.PodRunning,
+			PodIP:      ip,
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+		},
+	}
+}
+
+func TestBuildEndpointSubsetsFromPodsResolvesTargetPorts(t *testing.T) {
+	// Disable color for testing
+	originalNoColor := color.NoColor
+	color.NoColor = true
+	defer func() {
+		color.NoColor = originalNoColor
+	}()
+
+	servicePorts := []corev1.ServicePort{
+		{Name: "http", Port: 80, Protocol: corev1.ProtocolTCP, TargetPort: intstr.FromString("web")},
+		{Name: "metrics", Port: 9000, Protocol: corev1.ProtocolTCP, TargetPort: intstr.FromInt32(9090)},
+	}
+	pods := []corev1.Pod{
+		runningPod("old", "10.0.0.1", corev1.ContainerPort{Name: "web", ContainerPort: 8080}, corev1.ContainerPort{ContainerPort: 9090}),
+		runningPod("new", "10.0.0.2", corev1.ContainerPort{Name: "web", ContainerPort: 8081}, corev1.ContainerPort{ContainerPort: 9090}),
+		runningPod("also-old", "10.0.0.3", corev1.ContainerPort{Name: "web", ContainerPort: 8080}, corev1.ContainerPort{ContainerPort: 9090}),
+	}
+
+	subsets, err := buildEndpointSubsetsFromPods(pods, servicePorts)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// The named port maps to a different number on one pod, so it gets a subset of its own
+	if len(subsets) != 2 {
+		t.Fatalf("Expected a subset per set of resolved ports, got %+v", subsets)
+	}
+	if len(subsets[0].Addresses) != 2 || subsets[0].Ports[0].Port != 8080 || subsets[0].Ports[1].Port != 9090 {
+		t.Errorf("Expected two pods on ports 8080 and 9090, got %+v", subsets[0])
+	}
+	if len(subsets[1].Addresses) != 1 || subsets[1].Addresses[0].IP != "10.0.0.2" || subsets[1].Ports[0].Port != 8081 {
+		t.Errorf("Expected the other pod on port 8081, got %+v", subsets[1])
+	}
+	if subsets[0].Ports[0].Name != "http" {
+		t.Errorf("Expected endpoint ports to keep the service port name, got %+v", subsets[0].Ports[0])
+	}
+}
+
+func TestBuildEndpointSubsetsFromPodsMissingNamedPort(t *testing.T) {
+	// Disable color for testing
+	originalNoColor := color.NoColor
+	color.NoColor = true
+	defer func() {
+		color.NoColor = originalNoColor
+	}()
+
+	servicePorts := []corev1.ServicePort{
+		{Name: "http", Port: 80, Protocol: corev1.ProtocolTCP, TargetPort: intstr.FromString("web")},
+	}
+	exposing := runningPod("exposing", "10.0.0.1", corev1.ContainerPort{Name: "web", ContainerPort: 8080})
+	missing := runningPod("missing", "10.0.0.2", corev1.ContainerPort{Name: "admin", ContainerPort: 8080})
+	udp := runningPod("udp", "10.0.0.3", corev1.ContainerPort{Name: "web", ContainerPort: 8080, Protocol: corev1.ProtocolUDP})
+
+	// Pods without the port get no endpoint rather than a broken one
+	subsets, err := buildEndpointSubsetsFromPods([]corev1.Pod{exposing, missing, udp}, servicePorts)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(subsets) != 1 || len(subsets[0].Addresses) != 1 || subsets[0].Addresses[0].IP != "10.0.0.1" {
+		t.Errorf("Expected only the pod exposing the port, got %+v", subsets)
+	}
+
+	if _, err := buildEndpointSubsetsFromPods([]corev1.Pod{missing, udp}, servicePorts); err == nil {
+		t.Error("Expected an error when no pod exposes the target port")
+	}
+}
+
+func TestResolveTargetPort(t *testing.T) {
+	// Disable color for testing
+	originalNoColor := color.NoColor
+	color.NoColor = true
+	defer func() {
+		color.NoColor = originalNoColor
+	}()
+
+	pod := runningPod("web", "10.0.0.1", corev1.ContainerPort{Name: "web", ContainerPort: 8080})
+
+	tests := []struct {
+		name       string
+		targetPort intstr.IntOrString
+		expected   int32
+	}{
+		{name: "unset uses the service port", targetPort: intstr.IntOrString{}, expected: 80},
+		{name: "number", targetPort: intstr.FromInt32(8080), expected: 8080},
+		{name: "undeclared number is still used", targetPort: intstr.FromInt32(9999), expected: 9999},
+		{name: "name", targetPort: intstr.FromString("web"), expected: 8080},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			port, err := resolveTargetPort(pod, corev1.ServicePort{Port: 80, TargetPort: tt.targetPort})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if port != tt.expected {
+				t.Errorf("Expected port %d, got %d", tt.expected, port)
+			}
+		})
+	}
+}
@@ -16,37 +16,46 @@ import (
 // InjectLatency injects network latency using tc netem via ephemeral containers
 // Returns the list of pod names that were affected by the latency injection
 func InjectLatency(client kubernetes.Interface, namespace, selector, delay string, duration time.Duration, dryRun bool) ([]string, error) {
-	utils.Info(fmt.Sprintf("Searching for pods with selector '%s' in namespace '%s'", selector, namespace))
+	affectedPods, err := InjectLatencyForTarget(client, PodTarget{Namespace: namespace, Selector: selector}, delay, duration, dryRun)
+	if err != nil {
+		return nil, err
+	}
+	return PodNames(affectedPods), nil
+}
+
+// InjectLatencyForTarget injects latency into the running pods matched by a target
+// Returns the pods that were affected
+func InjectLatencyForTarget(client kubernetes.Interface, target PodTarget, delay string, duration time.Duration, dryRun bool) ([]PodRef, error) {
+	namespace := target.Namespace
+	utils.Info(fmt.Sprintf("Searching for %s", target))
 
 	// In dry-run mode, simulate the operation without making API calls
 	if dryRun {
-		utils.DryRun(fmt.Sprintf("Would search for pods with selector '%s' in namespace '%s'", selector, namespace))
+		utils.DryRun(fmt.Sprintf("Would search for %s", target))
 		utils.DryRun(fmt.Sprintf("Would inject latency to all running pods matching selector"))
 		utils.DryRun(fmt.Sprintf("Would add ephemeral containers with image: ghcr.io/chaos-tools/netem:latest"))
 		utils.DryRun(fmt.Sprintf("Would apply tc netem delay: %s for duration: %s", delay, duration))
 		// Return empty slice for dry-run as we can't determine actual pod names
-		return []string{}, nil
+		return []PodRef{}, nil
 	}
 
-	// List pods matching the selector
-	pods, err := client.CoreV1().Pods(namespace).List(context.TODO(), metav1.ListOptions{
-		LabelSelector: selector,
-	})
+	// List pods matching the target
+	pods, err := SelectPods(client, target)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list pods: %w", err)
+		return nil, err
 	}
 
-	if len(pods.Items) == 0 {
-		utils.Warn(fmt.Sprintf("No pods found matching selector '%s' in namespace '%s'", selector, namespace))
-		return []string{}, nil
+	if len(pods) == 0 {
+		utils.Warn(fmt.Sprintf("No pods found for %s", target))
+		return []PodRef{}, nil
 	}
 
-	utils.Info(fmt.Sprintf("Found %d pod(s) matching selector", len(pods.Items)))
+	utils.Info(fmt.Sprintf("Found %d pod(s) matching selector", len(pods)))
 
-	var affectedPods []string
+	var affectedPods []PodRef
 
 	// Process each pod
-	for _, pod := range pods.Items {
+	for _, pod := range pods {
 		if pod.Status.Phase != corev1.PodRunning {
 			utils.Warn(fmt.Sprintf("Skipping pod '%s' - not in Running state (current: %s)", pod.Name, pod.Status.Phase))
 			continue
@@ -58,7 +67,7 @@ func InjectLatency(client kubernetes.Interface, namespace, selector, delay strin
 			// Continue with other pods even if one fails
 		} else {
 			// Only add to affected pods if the injection was successful
-			affectedPods = append(affectedPods, pod.Name)
+			affectedPods = append(affectedPods, newPodRef(pod))
 		}
 	}
 
@@ -148,37 +157,46 @@ func getMainProcessName() string {
 // InjectPacketLoss injects network packet loss using tc netem via ephemeral containers
 // Returns the list of pod names that were affected by the packet loss injection
 func InjectPacketLoss(client kubernetes.Interface, namespace, selector, loss string, duration time.Duration, dryRun bool) ([]string, error) {
-	utils.Info(fmt.Sprintf("Searching for pods with selector '%s' in namespace '%s'", selector, namespace))
+	affectedPods, err := InjectPacketLossForTarget(client, PodTarget{Namespace: namespace, Selector: selector}, loss, duration, dryRun)
+	if err != nil {
+		return nil, err
+	}
+	return PodNames(affectedPods), nil
+}
+
+// InjectPacketLossForTarget injects packet loss into the running pods matched by a target
+// Returns the pods that were affected
+func InjectPacketLossForTarget(client kubernetes.Interface, target PodTarget, loss string, duration time.Duration, dryRun bool) ([]PodRef, error) {
+	namespace := target.Namespace
+	utils.Info(fmt.Sprintf("Searching for %s", target))
 
 	// In dry-run mode, simulate the operation without making API calls
 	if dryRun {
-		utils.DryRun(fmt.Sprintf("Would search for pods with selector '%s' in namespace '%s'", selector, namespace))
+		utils.DryRun(fmt.Sprintf("Would search for %s", target))
 		utils.DryRun(fmt.Sprintf("Would inject packet loss to all running pods matching selector"))
 		utils.DryRun(fmt.Sprintf("Would add ephemeral containers with image: ghcr.io/chaos-tools/netem:latest"))
 		utils.DryRun(fmt.Sprintf("Would apply tc netem loss: %s for duration: %s", loss, duration))
 		// Return empty slice for dry-run as we can't determine actual pod names
-		return []string{}, nil
+		return []PodRef{}, nil
 	}
 
-	// List pods matching the selector
-	pods, err := client.CoreV1().Pods(namespace).List(context.TODO(), metav1.ListOptions{
-		LabelSelector: selector,
-	})
+	// List pods matching the target
+	pods, err := SelectPods(client, target)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list pods: %w", err)
+		return nil, err
 	}
 
-	if len(pods.Items) == 0 {
-		utils.Warn(fmt.Sprintf("No pods found matching selector '%s' in namespace '%s'", selector, namespace))
-		return []string{}, nil
+	if len(pods) == 0 {
+		utils.Warn(fmt.Sprintf("No pods found for %s", target))
+		return []PodRef{}, nil
 	}
 
-	utils.Info(fmt.Sprintf("Found %d pod(s) matching selector", len(pods.Items)))
+	utils.Info(fmt.Sprintf("Found %d pod(s) matching selector", len(pods)))
 
-	var affectedPods []string
+	var affectedPods []PodRef
 
 	// Process each pod
-	for _, pod := range pods.Items {
+	for _, pod := range pods {
 		if pod.Status.Phase != corev1.PodRunning {
 			utils.Warn(fmt.Sprintf("Skipping pod '%s' - not in Running state (current: %s)", pod.Name, pod.Status.Phase))
 			continue
@@ -190,7 +208,7 @@ func InjectPacketLoss(client kubernetes.Interface, namespace, selector, loss str
 			// Continue with other pods even if one fails
 		} else {
 			// Only add to affected pods if the injection was successful
-			affectedPods = append(affectedPods, pod.Name)
+			affectedPods = append(affectedPods, newPodRef(pod))
 		}
 	}
 
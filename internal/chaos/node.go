@@ -0,0 +1,222 @@
+package chaos
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/isurusiri/tipsy/internal/utils"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+// DefaultChaosTaint is the taint applied by `tipsy node taint` when none is given
+const DefaultChaosTaint = "tipsy.io/chaos=true:NoSchedule"
+
+// NodeSnapshot records the scheduling state of a node before tipsy changed it
+type NodeSnapshot struct {
+	Name          string
+	UID           types.UID
+	Unschedulable bool
+	Taints        []corev1.Taint
+}
+
+// snapshotNode captures the fields of a node that tipsy may change
+func snapshotNode(node *corev1.Node) NodeSnapshot {
+	taints := make([]corev1.Taint, len(node.Spec.Taints))
+	copy(taints, node.Spec.Taints)
+	return NodeSnapshot{
+		Name:          node.Name,
+		UID:           node.UID,
+		Unschedulable: node.Spec.Unschedulable,
+		Taints:        taints,
+	}
+}
+
+// ResolveNodes returns the node names targeted by either a node name or a node label selector
+func ResolveNodes(client kubernetes.Interface, nodeName, nodeSelector string) ([]string, error) {
+	if nodeName != "" {
+		return []string{nodeName}, nil
+	}
+
+	nodes, err := client.CoreV1().Nodes().List(context.TODO(), metav1.ListOptions{
+		LabelSelector: nodeSelector,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nodes: %w", err)
+	}
+
+	var names []string
+	for _, node := range nodes.Items {
+		names = append(names, node.Name)
+	}
+
+	return names, nil
+}
+
+// CordonNode marks a node as unschedulable
+// Returns the node's prior state, or nil if the node was already cordoned and nothing was changed
+func CordonNode(client kubernetes.Interface, nodeName string, dryRun bool) (*NodeSnapshot, error) {
+	if dryRun {
+		utils.DryRun(fmt.Sprintf("Would cordon node '%s'", nodeName))
+		return nil, nil
+	}
+
+	node, err := client.CoreV1().Nodes().Get(context.TODO(), nodeName, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get node '%s': %w", nodeName, err)
+	}
+
+	if node.Spec.Unschedulable {
+		utils.Info(fmt.Sprintf("Node '%s' is already cordoned", nodeName))
+		return nil, nil
+	}
+
+	snapshot := snapshotNode(node)
+	node.Spec.Unschedulable = true
+	_, err = client.CoreV1().Nodes().Update(context.TODO(), node, metav1.UpdateOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to cordon node '%s': %w", nodeName, err)
+	}
+
+	utils.Info(fmt.Sprintf("Cordoned node '%s'", nodeName))
+	return &snapshot, nil
+}
+
+// CordonNodes cordons every given node
+// Returns the prior state of each node that was changed
+func CordonNodes(client kubernetes.Interface, nodeNames []string, dryRun bool) ([]NodeSnapshot, error) {
+	var changed []NodeSnapshot
+
+	for _, nodeName := range nodeNames {
+		snapshot, err := CordonNode(client, nodeName, dryRun)
+		if err != nil {
+			utils.Error(err.Error())
+			// Continue with other nodes even if one fails
+			continue
+		}
+		if snapshot != nil {
+			changed = append(changed, *snapshot)
+		}
+	}
+
+	return changed, nil
+}
+
+// DrainNodes cordons every given node and evicts its pods, skipping protected pods
+// Returns the prior state of each node that was changed
+func DrainNodes(client kubernetes.Interface, nodeNames []string, dryRun bool) ([]NodeSnapshot, error) {
+	if dryRun {
+		for _, nodeName := range nodeNames {
+			utils.DryRun(fmt.Sprintf("Would cordon node '%s' and evict its pods, skipping protected pods", nodeName))
+		}
+		return []NodeSnapshot{}, nil
+	}
+
+	var changed []NodeSnapshot
+
+	for _, nodeName := range nodeNames {
+		snapshot, err := CordonNode(client, nodeName, dryRun)
+		if err != nil {
+			utils.Error(err.Error())
+			continue
+		}
+		if snapshot != nil {
+			changed = append(changed, *snapshot)
+		}
+
+		pods, err := listPodsOnNode(client, nodeName)
+		if err != nil {
+			return changed, err
+		}
+
+		for _, pod := range pods {
+			if protected, reason := isProtectedPod(pod); protected {
+				utils.Info(fmt.Sprintf("Skipping pod '%s/%s' - %s", pod.Namespace, pod.Name, reason))
+				continue
+			}
+
+			// Evict rather than delete so PodDisruptionBudgets are honoured
+			err := client.PolicyV1().Evictions(pod.Namespace).Evict(context.TODO(), &policyv1.Eviction{
+				ObjectMeta: metav1.ObjectMeta{Name: pod.Name, Namespace: pod.Namespace},
+			})
+			if err != nil {
+				utils.Error(fmt.Sprintf("Failed to evict pod '%s/%s': %v", pod.Namespace, pod.Name, err))
+				continue
+			}
+			utils.Info(fmt.Sprintf("  Evicted pod: %s/%s", pod.Namespace, pod.Name))
+		}
+	}
+
+	return changed, nil
+}
+
+// TaintNodes adds a taint to every given node
+// Returns the prior state of each node that was changed
+func TaintNodes(client kubernetes.Interface, nodeNames []string, taint corev1.Taint, dryRun bool) ([]NodeSnapshot, error) {
+	if dryRun {
+		for _, nodeName := range nodeNames {
+			utils.DryRun(fmt.Sprintf("Would add taint '%s' to node '%s'", taint.ToString(), nodeName))
+		}
+		return []NodeSnapshot{}, nil
+	}
+
+	var changed []NodeSnapshot
+
+	for _, nodeName := range nodeNames {
+		node, err := client.CoreV1().Nodes().Get(context.TODO(), nodeName, metav1.GetOptions{})
+		if err != nil {
+			utils.Error(fmt.Sprintf("Failed to get node '%s': %v", nodeName, err))
+			continue
+		}
+
+		alreadyTainted := false
+		for _, existing := range node.Spec.Taints {
+			if existing.MatchTaint(&taint) {
+				alreadyTainted = true
+				break
+			}
+		}
+		if alreadyTainted {
+			utils.Info(fmt.Sprintf("Node '%s' already has taint '%s'", nodeName, taint.ToString()))
+			continue
+		}
+
+		snapshot := snapshotNode(node)
+		node.Spec.Taints = append(node.Spec.Taints, taint)
+		_, err = client.CoreV1().Nodes().Update(context.TODO(), node, metav1.UpdateOptions{})
+		if err != nil {
+			utils.Error(fmt.Sprintf("Failed to taint node '%s': %v", nodeName, err))
+			continue
+		}
+
+		utils.Info(fmt.Sprintf("Added taint '%s' to node '%s'", taint.ToString(), nodeName))
+		changed = append(changed, snapshot)
+	}
+
+	return changed, nil
+}
+
+// ParseTaint parses a taint in kubectl syntax: key=value:Effect or key:Effect
+func ParseTaint(spec string) (corev1.Taint, error) {
+	keyValue, effect, found := strings.Cut(spec, ":")
+	if !found || effect == "" {
+		return corev1.Taint{}, fmt.Errorf("invalid taint '%s': expected key=value:Effect", spec)
+	}
+
+	switch corev1.TaintEffect(effect) {
+	case corev1.TaintEffectNoSchedule, corev1.TaintEffectPreferNoSchedule, corev1.TaintEffectNoExecute:
+	default:
+		return corev1.Taint{}, fmt.Errorf("invalid taint effect '%s': expected NoSchedule, PreferNoSchedule or NoExecute", effect)
+	}
+
+	key, value, _ := strings.Cut(keyValue, "=")
+	if key == "" {
+		return corev1.Taint{}, fmt.Errorf("invalid taint '%s': key is required", spec)
+	}
+
+	return corev1.Taint{Key: key, Value: value, Effect: corev1.TaintEffect(effect)}, nil
+}
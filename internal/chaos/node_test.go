@@ -0,0 +1,181 @@
+package chaos
+
+import (
+	"context"
+	"testing"
+
+	"github.com/fatih/color"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestResolveNodes(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		createTestNode("node-a1", "zone-a"),
+		createTestNode("node-b1", "zone-b"),
+	)
+
+	names, err := ResolveNodes(client, "node-a1", "")
+	if err != nil || len(names) != 1 || names[0] != "node-a1" {
+		t.Errorf("Expected [node-a1], got %v (err: %v)", names, err)
+	}
+
+	names, err = ResolveNodes(client, "", DefaultTopologyKey+"=zone-b")
+	if err != nil || len(names) != 1 || names[0] != "node-b1" {
+		t.Errorf("Expected [node-b1], got %v (err: %v)", names, err)
+	}
+}
+
+func TestCordonNodes(t *testing.T) {
+	// Disable color for testing
+	originalNoColor := color.NoColor
+	color.NoColor = true
+	defer func() {
+		color.NoColor = originalNoColor
+	}()
+
+	alreadyCordoned := createTestNode("node-2", "zone-a")
+	alreadyCordoned.Spec.Unschedulable = true
+
+	tainted := createTestNode("node-1", "zone-a")
+	tainted.Spec.Taints = []corev1.Taint{{Key: "gpu", Value: "true", Effect: corev1.TaintEffectNoSchedule}}
+
+	client := fake.NewSimpleClientset(tainted, alreadyCordoned)
+
+	snapshots, err := CordonNodes(client, []string{"node-1", "node-2", "missing"}, false)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Only node-1 changed; node-2 was already cordoned and the missing node is skipped
+	if len(snapshots) != 1 {
+		t.Fatalf("Expected 1 snapshot, got %d", len(snapshots))
+	}
+
+	if snapshots[0].Name != "node-1" || snapshots[0].Unschedulable {
+		t.Errorf("Expected snapshot of schedulable node-1, got %+v", snapshots[0])
+	}
+	if len(snapshots[0].Taints) != 1 || snapshots[0].Taints[0].Key != "gpu" {
+		t.Errorf("Expected snapshot to keep the original taints, got %v", snapshots[0].Taints)
+	}
+
+	node, _ := client.CoreV1().Nodes().Get(context.TODO(), "node-1", metav1.GetOptions{})
+	if !node.Spec.Unschedulable {
+		t.Error("Expected node-1 to be cordoned")
+	}
+}
+
+func TestTaintNodes(t *testing.T) {
+	// Disable color for testing
+	originalNoColor := color.NoColor
+	color.NoColor = true
+	defer func() {
+		color.NoColor = originalNoColor
+	}()
+
+	client := fake.NewSimpleClientset(createTestNode("node-1", "zone-a"))
+
+	taint, err := ParseTaint(DefaultChaosTaint)
+	if err != nil {
+		t.Fatalf("Failed to parse default taint: %v", err)
+	}
+
+	snapshots, err := TaintNodes(client, []string{"node-1"}, taint, false)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(snapshots) != 1 || len(snapshots[0].Taints) != 0 {
+		t.Fatalf("Expected 1 snapshot with no taints, got %+v", snapshots)
+	}
+
+	node, _ := client.CoreV1().Nodes().Get(context.TODO(), "node-1", metav1.GetOptions{})
+	if len(node.Spec.Taints) != 1 || !node.Spec.Taints[0].MatchTaint(&taint) {
+		t.Errorf("Expected node to carry the chaos taint, got %v", node.Spec.Taints)
+	}
+
+	// Tainting again is a no-op
+	snapshots, err = TaintNodes(client, []string{"node-1"}, taint, false)
+	if err != nil || len(snapshots) != 0 {
+		t.Errorf("Expected no changes when taint already present, got %+v (err: %v)", snapshots, err)
+	}
+}
+
+func TestDrainNodes(t *testing.T) {
+	// Disable color for testing
+	originalNoColor := color.NoColor
+	color.NoColor = true
+	defer func() {
+		color.NoColor = originalNoColor
+	}()
+
+	client := createOutageTestClient()
+
+	snapshots, err := DrainNodes(client, []string{"node-a1"}, false)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(snapshots) != 1 {
+		t.Fatalf("Expected 1 snapshot, got %d", len(snapshots))
+	}
+
+	node, _ := client.CoreV1().Nodes().Get(context.TODO(), "node-a1", metav1.GetOptions{})
+	if !node.Spec.Unschedulable {
+		t.Error("Expected drained node to be cordoned")
+	}
+
+	var evictions int
+	for _, action := range client.Actions() {
+		if action.GetSubresource() == "eviction" {
+			evictions++
+		}
+	}
+	if evictions != 1 {
+		t.Errorf("Expected exactly 1 eviction (web-1), got %d", evictions)
+	}
+}
+
+func TestDrainNodes_DryRun(t *testing.T) {
+	client := createOutageTestClient()
+
+	snapshots, err := DrainNodes(client, []string{"node-a1"}, true)
+	if err != nil || len(snapshots) != 0 {
+		t.Errorf("Expected no changes in dry-run mode, got %+v (err: %v)", snapshots, err)
+	}
+
+	if len(client.Actions()) != 0 {
+		t.Errorf("Expected no API calls in dry-run mode, got %d", len(client.Actions()))
+	}
+}
+
+func TestParseTaint(t *testing.T) {
+	testCases := []struct {
+		spec        string
+		expected    corev1.Taint
+		expectError bool
+	}{
+		{spec: "dedicated=chaos:NoExecute", expected: corev1.Taint{Key: "dedicated", Value: "chaos", Effect: corev1.TaintEffectNoExecute}},
+		{spec: "maintenance:NoSchedule", expected: corev1.Taint{Key: "maintenance", Effect: corev1.TaintEffectNoSchedule}},
+		{spec: "dedicated=chaos", expectError: true},
+		{spec: "dedicated=chaos:Sometimes", expectError: true},
+		{spec: "=chaos:NoSchedule", expectError: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.spec, func(t *testing.T) {
+			taint, err := ParseTaint(tc.spec)
+			if tc.expectError {
+				if err == nil {
+					t.Errorf("Expected error for taint '%s'", tc.spec)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if taint != tc.expected {
+				t.Errorf("Expected %+v, got %+v", tc.expected, taint)
+			}
+		})
+	}
+}
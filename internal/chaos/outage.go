@@ -0,0 +1,171 @@
+package chaos
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/isurusiri/tipsy/internal/utils"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// DefaultTopologyKey is the well-known node label that holds the availability zone
+const DefaultTopologyKey = "topology.kubernetes.io/zone"
+
+// Outage modes
+const (
+	OutageModeKill    = "kill"
+	OutageModeIsolate = "isolate"
+)
+
+// isolateLoss is the netem packet loss used to cut a pod off the network
+const isolateLoss = "100%"
+
+// OutageOptions controls what happens to the pods caught in an outage
+type OutageOptions struct {
+	Mode     string        // OutageModeKill or OutageModeIsolate
+	Duration time.Duration // how long isolation lasts (ignored for kill)
+	Cordon   bool          // cordon the nodes first so replacements land elsewhere
+}
+
+// OutageResult describes what an outage touched
+type OutageResult struct {
+	Nodes         []string       // nodes in scope of the outage
+	CordonedNodes []NodeSnapshot // prior state of the nodes cordoned by this outage
+	Pods          []PodRef       // pods that were killed or isolated
+}
+
+// FindNodesInZone returns the names of the nodes whose topology label matches the zone
+func FindNodesInZone(client kubernetes.Interface, zone, topologyKey string) ([]string, error) {
+	if topologyKey == "" {
+		topologyKey = DefaultTopologyKey
+	}
+
+	nodes, err := client.CoreV1().Nodes().List(context.TODO(), metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", topologyKey, zone),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nodes: %w", err)
+	}
+
+	var names []string
+	for _, node := range nodes.Items {
+		names = append(names, node.Name)
+	}
+
+	return names, nil
+}
+
+// ZoneOutage simulates the loss of every node in a topology zone
+func ZoneOutage(client kubernetes.Interface, zone, topologyKey string, opts OutageOptions, dryRun bool) (*OutageResult, error) {
+	if topologyKey == "" {
+		topologyKey = DefaultTopologyKey
+	}
+	utils.Info(fmt.Sprintf("Starting zone outage for '%s=%s' (mode: %s)", topologyKey, zone, opts.Mode))
+
+	// In dry-run mode, simulate the operation without making API calls
+	if dryRun {
+		utils.DryRun(fmt.Sprintf("Would search for nodes labelled '%s=%s'", topologyKey, zone))
+		return NodeOutage(client, nil, opts, dryRun)
+	}
+
+	nodeNames, err := FindNodesInZone(client, zone, topologyKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(nodeNames) == 0 {
+		utils.Warn(fmt.Sprintf("No nodes found labelled '%s=%s'", topologyKey, zone))
+		return &OutageResult{}, nil
+	}
+
+	utils.Info(fmt.Sprintf("Found %d node(s) in zone '%s'", len(nodeNames), zone))
+	return NodeOutage(client, nodeNames, opts, dryRun)
+}
+
+// NodeOutage kills or isolates every eligible pod scheduled on the given nodes,
+// optionally cordoning the nodes first
+func NodeOutage(client kubernetes.Interface, nodeNames []string, opts OutageOptions, dryRun bool) (*OutageResult, error) {
+	if opts.Mode != OutageModeKill && opts.Mode != OutageModeIsolate {
+		return nil, fmt.Errorf("unsupported outage mode: %s", opts.Mode)
+	}
+
+	// In dry-run mode, simulate the operation without making API calls
+	if dryRun {
+		if opts.Cordon {
+			utils.DryRun("Would cordon the affected nodes")
+		}
+		utils.DryRun("Would list pods on the affected nodes across all namespaces, skipping protected pods")
+		if opts.Mode == OutageModeKill {
+			utils.DryRun("Would delete every eligible pod")
+		} else {
+			utils.DryRun(fmt.Sprintf("Would inject %s packet loss into every eligible pod for duration: %s", isolateLoss, opts.Duration))
+		}
+		return &OutageResult{}, nil
+	}
+
+	result := &OutageResult{Nodes: nodeNames}
+
+	// Cordon before touching pods so replacements cannot land back on these nodes
+	if opts.Cordon {
+		cordoned, err := CordonNodes(client, nodeNames, dryRun)
+		if err != nil {
+			return result, err
+		}
+		result.CordonedNodes = cordoned
+	}
+
+	for _, nodeName := range nodeNames {
+		pods, err := listPodsOnNode(client, nodeName)
+		if err != nil {
+			return result, err
+		}
+
+		utils.Info(fmt.Sprintf("Found %d pod(s) on node '%s'", len(pods), nodeName))
+
+		for _, pod := range pods {
+			if protected, reason := isProtectedPod(pod); protected {
+				utils.Info(fmt.Sprintf("Skipping pod '%s/%s' - %s", pod.Namespace, pod.Name, reason))
+				continue
+			}
+
+			if opts.Mode == OutageModeKill {
+				err = client.CoreV1().Pods(pod.Namespace).Delete(context.TODO(), pod.Name, metav1.DeleteOptions{})
+			} else {
+				err = injectPacketLossToPod(client, pod.Namespace, pod.Name, isolateLoss, opts.Duration, dryRun)
+			}
+			if err != nil {
+				utils.Error(fmt.Sprintf("Failed to %s pod '%s/%s': %v", opts.Mode, pod.Namespace, pod.Name, err))
+				// Continue with other pods even if one fails
+				continue
+			}
+
+			result.Pods = append(result.Pods, newPodRef(pod))
+		}
+	}
+
+	utils.Info(fmt.Sprintf("Outage affected %d pod(s) on %d node(s)", len(result.Pods), len(nodeNames)))
+	return result, nil
+}
+
+// listPodsOnNode lists the pods scheduled on a node across all namespaces
+func listPodsOnNode(client kubernetes.Interface, nodeName string) ([]corev1.Pod, error) {
+	pods, err := client.CoreV1().Pods(metav1.NamespaceAll).List(context.TODO(), metav1.ListOptions{
+		FieldSelector: fmt.Sprintf("spec.nodeName=%s", nodeName),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods on node '%s': %w", nodeName, err)
+	}
+
+	// Filter again client-side in case the field selector was not honoured
+	var onNode []corev1.Pod
+	for _, pod := range pods.Items {
+		if pod.Spec.NodeName == nodeName {
+			onNode = append(onNode, pod)
+		}
+	}
+
+	return onNode, nil
+}
@@ -0,0 +1,239 @@
+package chaos
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/fatih/color"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// Helper function to create a node in a zone
+func createTestNode(name, zone string) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{DefaultTopologyKey: zone},
+		},
+	}
+}
+
+// Helper function to create a running pod scheduled on a node
+func createTestPodOnNode(name, namespace, nodeName string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec:   corev1.PodSpec{NodeName: nodeName},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+}
+
+// createOutageTestClient builds a cluster with two zones and a mix of eligible and protected pods
+func createOutageTestClient() *fake.Clientset {
+	daemonPod := createTestPodOnNode("node-agent", "monitoring", "node-a1")
+	daemonPod.OwnerReferences = []metav1.OwnerReference{{Kind: "DaemonSet", Name: "node-agent"}}
+
+	excludedPod := createTestPodOnNode("excluded", "default", "node-a2")
+	excludedPod.Labels = map[string]string{ExcludeLabel: "true"}
+
+	objects := []runtime.Object{
+		createTestNode("node-a1", "zone-a"),
+		createTestNode("node-a2", "zone-a"),
+		createTestNode("node-b1", "zone-b"),
+		createTestPodOnNode("web-1", "default", "node-a1"),
+		createTestPodOnNode("api-1", "production", "node-a2"),
+		createTestPodOnNode("web-2", "default", "node-b1"),
+		createTestPodOnNode("coredns", "kube-system", "node-a1"),
+		daemonPod,
+		excludedPod,
+	}
+
+	return fake.NewSimpleClientset(objects...)
+}
+
+func TestFindNodesInZone(t *testing.T) {
+	client := createOutageTestClient()
+
+	nodes, err := FindNodesInZone(client, "zone-a", "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(nodes) != 2 {
+		t.Fatalf("Expected 2 nodes in zone-a, got %d: %v", len(nodes), nodes)
+	}
+}
+
+func TestZoneOutage_KillWithCordon(t *testing.T) {
+	// Disable color for testing
+	originalNoColor := color.NoColor
+	color.NoColor = true
+	defer func() {
+		color.NoColor = originalNoColor
+	}()
+
+	client := createOutageTestClient()
+
+	result, err := ZoneOutage(client, "zone-a", DefaultTopologyKey, OutageOptions{Mode: OutageModeKill, Cordon: true}, false)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(result.CordonedNodes) != 2 {
+		t.Errorf("Expected 2 cordoned nodes, got %d", len(result.CordonedNodes))
+	}
+
+	if len(result.Pods) != 2 {
+		t.Fatalf("Expected 2 killed pods, got %d: %v", len(result.Pods), result.Pods)
+	}
+
+	// The zone-b pod and every protected pod must survive
+	for _, ref := range []PodRef{
+		{Name: "web-2", Namespace: "default"},
+		{Name: "coredns", Namespace: "kube-system"},
+		{Name: "node-agent", Namespace: "monitoring"},
+		{Name: "excluded", Namespace: "default"},
+	} {
+		if _, err := client.CoreV1().Pods(ref.Namespace).Get(context.TODO(), ref.Name, metav1.GetOptions{}); err != nil {
+			t.Errorf("Expected pod '%s/%s' to survive the outage: %v", ref.Namespace, ref.Name, err)
+		}
+	}
+
+	// The zone-a nodes should be cordoned, the zone-b node untouched
+	for name, expected := range map[string]bool{"node-a1": true, "node-a2": true, "node-b1": false} {
+		node, err := client.CoreV1().Nodes().Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("Failed to get node: %v", err)
+		}
+		if node.Spec.Unschedulable != expected {
+			t.Errorf("Node '%s': expected unschedulable=%t, got %t", name, expected, node.Spec.Unschedulable)
+		}
+	}
+}
+
+func TestNodeOutage_Isolate(t *testing.T) {
+	// Disable color for testing
+	originalNoColor := color.NoColor
+	color.NoColor = true
+	defer func() {
+		color.NoColor = originalNoColor
+	}()
+
+	client := createOutageTestClient()
+
+	result, err := NodeOutage(client, []string{"node-a2"}, OutageOptions{Mode: OutageModeIsolate, Duration: 30 * time.Second}, false)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(result.Pods) != 1 || result.Pods[0].Name != "api-1" {
+		t.Fatalf("Expected only api-1 to be isolated, got %v", result.Pods)
+	}
+
+	if len(result.CordonedNodes) != 0 {
+		t.Errorf("Expected no cordoned nodes without --cordon, got %v", result.CordonedNodes)
+	}
+
+	// Isolated pods are not deleted
+	if _, err := client.CoreV1().Pods("production").Get(context.TODO(), "api-1", metav1.GetOptions{}); err != nil {
+		t.Errorf("Expected isolated pod to still exist: %v", err)
+	}
+}
+
+func TestNodeOutage_InvalidMode(t *testing.T) {
+	client := createOutageTestClient()
+
+	_, err := NodeOutage(client, []string{"node-a1"}, OutageOptions{Mode: "explode"}, false)
+	if err == nil {
+		t.Error("Expected error for unsupported outage mode")
+	}
+}
+
+func TestZoneOutage_DryRun(t *testing.T) {
+	// Disable color for testing
+	originalNoColor := color.NoColor
+	color.NoColor = true
+	defer func() {
+		color.NoColor = originalNoColor
+	}()
+
+	client := createOutageTestClient()
+
+	result, err := ZoneOutage(client, "zone-a", "", OutageOptions{Mode: OutageModeKill, Cordon: true}, true)
+	if err != nil {
+		t.Fatalf("Unexpected error in dry-run mode: %v", err)
+	}
+
+	if len(result.Pods) != 0 || len(result.CordonedNodes) != 0 {
+		t.Errorf("Expected empty result in dry-run mode, got %+v", result)
+	}
+
+	pods, err := client.CoreV1().Pods(metav1.NamespaceAll).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("Failed to list pods: %v", err)
+	}
+	if len(pods.Items) != 6 {
+		t.Errorf("Expected all 6 pods to remain in dry-run mode, got %d", len(pods.Items))
+	}
+}
+
+func TestIsProtectedPod(t *testing.T) {
+	now := metav1.Now()
+
+	testCases := []struct {
+		name     string
+		pod      corev1.Pod
+		expected bool
+	}{
+		{
+			name:     "regular running pod",
+			pod:      *createTestPodOnNode("web", "default", "node"),
+			expected: false,
+		},
+		{
+			name:     "system namespace",
+			pod:      *createTestPodOnNode("coredns", "kube-system", "node"),
+			expected: true,
+		},
+		{
+			name: "mirror pod",
+			pod: corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "etcd",
+					Namespace:   "default",
+					Annotations: map[string]string{corev1.MirrorPodAnnotationKey: "hash"},
+				},
+				Status: corev1.PodStatus{Phase: corev1.PodRunning},
+			},
+			expected: true,
+		},
+		{
+			name: "terminating pod",
+			pod: corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "old", Namespace: "default", DeletionTimestamp: &now},
+				Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+			},
+			expected: true,
+		},
+		{
+			name:     "pending pod",
+			pod:      corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "new", Namespace: "default"}},
+			expected: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			protected, reason := isProtectedPod(tc.pod)
+			if protected != tc.expected {
+				t.Errorf("Expected protected=%t, got %t (%s)", tc.expected, protected, reason)
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package chaos
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/isurusiri/tipsy/internal/utils"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Labels marking objects that tipsy created and owns
+const (
+	ManagedByLabel = "app.kubernetes.io/managed-by"
+	ManagedByValue = "tipsy"
+)
+
+// QuotaSqueezeName is the name of the ResourceQuota created by `tipsy quota-squeeze`
+const QuotaSqueezeName = "tipsy-quota-squeeze"
+
+// QuotaLimits describes the hard limits of a squeeze quota
+type QuotaLimits struct {
+	Pods   *int64 // maximum number of pods; nil leaves pods unlimited
+	CPU    string // maximum total CPU requests, e.g. "500m"; empty leaves CPU unlimited
+	Memory string // maximum total memory requests, e.g. "512Mi"; empty leaves memory unlimited
+}
+
+// BuildQuotaHard converts quota limits into a ResourceQuota hard resource list
+func BuildQuotaHard(limits QuotaLimits) (corev1.ResourceList, error) {
+	hard := corev1.ResourceList{}
+
+	if limits.Pods != nil {
+		if *limits.Pods < 0 {
+			return nil, fmt.Errorf("pod limit cannot be negative: %d", *limits.Pods)
+		}
+		hard[corev1.ResourcePods] = *resource.NewQuantity(*limits.Pods, resource.DecimalSI)
+	}
+
+	if limits.CPU != "" {
+		cpu, err := resource.ParseQuantity(limits.CPU)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CPU quantity '%s': %w", limits.CPU, err)
+		}
+		hard[corev1.ResourceRequestsCPU] = cpu
+	}
+
+	if limits.Memory != "" {
+		memory, err := resource.ParseQuantity(limits.Memory)
+		if err != nil {
+			return nil, fmt.Errorf("invalid memory quantity '%s': %w", limits.Memory, err)
+		}
+		hard[corev1.ResourceRequestsMemory] = memory
+	}
+
+	if len(hard) == 0 {
+		return nil, fmt.Errorf("at least one of pods, CPU or memory must be limited")
+	}
+
+	return hard, nil
+}
+
+// QuotaSqueeze creates a tipsy-labelled ResourceQuota that stops new pods from being admitted
+// in a namespace, or caps the CPU and memory they can request
+// Returns the name of the created quota
+func QuotaSqueeze(client kubernetes.Interface, namespace string, limits QuotaLimits, dryRun bool) (string, error) {
+	utils.Info(fmt.Sprintf("Starting quota squeeze in namespace '%s'", namespace))
+
+	hard, err := BuildQuotaHard(limits)
+	if err != nil {
+		return "", err
+	}
+
+	// In dry-run mode, simulate the operation without making API calls
+	if dryRun {
+		utils.DryRun(fmt.Sprintf("Would create ResourceQuota '%s' in namespace '%s' with limits: %s", QuotaSqueezeName, namespace, formatResourceList(hard)))
+		return QuotaSqueezeName, nil
+	}
+
+	quota := &corev1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      QuotaSqueezeName,
+			Namespace: namespace,
+			Labels:    map[string]string{ManagedByLabel: ManagedByValue},
+		},
+		Spec: corev1.ResourceQuotaSpec{Hard: hard},
+	}
+
+	_, err = client.CoreV1().ResourceQuotas(namespace).Create(context.TODO(), quota, metav1.CreateOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to create resource quota '%s': %w", QuotaSqueezeName, err)
+	}
+
+	utils.Info(fmt.Sprintf("Created ResourceQuota '%s' with limits: %s", QuotaSqueezeName, formatResourceList(hard)))
+	return QuotaSqueezeName, nil
+}
+
+// formatResourceList renders a resource list as "name=quantity" pairs in a stable order
+func formatResourceList(resources corev1.ResourceList) string {
+	var formatted string
+	for _, name := range []corev1.ResourceName{corev1.ResourcePods, corev1.ResourceCPU, corev1.ResourceMemory, corev1.ResourceRequestsCPU, corev1.ResourceRequestsMemory} {
+		quantity, exists := resources[name]
+		if !exists {
+			continue
+		}
+		if formatted != "" {
+			formatted += ", "
+		}
+		formatted += fmt.Sprintf("%s=%s", name, quantity.String())
+	}
+	return formatted
+}
@@ -0,0 +1,106 @@
+package chaos
+
+import (
+	"context"
+	"testing"
+
+	"github.com/fatih/color"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestBuildQuotaHard(t *testing.T) {
+	zero := int64(0)
+	negative := int64(-1)
+
+	testCases := []struct {
+		name          string
+		limits        QuotaLimits
+		expectedNames []corev1.ResourceName
+		expectError   bool
+	}{
+		{name: "block pods", limits: QuotaLimits{Pods: &zero}, expectedNames: []corev1.ResourceName{corev1.ResourcePods}},
+		{name: "cpu and memory", limits: QuotaLimits{CPU: "500m", Memory: "512Mi"}, expectedNames: []corev1.ResourceName{corev1.ResourceRequestsCPU, corev1.ResourceRequestsMemory}},
+		{name: "no limits", limits: QuotaLimits{}, expectError: true},
+		{name: "negative pods", limits: QuotaLimits{Pods: &negative}, expectError: true},
+		{name: "invalid cpu", limits: QuotaLimits{CPU: "lots"}, expectError: true},
+		{name: "invalid memory", limits: QuotaLimits{Memory: "lots"}, expectError: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			hard, err := BuildQuotaHard(tc.limits)
+			if tc.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if len(hard) != len(tc.expectedNames) {
+				t.Errorf("Expected %d resources, got %v", len(tc.expectedNames), hard)
+			}
+			for _, name := range tc.expectedNames {
+				if _, exists := hard[name]; !exists {
+					t.Errorf("Expected resource %s to be limited", name)
+				}
+			}
+		})
+	}
+}
+
+func TestQuotaSqueeze(t *testing.T) {
+	// Disable color for testing
+	originalNoColor := color.NoColor
+	color.NoColor = true
+	defer func() {
+		color.NoColor = originalNoColor
+	}()
+
+	client := fake.NewSimpleClientset()
+	zero := int64(0)
+
+	name, err := QuotaSqueeze(client, "checkout", QuotaLimits{Pods: &zero, CPU: "500m"}, false)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	quota, err := client.CoreV1().ResourceQuotas("checkout").Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Expected quota to be created: %v", err)
+	}
+
+	if quota.Labels[ManagedByLabel] != ManagedByValue {
+		t.Errorf("Expected quota to be labelled as managed by tipsy, got %v", quota.Labels)
+	}
+	pods := quota.Spec.Hard[corev1.ResourcePods]
+	if pods.Value() != 0 {
+		t.Errorf("Expected pods limit 0, got %s", pods.String())
+	}
+	cpu := quota.Spec.Hard[corev1.ResourceRequestsCPU]
+	if cpu.String() != "500m" {
+		t.Errorf("Expected CPU limit 500m, got %s", cpu.String())
+	}
+
+	// A second squeeze in the same namespace fails rather than replacing the quota
+	if _, err := QuotaSqueeze(client, "checkout", QuotaLimits{Pods: &zero}, false); err == nil {
+		t.Error("Expected error when the squeeze quota already exists")
+	}
+}
+
+func TestQuotaSqueeze_DryRun(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	zero := int64(0)
+
+	if _, err := QuotaSqueeze(client, "checkout", QuotaLimits{Pods: &zero}, true); err != nil {
+		t.Fatalf("Unexpected error in dry-run mode: %v", err)
+	}
+
+	quotas, _ := client.CoreV1().ResourceQuotas("checkout").List(context.TODO(), metav1.ListOptions{})
+	if len(quotas.Items) != 0 {
+		t.Error("Expected no quota to be created in dry-run mode")
+	}
+}
@@ -0,0 +1,154 @@
+package chaos
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/isurusiri/tipsy/internal/utils"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// ReadinessGateCondition is the pod condition owned by tipsy
+// Pods opt in to readiness flapping by declaring a readiness gate with this condition type
+const ReadinessGateCondition corev1.PodConditionType = "tipsy.io/ready"
+
+// FindFlappablePods returns the running pods matched by the target that declare the tipsy readiness gate
+func FindFlappablePods(client kubernetes.Interface, target PodTarget, dryRun bool) ([]PodRef, error) {
+	utils.Info(fmt.Sprintf("Searching for %s", target))
+
+	// In dry-run mode, simulate the operation without making API calls
+	if dryRun {
+		utils.DryRun(fmt.Sprintf("Would search for %s", target))
+		utils.DryRun(fmt.Sprintf("Would select running pods that declare the '%s' readiness gate", ReadinessGateCondition))
+		return []PodRef{}, nil
+	}
+
+	pods, err := SelectPods(client, target)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(pods) == 0 {
+		utils.Warn(fmt.Sprintf("No pods found for %s", target))
+		return []PodRef{}, nil
+	}
+
+	utils.Info(fmt.Sprintf("Found %d pod(s)", len(pods)))
+
+	var flappable []PodRef
+	for _, pod := range pods {
+		if pod.Status.Phase != corev1.PodRunning {
+			utils.Warn(fmt.Sprintf("Skipping pod '%s' - not in Running state (current: %s)", pod.Name, pod.Status.Phase))
+			continue
+		}
+
+		if !hasReadinessGate(pod) {
+			utils.Warn(fmt.Sprintf("Skipping pod '%s' - no readiness gate for condition '%s'", pod.Name, ReadinessGateCondition))
+			continue
+		}
+
+		flappable = append(flappable, newPodRef(pod))
+	}
+
+	return flappable, nil
+}
+
+// FlapReadiness toggles the tipsy readiness condition of the given pods every period until the
+// duration has passed or stop is closed, starting with not-ready
+// The condition is left in whatever state the last toggle put it in; rollback sets it back to True
+func FlapReadiness(client kubernetes.Interface, namespace string, podNames []string, period, duration time.Duration, stop <-chan struct{}, dryRun bool) error {
+	if period <= 0 {
+		return fmt.Errorf("period must be greater than zero")
+	}
+
+	if dryRun {
+		utils.DryRun(fmt.Sprintf("Would toggle condition '%s' on matching pods every %s for %s", ReadinessGateCondition, period, duration))
+		return nil
+	}
+
+	if len(podNames) == 0 {
+		return nil
+	}
+
+	utils.Info(fmt.Sprintf("Flapping readiness of %d pod(s) every %s for %s", len(podNames), period, duration))
+
+	deadline := time.After(duration)
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+
+	status := corev1.ConditionFalse
+	for {
+		for _, podName := range podNames {
+			if err := SetReadinessCondition(client, namespace, podName, status); err != nil {
+				utils.Error(fmt.Sprintf("Failed to set readiness of pod '%s': %v", podName, err))
+				// Continue with other pods even if one fails
+			}
+		}
+		utils.Info(fmt.Sprintf("Set condition '%s' to %s on %d pod(s)", ReadinessGateCondition, status, len(podNames)))
+
+		select {
+		case <-deadline:
+			return nil
+		case <-stop:
+			utils.Info("Stopped flapping readiness early")
+			return nil
+		case <-ticker.C:
+		}
+
+		if status == corev1.ConditionFalse {
+			status = corev1.ConditionTrue
+		} else {
+			status = corev1.ConditionFalse
+		}
+	}
+}
+
+// SetReadinessCondition sets the tipsy readiness condition of a pod through the status subresource
+func SetReadinessCondition(client kubernetes.Interface, namespace, podName string, status corev1.ConditionStatus) error {
+	pod, err := client.CoreV1().Pods(namespace).Get(context.TODO(), podName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get pod: %w", err)
+	}
+
+	condition := corev1.PodCondition{
+		Type:               ReadinessGateCondition,
+		Status:             status,
+		LastTransitionTime: metav1.Now(),
+		Reason:             "TipsyReadinessFlap",
+	}
+
+	found := false
+	for i := range pod.Status.Conditions {
+		if pod.Status.Conditions[i].Type == ReadinessGateCondition {
+			if pod.Status.Conditions[i].Status == status {
+				return nil
+			}
+			pod.Status.Conditions[i] = condition
+			found = true
+			break
+		}
+	}
+	if !found {
+		pod.Status.Conditions = append(pod.Status.Conditions, condition)
+	}
+
+	_, err = client.CoreV1().Pods(namespace).UpdateStatus(context.TODO(), pod, metav1.UpdateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to update pod status: %w", err)
+	}
+
+	return nil
+}
+
+// hasReadinessGate reports whether a pod declares the tipsy readiness gate
+func hasReadinessGate(pod corev1.Pod) bool {
+	for _, gate := range pod.Spec.ReadinessGates {
+		if gate.ConditionType == ReadinessGateCondition {
+			return true
+		}
+	}
+	return false
+}
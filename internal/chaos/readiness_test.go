@@ -0,0 +1,157 @@
+package chaos
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/fatih/color"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// Helper function to create a pod that optionally declares the tipsy readiness gate
+func createTestPodWithGate(name string, phase corev1.PodPhase, withGate bool) *corev1.Pod {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: map[string]string{"app": "api"}},
+		Status:     corev1.PodStatus{Phase: phase},
+	}
+	if withGate {
+		pod.Spec.ReadinessGates = []corev1.PodReadinessGate{{ConditionType: ReadinessGateCondition}}
+	}
+	return pod
+}
+
+// readinessCondition returns the status of the tipsy condition on a pod, or "" if it is not set
+func readinessCondition(t *testing.T, client *fake.Clientset, podName string) corev1.ConditionStatus {
+	pod, err := client.CoreV1().Pods("default").Get(context.TODO(), podName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Failed to get pod: %v", err)
+	}
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == ReadinessGateCondition {
+			return condition.Status
+		}
+	}
+	return ""
+}
+
+func TestFindFlappablePods(t *testing.T) {
+	// Disable color for testing
+	originalNoColor := color.NoColor
+	color.NoColor = true
+	defer func() {
+		color.NoColor = originalNoColor
+	}()
+
+	client := fake.NewSimpleClientset(
+		createTestPodWithGate("gated", corev1.PodRunning, true),
+		createTestPodWithGate("ungated", corev1.PodRunning, false),
+		createTestPodWithGate("pending", corev1.PodPending, true),
+	)
+
+	pods, err := FindFlappablePods(client, PodTarget{Namespace: "default", Selector: "app=api"}, false)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(pods) != 1 || pods[0].Name != "gated" {
+		t.Errorf("Expected only the running gated pod, got %v", pods)
+	}
+}
+
+func TestFindFlappablePods_Ordinal(t *testing.T) {
+	// Disable color for testing
+	originalNoColor := color.NoColor
+	color.NoColor = true
+	defer func() {
+		color.NoColor = originalNoColor
+	}()
+
+	var pods []runtime.Object
+	for ordinal := 0; ordinal < 3; ordinal++ {
+		pod := createTestStatefulSetPod("db", ordinal)
+		pod.Spec.ReadinessGates = []corev1.PodReadinessGate{{ConditionType: ReadinessGateCondition}}
+		pods = append(pods, pod)
+	}
+	client := fake.NewSimpleClientset(pods...)
+
+	found, err := FindFlappablePods(client, PodTarget{Namespace: "default", Selector: "app=db", Ordinals: []int{0}}, false)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(found) != 1 || found[0].Name != "db-0" || found[0].Ordinal != 0 {
+		t.Errorf("Expected only the primary, got %v", found)
+	}
+}
+
+func TestFlapReadiness(t *testing.T) {
+	// Disable color for testing
+	originalNoColor := color.NoColor
+	color.NoColor = true
+	defer func() {
+		color.NoColor = originalNoColor
+	}()
+
+	client := fake.NewSimpleClientset(createTestPodWithGate("gated", corev1.PodRunning, true))
+
+	// A duration shorter than the period toggles exactly once, to not-ready
+	err := FlapReadiness(client, "default", []string{"gated"}, time.Hour, 10*time.Millisecond, nil, false)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if status := readinessCondition(t, client, "gated"); status != corev1.ConditionFalse {
+		t.Errorf("Expected condition False after the first toggle, got '%s'", status)
+	}
+
+	if err := SetReadinessCondition(client, "default", "gated", corev1.ConditionTrue); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if status := readinessCondition(t, client, "gated"); status != corev1.ConditionTrue {
+		t.Errorf("Expected condition True, got '%s'", status)
+	}
+
+	// An invalid period is rejected
+	if err := FlapReadiness(client, "default", []string{"gated"}, 0, time.Second, nil, false); err == nil {
+		t.Error("Expected error for zero period")
+	}
+}
+
+func TestFlapReadiness_Stop(t *testing.T) {
+	// Disable color for testing
+	originalNoColor := color.NoColor
+	color.NoColor = true
+	defer func() {
+		color.NoColor = originalNoColor
+	}()
+
+	client := fake.NewSimpleClientset(createTestPodWithGate("gated", corev1.PodRunning, true))
+
+	stop := make(chan struct{})
+	close(stop)
+
+	start := time.Now()
+	if err := FlapReadiness(client, "default", []string{"gated"}, time.Hour, time.Hour, stop, false); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if time.Since(start) > 5*time.Second {
+		t.Error("Expected a closed stop channel to end flapping early")
+	}
+}
+
+func TestFlapReadiness_DryRun(t *testing.T) {
+	client := fake.NewSimpleClientset(createTestPodWithGate("gated", corev1.PodRunning, true))
+
+	if err := FlapReadiness(client, "default", []string{"gated"}, time.Millisecond, time.Second, nil, true); err != nil {
+		t.Fatalf("Unexpected error in dry-run mode: %v", err)
+	}
+
+	if status := readinessCondition(t, client, "gated"); status != "" {
+		t.Errorf("Expected condition to be unset in dry-run mode, got '%s'", status)
+	}
+}
@@ -0,0 +1,162 @@
+package chaos
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/isurusiri/tipsy/internal/utils"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// ResizeResult records the resources of a pod's containers before they were squeezed
+type ResizeResult struct {
+	Pod      PodRef
+	Original map[string]corev1.ResourceRequirements // keyed by container name
+}
+
+// ResizeSqueeze shrinks the CPU and memory limits of the containers in every running pod
+// matched by the target, in place through the pod resize subresource
+// Returns the original resources of each pod that was resized
+func ResizeSqueeze(client kubernetes.Interface, target PodTarget, cpu, memory string, dryRun bool) ([]ResizeResult, error) {
+	if cpu == "" && memory == "" {
+		return nil, fmt.Errorf("at least one of CPU or memory must be given")
+	}
+
+	limits := corev1.ResourceList{}
+	if cpu != "" {
+		quantity, err := resource.ParseQuantity(cpu)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CPU quantity '%s': %w", cpu, err)
+		}
+		limits[corev1.ResourceCPU] = quantity
+	}
+	if memory != "" {
+		quantity, err := resource.ParseQuantity(memory)
+		if err != nil {
+			return nil, fmt.Errorf("invalid memory quantity '%s': %w", memory, err)
+		}
+		limits[corev1.ResourceMemory] = quantity
+	}
+
+	utils.Info(fmt.Sprintf("Searching for %s", target))
+
+	// In dry-run mode, simulate the operation without making API calls
+	if dryRun {
+		utils.DryRun(fmt.Sprintf("Would search for %s", target))
+		utils.DryRun(fmt.Sprintf("Would resize every container in the running pods found to limits: %s", formatResourceList(limits)))
+		return []ResizeResult{}, nil
+	}
+
+	pods, err := SelectPods(client, target)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(pods) == 0 {
+		utils.Warn(fmt.Sprintf("No pods found for %s", target))
+		return []ResizeResult{}, nil
+	}
+
+	utils.Info(fmt.Sprintf("Found %d pod(s)", len(pods)))
+
+	var results []ResizeResult
+
+	for _, pod := range pods {
+		if pod.Status.Phase != corev1.PodRunning {
+			utils.Warn(fmt.Sprintf("Skipping pod '%s' - not in Running state (current: %s)", pod.Name, pod.Status.Phase))
+			continue
+		}
+
+		result, err := resizePod(client, pod, limits)
+		if err != nil {
+			utils.Error(fmt.Sprintf("Failed to resize pod '%s': %v", pod.Name, err))
+			// Continue with other pods even if one fails
+			continue
+		}
+		if result == nil {
+			continue
+		}
+
+		results = append(results, *result)
+	}
+
+	return results, nil
+}
+
+// resizePod lowers the limits of every container in a pod to the given limits, keeping requests at
+// or below the new limits
+// Only limits that exist and are above the target are lowered: raising a limit is not a squeeze, and
+// adding one can change the pod's QoS class, which the resize subresource rejects
+// Returns nil if no container had a limit to lower
+func resizePod(client kubernetes.Interface, pod corev1.Pod, limits corev1.ResourceList) (*ResizeResult, error) {
+	result := &ResizeResult{Pod: newPodRef(pod), Original: make(map[string]corev1.ResourceRequirements)}
+	resized := pod.DeepCopy()
+	squeezed := false
+
+	for i := range resized.Spec.Containers {
+		container := &resized.Spec.Containers[i]
+		result.Original[container.Name] = *container.Resources.DeepCopy()
+
+		for name, limit := range limits {
+			current, exists := container.Resources.Limits[name]
+			if !exists {
+				utils.Warn(fmt.Sprintf("Skipping %s of container '%s' in pod '%s' - it has no limit to lower", name, container.Name, pod.Name))
+				continue
+			}
+			if current.Cmp(limit) <= 0 {
+				utils.Warn(fmt.Sprintf("Skipping %s of container '%s' in pod '%s' - its limit %s is already at or below %s", name, container.Name, pod.Name, current.String(), limit.String()))
+				continue
+			}
+
+			for _, policy := range container.ResizePolicy {
+				if policy.ResourceName == name && policy.RestartPolicy == corev1.RestartContainer {
+					utils.Warn(fmt.Sprintf("Container '%s' in pod '%s' restarts when its %s is resized", container.Name, pod.Name, name))
+				}
+			}
+
+			container.Resources.Limits[name] = limit
+			// Requests may never exceed limits
+			if request, exists := container.Resources.Requests[name]; exists && request.Cmp(limit) > 0 {
+				container.Resources.Requests[name] = limit
+			}
+			squeezed = true
+		}
+	}
+
+	if !squeezed {
+		utils.Warn(fmt.Sprintf("Skipping pod '%s' - no container has a limit above %s", pod.Name, formatResourceList(limits)))
+		return nil, nil
+	}
+
+	_, err := client.CoreV1().Pods(pod.Namespace).UpdateResize(context.TODO(), pod.Name, resized, metav1.UpdateOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	utils.Info(fmt.Sprintf("Resized pod '%s' to limits: %s", pod.Name, formatResourceList(limits)))
+	return result, nil
+}
+
+// RestorePodResources puts back the original resources of a pod's containers through the resize subresource
+func RestorePodResources(client kubernetes.Interface, namespace, podName string, original map[string]corev1.ResourceRequirements) error {
+	pod, err := client.CoreV1().Pods(namespace).Get(context.TODO(), podName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get pod: %w", err)
+	}
+
+	for i := range pod.Spec.Containers {
+		if resources, exists := original[pod.Spec.Containers[i].Name]; exists {
+			pod.Spec.Containers[i].Resources = resources
+		}
+	}
+
+	_, err = client.CoreV1().Pods(namespace).UpdateResize(context.TODO(), podName, pod, metav1.UpdateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to resize pod: %w", err)
+	}
+
+	return nil
+}
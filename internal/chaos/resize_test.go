@@ -0,0 +1,222 @@
+package chaos
+
+import (
+	"context"
+	"testing"
+
+	"github.com/fatih/color"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// Helper function to create a running pod with one container's resources
+func createTestPodWithResources(name string, phase corev1.PodPhase, resources corev1.ResourceRequirements) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: map[string]string{"app": "api"}},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "api", Resources: resources}},
+		},
+		Status: corev1.PodStatus{Phase: phase},
+	}
+}
+
+func TestResizeSqueeze(t *testing.T) {
+	// Disable color for testing
+	originalNoColor := color.NoColor
+	color.NoColor = true
+	defer func() {
+		color.NoColor = originalNoColor
+	}()
+
+	resources := corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("500m"),
+			corev1.ResourceMemory: resource.MustParse("64Mi"),
+		},
+		Limits: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("1"),
+			corev1.ResourceMemory: resource.MustParse("512Mi"),
+		},
+	}
+
+	client := fake.NewSimpleClientset(
+		createTestPodWithResources("api-1", corev1.PodRunning, resources),
+		createTestPodWithResources("api-2", corev1.PodPending, resources),
+	)
+
+	results, err := ResizeSqueeze(client, PodTarget{Namespace: "default", Selector: "app=api"}, "100m", "128Mi", false)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(results) != 1 || results[0].Pod.Name != "api-1" {
+		t.Fatalf("Expected only the running pod to be resized, got %v", results)
+	}
+
+	original := results[0].Original["api"]
+	if original.Limits.Cpu().String() != "1" || original.Limits.Memory().String() != "512Mi" {
+		t.Errorf("Expected original limits to be recorded, got %v", original.Limits)
+	}
+
+	pod, _ := client.CoreV1().Pods("default").Get(context.TODO(), "api-1", metav1.GetOptions{})
+	container := pod.Spec.Containers[0]
+	if container.Resources.Limits.Cpu().String() != "100m" || container.Resources.Limits.Memory().String() != "128Mi" {
+		t.Errorf("Expected squeezed limits, got %v", container.Resources.Limits)
+	}
+	// The CPU request is lowered to the new limit; the memory request already fits
+	if container.Resources.Requests.Cpu().String() != "100m" {
+		t.Errorf("Expected CPU request to be lowered to the limit, got %s", container.Resources.Requests.Cpu().String())
+	}
+	if container.Resources.Requests.Memory().String() != "64Mi" {
+		t.Errorf("Expected memory request to be kept, got %s", container.Resources.Requests.Memory().String())
+	}
+
+	// Restore the original resources
+	if err := RestorePodResources(client, "default", "api-1", results[0].Original); err != nil {
+		t.Fatalf("Unexpected error restoring resources: %v", err)
+	}
+
+	pod, _ = client.CoreV1().Pods("default").Get(context.TODO(), "api-1", metav1.GetOptions{})
+	if pod.Spec.Containers[0].Resources.Limits.Cpu().String() != "1" || pod.Spec.Containers[0].Resources.Requests.Cpu().String() != "500m" {
+		t.Errorf("Expected original resources after restore, got %v", pod.Spec.Containers[0].Resources)
+	}
+}
+
+func TestResizeSqueeze_OnlyLowersExistingLimits(t *testing.T) {
+	// Disable color for testing
+	originalNoColor := color.NoColor
+	color.NoColor = true
+	defer func() {
+		color.NoColor = originalNoColor
+	}()
+
+	pod := createTestPodWithResources("api-1", corev1.PodRunning, corev1.ResourceRequirements{})
+	pod.Spec.Containers = []corev1.Container{
+		// No limits at all
+		{Name: "unlimited", Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("50m")},
+		}},
+		// CPU already below the target, memory above it
+		{Name: "small", Resources: corev1.ResourceRequirements{
+			Limits: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("50m"),
+				corev1.ResourceMemory: resource.MustParse("512Mi"),
+			},
+		}},
+	}
+	client := fake.NewSimpleClientset(pod)
+
+	results, err := ResizeSqueeze(client, PodTarget{Namespace: "default", Selector: "app=api"}, "100m", "128Mi", false)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("Expected the pod to be resized, got %v", results)
+	}
+
+	resized, _ := client.CoreV1().Pods("default").Get(context.TODO(), "api-1", metav1.GetOptions{})
+	if limits := resized.Spec.Containers[0].Resources.Limits; len(limits) != 0 {
+		t.Errorf("Expected no limits to be added to an unlimited container, got %v", limits)
+	}
+	small := resized.Spec.Containers[1].Resources.Limits
+	if small.Cpu().String() != "50m" {
+		t.Errorf("Expected a lower CPU limit to be kept, got %s", small.Cpu().String())
+	}
+	if small.Memory().String() != "128Mi" {
+		t.Errorf("Expected the memory limit to be squeezed, got %s", small.Memory().String())
+	}
+}
+
+func TestResizeSqueeze_NothingToLower(t *testing.T) {
+	// Disable color for testing
+	originalNoColor := color.NoColor
+	color.NoColor = true
+	defer func() {
+		color.NoColor = originalNoColor
+	}()
+
+	client := fake.NewSimpleClientset(
+		createTestPodWithResources("unlimited", corev1.PodRunning, corev1.ResourceRequirements{}),
+		createTestPodWithResources("small", corev1.PodRunning, corev1.ResourceRequirements{
+			Limits: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("50m")},
+		}),
+	)
+
+	results, err := ResizeSqueeze(client, PodTarget{Namespace: "default", Selector: "app=api"}, "100m", "", false)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(results) != 0 {
+		t.Errorf("Expected no pods to be resized, got %v", results)
+	}
+
+	pod, _ := client.CoreV1().Pods("default").Get(context.TODO(), "small", metav1.GetOptions{})
+	if pod.Spec.Containers[0].Resources.Limits.Cpu().String() != "50m" {
+		t.Errorf("Expected the lower limit to be kept, got %s", pod.Spec.Containers[0].Resources.Limits.Cpu().String())
+	}
+}
+
+func TestResizeSqueeze_Errors(t *testing.T) {
+	client := fake.NewSimpleClientset()
+
+	if _, err := ResizeSqueeze(client, PodTarget{Namespace: "default", Selector: "app=api"}, "", "", false); err == nil {
+		t.Error("Expected error when no limits are given")
+	}
+
+	if _, err := ResizeSqueeze(client, PodTarget{Namespace: "default", Selector: "app=api"}, "lots", "", false); err == nil {
+		t.Error("Expected error for invalid CPU quantity")
+	}
+}
+
+func TestResizeSqueeze_DryRun(t *testing.T) {
+	resources := corev1.ResourceRequirements{Limits: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")}}
+	client := fake.NewSimpleClientset(createTestPodWithResources("api-1", corev1.PodRunning, resources))
+
+	results, err := ResizeSqueeze(client, PodTarget{Namespace: "default", Selector: "app=api"}, "100m", "", true)
+	if err != nil {
+		t.Fatalf("Unexpected error in dry-run mode: %v", err)
+	}
+	if len(results) != 0 {
+		t.Errorf("Expected no results in dry-run mode, got %v", results)
+	}
+
+	pod, _ := client.CoreV1().Pods("default").Get(context.TODO(), "api-1", metav1.GetOptions{})
+	if pod.Spec.Containers[0].Resources.Limits.Cpu().String() != "1" {
+		t.Error("Expected limits to be unchanged in dry-run mode")
+	}
+}
+
+func TestResizeSqueeze_Ordinal(t *testing.T) {
+	// Disable color for testing
+	originalNoColor := color.NoColor
+	color.NoColor = true
+	defer func() {
+		color.NoColor = originalNoColor
+	}()
+
+	var pods []runtime.Object
+	for ordinal := 0; ordinal < 3; ordinal++ {
+		pod := createTestStatefulSetPod("db", ordinal)
+		pod.Spec.Containers = []corev1.Container{{Name: "db", Resources: corev1.ResourceRequirements{
+			Limits: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")},
+		}}}
+		pods = append(pods, pod)
+	}
+	client := fake.NewSimpleClientset(pods...)
+
+	// All but the primary
+	results, err := ResizeSqueeze(client, PodTarget{Namespace: "default", Selector: "app=db", ExceptOrdinals: []int{0}}, "100m", "", false)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(results) != 2 || results[0].Pod.Name != "db-1" || results[1].Pod.Name != "db-2" {
+		t.Fatalf("Expected every pod but the primary to be resized, got %v", results)
+	}
+	if results[0].Pod.Ordinal != 1 {
+		t.Errorf("Expected the ordinal to be recorded, got %d", results[0].Pod.Ordinal)
+	}
+}
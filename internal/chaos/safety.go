@@ -0,0 +1,49 @@
+package chaos
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ExcludeLabel opts a pod out of faults that select pods across namespaces when set to "true"
+const ExcludeLabel = "tipsy.io/exclude"
+
+// protectedNamespaces are never touched by faults that select pods across namespaces
+var protectedNamespaces = map[string]bool{
+	"kube-system":     true,
+	"kube-public":     true,
+	"kube-node-lease": true,
+}
+
+// isProtectedPod reports whether a pod must be left alone by faults that select
+// pods across namespaces (for example node or zone outages), along with the reason
+func isProtectedPod(pod corev1.Pod) (bool, string) {
+	if protectedNamespaces[pod.Namespace] {
+		return true, "system namespace"
+	}
+
+	if pod.Labels[ExcludeLabel] == "true" {
+		return true, "opted out via " + ExcludeLabel + " label"
+	}
+
+	// Mirror pods belong to static manifests on the node and cannot be managed through the API
+	if _, isMirror := pod.Annotations[corev1.MirrorPodAnnotationKey]; isMirror {
+		return true, "static mirror pod"
+	}
+
+	// DaemonSet pods are node agents and would be recreated on the same node anyway
+	for _, owner := range pod.OwnerReferences {
+		if owner.Kind == "DaemonSet" {
+			return true, "managed by DaemonSet"
+		}
+	}
+
+	if pod.DeletionTimestamp != nil {
+		return true, "already terminating"
+	}
+
+	if pod.Status.Phase != corev1.PodRunning {
+		return true, "not in Running state"
+	}
+
+	return false, ""
+}
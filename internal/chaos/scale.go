@@ -0,0 +1,221 @@
+package chaos
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/isurusiri/tipsy/internal/utils"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Workload kinds that can be scaled
+const (
+	KindDeployment  = "Deployment"
+	KindStatefulSet = "StatefulSet"
+)
+
+// HPASnapshot records the bounds of a HorizontalPodAutoscaler targeting a scaled workload
+type HPASnapshot struct {
+	Name        string
+	MinReplicas int32
+	MaxReplicas int32
+	Pinned      bool // true if tipsy pinned the HPA bounds to the new replica count
+}
+
+// ScaleResult describes a replica change made by tipsy
+type ScaleResult struct {
+	Kind             string
+	Name             string
+	UID              types.UID
+	OriginalReplicas int32
+	NewReplicas      int32
+	HPA              *HPASnapshot // nil if no HPA targets the workload
+}
+
+// ComputeReplicas works out the new replica count from either an absolute target
+// or a relative change such as "-50%", "+2" or "-1"
+func ComputeReplicas(current int32, to int32, toSet bool, by string) (int32, error) {
+	if toSet {
+		if to < 0 {
+			return 0, fmt.Errorf("replica count cannot be negative: %d", to)
+		}
+		return to, nil
+	}
+
+	if by == "" {
+		return 0, fmt.Errorf("either a target replica count or a relative change is required")
+	}
+
+	var delta float64
+	if strings.HasSuffix(by, "%") {
+		percent, err := strconv.ParseFloat(strings.TrimSuffix(by, "%"), 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid relative change '%s': %w", by, err)
+		}
+		delta = float64(current) * percent / 100
+	} else {
+		value, err := strconv.Atoi(by)
+		if err != nil {
+			return 0, fmt.Errorf("invalid relative change '%s': %w", by, err)
+		}
+		delta = float64(value)
+	}
+
+	replicas := int32(math.Round(float64(current) + delta))
+	if replicas < 0 {
+		replicas = 0
+	}
+
+	return replicas, nil
+}
+
+// ScaleWorkload changes the replica count of a Deployment or StatefulSet
+// If pinHPA is set, an HPA targeting the workload has its bounds pinned to the new count
+func ScaleWorkload(client kubernetes.Interface, namespace, kind, name string, to int32, toSet bool, by string, pinHPA, dryRun bool) (*ScaleResult, error) {
+	utils.Info(fmt.Sprintf("Scaling %s '%s' in namespace '%s'", kind, name, namespace))
+
+	if kind != KindDeployment && kind != KindStatefulSet {
+		return nil, fmt.Errorf("unsupported workload kind: %s", kind)
+	}
+
+	// In dry-run mode, simulate the operation without making API calls
+	if dryRun {
+		utils.DryRun(fmt.Sprintf("Would fetch %s '%s' in namespace '%s'", kind, name, namespace))
+		if toSet {
+			utils.DryRun(fmt.Sprintf("Would scale %s '%s' to %d replica(s)", kind, name, to))
+		} else {
+			utils.DryRun(fmt.Sprintf("Would scale %s '%s' by %s", kind, name, by))
+		}
+		utils.DryRun(fmt.Sprintf("Would check for HorizontalPodAutoscalers targeting %s '%s'", kind, name))
+		return &ScaleResult{Kind: kind, Name: name}, nil
+	}
+
+	current, uid, err := GetReplicas(client, namespace, kind, name)
+	if err != nil {
+		return nil, err
+	}
+
+	replicas, err := ComputeReplicas(current, to, toSet, by)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ScaleResult{Kind: kind, Name: name, UID: uid, OriginalReplicas: current, NewReplicas: replicas}
+
+	hpa, err := findHPA(client, namespace, kind, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if hpa != nil {
+		result.HPA = &HPASnapshot{Name: hpa.Name, MaxReplicas: hpa.Spec.MaxReplicas, MinReplicas: 1}
+		if hpa.Spec.MinReplicas != nil {
+			result.HPA.MinReplicas = *hpa.Spec.MinReplicas
+		}
+
+		if !pinHPA {
+			utils.Warn(fmt.Sprintf("HorizontalPodAutoscaler '%s' (min=%d, max=%d) targets %s '%s' and will fight the change; use --pin-hpa to hold it at %d",
+				hpa.Name, result.HPA.MinReplicas, result.HPA.MaxReplicas, kind, name, replicas))
+		} else if replicas < 1 {
+			utils.Warn(fmt.Sprintf("Cannot pin HorizontalPodAutoscaler '%s' below 1 replica; it will scale %s '%s' back up", hpa.Name, kind, name))
+		} else {
+			hpa.Spec.MinReplicas = &replicas
+			hpa.Spec.MaxReplicas = replicas
+			_, err = client.AutoscalingV2().HorizontalPodAutoscalers(namespace).Update(context.TODO(), hpa, metav1.UpdateOptions{})
+			if err != nil {
+				return nil, fmt.Errorf("failed to pin HorizontalPodAutoscaler '%s': %w", hpa.Name, err)
+			}
+			result.HPA.Pinned = true
+			utils.Info(fmt.Sprintf("Pinned HorizontalPodAutoscaler '%s' to %d replica(s)", hpa.Name, replicas))
+		}
+	}
+
+	if err := SetReplicas(client, namespace, kind, name, replicas); err != nil {
+		return result, err
+	}
+
+	utils.Info(fmt.Sprintf("Scaled %s '%s' from %d to %d replica(s)", kind, name, current, replicas))
+	return result, nil
+}
+
+// SetReplicas updates the replica count of a Deployment or StatefulSet
+func SetReplicas(client kubernetes.Interface, namespace, kind, name string, replicas int32) error {
+	switch kind {
+	case KindDeployment:
+		deployment, err := client.AppsV1().Deployments(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to get deployment '%s': %w", name, err)
+		}
+		deployment.Spec.Replicas = &replicas
+		_, err = client.AppsV1().Deployments(namespace).Update(context.TODO(), deployment, metav1.UpdateOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to scale deployment '%s': %w", name, err)
+		}
+	case KindStatefulSet:
+		statefulSet, err := client.AppsV1().StatefulSets(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to get statefulset '%s': %w", name, err)
+		}
+		statefulSet.Spec.Replicas = &replicas
+		_, err = client.AppsV1().StatefulSets(namespace).Update(context.TODO(), statefulSet, metav1.UpdateOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to scale statefulset '%s': %w", name, err)
+		}
+	default:
+		return fmt.Errorf("unsupported workload kind: %s", kind)
+	}
+
+	return nil
+}
+
+// GetReplicas returns the desired replica count and UID of a Deployment or StatefulSet
+func GetReplicas(client kubernetes.Interface, namespace, kind, name string) (int32, types.UID, error) {
+	var replicas *int32
+	var uid types.UID
+
+	switch kind {
+	case KindDeployment:
+		deployment, err := client.AppsV1().Deployments(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			return 0, "", fmt.Errorf("failed to get deployment '%s': %w", name, err)
+		}
+		replicas = deployment.Spec.Replicas
+		uid = deployment.UID
+	case KindStatefulSet:
+		statefulSet, err := client.AppsV1().StatefulSets(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			return 0, "", fmt.Errorf("failed to get statefulset '%s': %w", name, err)
+		}
+		replicas = statefulSet.Spec.Replicas
+		uid = statefulSet.UID
+	}
+
+	// The API server defaults an unset replica count to 1
+	if replicas == nil {
+		return 1, uid, nil
+	}
+	return *replicas, uid, nil
+}
+
+// findHPA returns the HorizontalPodAutoscaler targeting a workload, or nil if there is none
+func findHPA(client kubernetes.Interface, namespace, kind, name string) (*autoscalingv2.HorizontalPodAutoscaler, error) {
+	hpas, err := client.AutoscalingV2().HorizontalPodAutoscalers(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list horizontal pod autoscalers: %w", err)
+	}
+
+	for i := range hpas.Items {
+		ref := hpas.Items[i].Spec.ScaleTargetRef
+		if ref.Kind == kind && ref.Name == name {
+			return &hpas.Items[i], nil
+		}
+	}
+
+	return nil, nil
+}
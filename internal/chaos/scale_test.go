@@ -0,0 +1,206 @@
+package chaos
+
+import (
+	"context"
+	"testing"
+
+	"github.com/fatih/color"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// Helper function to create a deployment with a replica count
+func createTestDeployment(name string, replicas int32) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+	}
+}
+
+// Helper function to create an HPA targeting a workload
+func createTestHPA(name, kind, target string, minReplicas, maxReplicas int32) *autoscalingv2.HorizontalPodAutoscaler {
+	return &autoscalingv2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{Kind: kind, Name: target},
+			MinReplicas:    &minReplicas,
+			MaxReplicas:    maxReplicas,
+		},
+	}
+}
+
+func TestComputeReplicas(t *testing.T) {
+	testCases := []struct {
+		name        string
+		current     int32
+		to          int32
+		toSet       bool
+		by          string
+		expected    int32
+		expectError bool
+	}{
+		{name: "absolute target", current: 4, to: 1, toSet: true, expected: 1},
+		{name: "absolute zero", current: 4, to: 0, toSet: true, expected: 0},
+		{name: "negative absolute target", current: 4, to: -1, toSet: true, expectError: true},
+		{name: "halve", current: 4, by: "-50%", expected: 2},
+		{name: "halve odd count rounds", current: 5, by: "-50%", expected: 3},
+		{name: "grow by percent", current: 4, by: "+50%", expected: 6},
+		{name: "remove one", current: 3, by: "-1", expected: 2},
+		{name: "never below zero", current: 2, by: "-5", expected: 0},
+		{name: "invalid change", current: 2, by: "half", expectError: true},
+		{name: "no change specified", current: 2, expectError: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			replicas, err := ComputeReplicas(tc.current, tc.to, tc.toSet, tc.by)
+			if tc.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if replicas != tc.expected {
+				t.Errorf("Expected %d replicas, got %d", tc.expected, replicas)
+			}
+		})
+	}
+}
+
+func TestScaleWorkload_Deployment(t *testing.T) {
+	// Disable color for testing
+	originalNoColor := color.NoColor
+	color.NoColor = true
+	defer func() {
+		color.NoColor = originalNoColor
+	}()
+
+	client := fake.NewSimpleClientset(createTestDeployment("web", 4))
+
+	result, err := ScaleWorkload(client, "default", KindDeployment, "web", 0, false, "-50%", false, false)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if result.OriginalReplicas != 4 || result.NewReplicas != 2 {
+		t.Errorf("Expected 4 -> 2 replicas, got %d -> %d", result.OriginalReplicas, result.NewReplicas)
+	}
+	if result.HPA != nil {
+		t.Errorf("Expected no HPA, got %+v", result.HPA)
+	}
+
+	deployment, _ := client.AppsV1().Deployments("default").Get(context.TODO(), "web", metav1.GetOptions{})
+	if *deployment.Spec.Replicas != 2 {
+		t.Errorf("Expected deployment to have 2 replicas, got %d", *deployment.Spec.Replicas)
+	}
+}
+
+func TestScaleWorkload_StatefulSet(t *testing.T) {
+	// Disable color for testing
+	originalNoColor := color.NoColor
+	color.NoColor = true
+	defer func() {
+		color.NoColor = originalNoColor
+	}()
+
+	replicas := int32(3)
+	client := fake.NewSimpleClientset(&appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "default"},
+		Spec:       appsv1.StatefulSetSpec{Replicas: &replicas},
+	})
+
+	result, err := ScaleWorkload(client, "default", KindStatefulSet, "db", 1, true, "", false, false)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if result.OriginalReplicas != 3 || result.NewReplicas != 1 {
+		t.Errorf("Expected 3 -> 1 replicas, got %d -> %d", result.OriginalReplicas, result.NewReplicas)
+	}
+
+	statefulSet, _ := client.AppsV1().StatefulSets("default").Get(context.TODO(), "db", metav1.GetOptions{})
+	if *statefulSet.Spec.Replicas != 1 {
+		t.Errorf("Expected statefulset to have 1 replica, got %d", *statefulSet.Spec.Replicas)
+	}
+}
+
+func TestScaleWorkload_HPA(t *testing.T) {
+	// Disable color for testing
+	originalNoColor := color.NoColor
+	color.NoColor = true
+	defer func() {
+		color.NoColor = originalNoColor
+	}()
+
+	testCases := []struct {
+		name           string
+		pinHPA         bool
+		expectedPinned bool
+		expectedMin    int32
+		expectedMax    int32
+	}{
+		{name: "warn only", pinHPA: false, expectedPinned: false, expectedMin: 2, expectedMax: 10},
+		{name: "pin bounds", pinHPA: true, expectedPinned: true, expectedMin: 1, expectedMax: 1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := fake.NewSimpleClientset(
+				createTestDeployment("web", 4),
+				createTestHPA("web-hpa", KindDeployment, "web", 2, 10),
+				createTestHPA("other-hpa", KindDeployment, "other", 1, 3),
+			)
+
+			result, err := ScaleWorkload(client, "default", KindDeployment, "web", 1, true, "", tc.pinHPA, false)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if result.HPA == nil || result.HPA.Name != "web-hpa" {
+				t.Fatalf("Expected web-hpa to be detected, got %+v", result.HPA)
+			}
+			if result.HPA.MinReplicas != 2 || result.HPA.MaxReplicas != 10 {
+				t.Errorf("Expected original HPA bounds 2-10, got %d-%d", result.HPA.MinReplicas, result.HPA.MaxReplicas)
+			}
+			if result.HPA.Pinned != tc.expectedPinned {
+				t.Errorf("Expected pinned=%t, got %t", tc.expectedPinned, result.HPA.Pinned)
+			}
+
+			hpa, _ := client.AutoscalingV2().HorizontalPodAutoscalers("default").Get(context.TODO(), "web-hpa", metav1.GetOptions{})
+			if *hpa.Spec.MinReplicas != tc.expectedMin || hpa.Spec.MaxReplicas != tc.expectedMax {
+				t.Errorf("Expected HPA bounds %d-%d, got %d-%d", tc.expectedMin, tc.expectedMax, *hpa.Spec.MinReplicas, hpa.Spec.MaxReplicas)
+			}
+		})
+	}
+}
+
+func TestScaleWorkload_Errors(t *testing.T) {
+	client := fake.NewSimpleClientset()
+
+	if _, err := ScaleWorkload(client, "default", "DaemonSet", "agent", 1, true, "", false, false); err == nil {
+		t.Error("Expected error for unsupported workload kind")
+	}
+
+	if _, err := ScaleWorkload(client, "default", KindDeployment, "missing", 1, true, "", false, false); err == nil {
+		t.Error("Expected error for missing deployment")
+	}
+}
+
+func TestScaleWorkload_DryRun(t *testing.T) {
+	client := fake.NewSimpleClientset(createTestDeployment("web", 4))
+
+	_, err := ScaleWorkload(client, "default", KindDeployment, "web", 1, true, "", true, true)
+	if err != nil {
+		t.Fatalf("Unexpected error in dry-run mode: %v", err)
+	}
+
+	deployment, _ := client.AppsV1().Deployments("default").Get(context.TODO(), "web", metav1.GetOptions{})
+	if *deployment.Spec.Replicas != 4 {
+		t.Errorf("Expected deployment to keep 4 replicas in dry-run mode, got %d", *deployment.Spec.Replicas)
+	}
+}
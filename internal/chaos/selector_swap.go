@@ -0,0 +1,98 @@
+package chaos
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/isurusiri/tipsy/internal/utils"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Misroute modes
+const (
+	// MisrouteModeEndpoints rewrites the service's Endpoints and EndpointSlices
+	MisrouteModeEndpoints = "endpoints"
+	// MisrouteModeSelectorSwap patches the service's selector, so the controllers do the rewriting
+	MisrouteModeSelectorSwap = "selector-swap"
+)
+
+// MisroutedLabel is the label a swapped selector requires to match nothing; no pod carries it
+const MisroutedLabel = "tipsy.io/misrouted"
+
+// SelectorSwapResult describes a selector swapped by tipsy
+type SelectorSwapResult struct {
+	Original string // the service's selector before the swap, as a label selector string
+	Swapped  string // the selector tipsy put in its place
+}
+
+// SwapServiceSelector points a service's selector at the pods matching replaceSelector, or,
+// if that is empty, at no pods at all
+// Unlike rewriting the endpoints, this does not race the endpoints controller: the controller
+// itself routes the service to the new pods. Only services with a selector can be swapped
+func SwapServiceSelector(client kubernetes.Interface, svcName, namespace, replaceSelector, actionID string, dryRun bool) (*SelectorSwapResult, error) {
+	utils.Info(fmt.Sprintf("Starting selector swap for service '%s' in namespace '%s'", svcName, namespace))
+
+	// Services select pods by equality only
+	swapped := map[string]string{MisroutedLabel: actionID}
+	if replaceSelector != "" {
+		parsed, err := labels.ConvertSelectorToLabelsMap(replaceSelector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid replacement selector '%s', a service selector takes key=value pairs only: %w", replaceSelector, err)
+		}
+		swapped = parsed
+	}
+
+	if dryRun {
+		utils.DryRun(fmt.Sprintf("Would fetch service '%s' in namespace '%s'", svcName, namespace))
+		utils.DryRun(fmt.Sprintf("Would change the selector of service '%s' to '%s'", svcName, FormatSelector(swapped)))
+		return &SelectorSwapResult{Swapped: FormatSelector(swapped)}, nil
+	}
+
+	service, err := client.CoreV1().Services(namespace).Get(context.TODO(), svcName, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get service '%s': %w", svcName, err)
+	}
+	if len(service.Spec.Selector) == 0 {
+		return nil, fmt.Errorf("service '%s' has no selector to swap; use --mode %s", svcName, MisrouteModeEndpoints)
+	}
+
+	result := &SelectorSwapResult{
+		Original: FormatSelector(service.Spec.Selector),
+		Swapped:  FormatSelector(swapped),
+	}
+
+	if err := SetServiceSelector(client, namespace, svcName, swapped); err != nil {
+		return nil, err
+	}
+
+	utils.Info(fmt.Sprintf("Changed the selector of service '%s' from '%s' to '%s'", svcName, result.Original, result.Swapped))
+	return result, nil
+}
+
+// SetServiceSelector replaces a service's selector outright
+// A JSON patch is used since a merge patch would keep keys missing from the new selector
+func SetServiceSelector(client kubernetes.Interface, namespace, svcName string, selector map[string]string) error {
+	patch := []map[string]interface{}{
+		{"op": "replace", "path": "/spec/selector", "value": selector},
+	}
+
+	patchBytes, err := json.Marshal(patch)
+	if err != nil {
+		return fmt.Errorf("failed to marshal patch: %w", err)
+	}
+
+	_, err = client.CoreV1().Services(namespace).Patch(context.TODO(), svcName, types.JSONPatchType, patchBytes, metav1.PatchOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to patch selector of service '%s': %w", svcName, err)
+	}
+	return nil
+}
+
+// FormatSelector renders a service selector as a label selector string, keys sorted
+func FormatSelector(selector map[string]string) string {
+	return labels.SelectorFromSet(selector).String()
+}
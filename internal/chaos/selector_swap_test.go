@@ -0,0 +1,77 @@
+package chaos
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/fatih/color"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestSwapServiceSelector(t *testing.T) {
+	// Disable color for testing
+	originalNoColor := color.NoColor
+	color.NoColor = true
+	defer func() {
+		color.NoColor = originalNoColor
+	}()
+
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Spec:       corev1.ServiceSpec{Selector: map[string]string{"app": "web", "tier": "frontend"}},
+	}
+
+	tests := []struct {
+		name            string
+		replaceSelector string
+		expected        string
+	}{
+		{name: "replacement selector", replaceSelector: "app=decoy", expected: "app=decoy"},
+		{name: "select nothing", replaceSelector: "", expected: MisroutedLabel + "=abc123"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := fake.NewSimpleClientset(service.DeepCopy())
+
+			result, err := SwapServiceSelector(client, "web", "default", tt.replaceSelector, "abc123", false)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if result.Original != "app=web,tier=frontend" || result.Swapped != tt.expected {
+				t.Errorf("Unexpected result: %+v", result)
+			}
+
+			// Keys of the original selector must not survive the swap
+			updated, err := client.CoreV1().Services("default").Get(context.TODO(), "web", metav1.GetOptions{})
+			if err != nil {
+				t.Fatalf("Failed to get service: %v", err)
+			}
+			if FormatSelector(updated.Spec.Selector) != tt.expected {
+				t.Errorf("Expected selector '%s', got %v", tt.expected, updated.Spec.Selector)
+			}
+		})
+	}
+}
+
+func TestSwapServiceSelectorRefusals(t *testing.T) {
+	// Disable color for testing
+	originalNoColor := color.NoColor
+	color.NoColor = true
+	defer func() {
+		color.NoColor = originalNoColor
+	}()
+
+	selectorless := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"}}
+	client := fake.NewSimpleClientset(selectorless)
+
+	if _, err := SwapServiceSelector(client, "web", "default", "", "abc123", false); err == nil || !strings.Contains(err.Error(), "no selector") {
+		t.Errorf("Expected a service without a selector to be refused, got %v", err)
+	}
+	if _, err := SwapServiceSelector(client, "web", "default", "app in (a,b)", "abc123", false); err == nil {
+		t.Error("Expected a set-based replacement selector to be refused")
+	}
+}
@@ -0,0 +1,182 @@
+package chaos
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/isurusiri/tipsy/internal/utils"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+// PodTarget describes the pods a fault is aimed at
+type PodTarget struct {
+	Namespace      string
+	Selector       string // label selector; may be empty when LeaderLease is set
+	LeaderLease    string // namespace/name of a coordination Lease; narrows the target to the pod holding it
+	Ordinals       []int  // narrows the target to StatefulSet pods with these ordinals
+	ExceptOrdinals []int  // narrows the target to StatefulSet pods without these ordinals
+}
+
+// String describes the target for log messages
+func (t PodTarget) String() string {
+	var description string
+	if t.LeaderLease != "" {
+		if t.Selector != "" {
+			description = fmt.Sprintf("leader of Lease '%s' with selector '%s' in namespace '%s'", t.LeaderLease, t.Selector, t.Namespace)
+		} else {
+			description = fmt.Sprintf("leader of Lease '%s' in namespace '%s'", t.LeaderLease, t.Namespace)
+		}
+	} else {
+		description = fmt.Sprintf("pods with selector '%s' in namespace '%s'", t.Selector, t.Namespace)
+	}
+
+	if len(t.Ordinals) > 0 {
+		description += fmt.Sprintf(" at ordinal(s) %s", formatOrdinals(t.Ordinals))
+	}
+	if len(t.ExceptOrdinals) > 0 {
+		description += fmt.Sprintf(" except ordinal(s) %s", formatOrdinals(t.ExceptOrdinals))
+	}
+
+	return description
+}
+
+// PodRef identifies a pod affected by a fault
+type PodRef struct {
+	Name      string
+	Namespace string
+	Node      string
+	UID       types.UID
+	Owner     *metav1.OwnerReference // controller of the pod, or nil for a bare pod
+	Ordinal   int                    // StatefulSet ordinal, or -1 if the pod does not belong to a StatefulSet
+}
+
+// newPodRef builds a reference to a pod
+func newPodRef(pod corev1.Pod) PodRef {
+	ref := PodRef{Name: pod.Name, Namespace: pod.Namespace, Node: pod.Spec.NodeName, UID: pod.UID, Ordinal: -1}
+	ref.Owner = metav1.GetControllerOf(&pod)
+	if ordinal, ok := PodOrdinal(pod); ok {
+		ref.Ordinal = ordinal
+	}
+	return ref
+}
+
+// PodNames returns the names of the referenced pods
+func PodNames(refs []PodRef) []string {
+	names := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		names = append(names, ref.Name)
+	}
+	return names
+}
+
+// PodOrdinal returns the StatefulSet ordinal of a pod
+// The pod-index label is used where the cluster sets it; otherwise the ordinal is
+// taken from the name suffix of a pod owned by a StatefulSet
+func PodOrdinal(pod corev1.Pod) (int, bool) {
+	if index, exists := pod.Labels[appsv1.PodIndexLabel]; exists {
+		if ordinal, err := strconv.Atoi(index); err == nil {
+			return ordinal, true
+		}
+	}
+
+	for _, owner := range pod.OwnerReferences {
+		if owner.Kind != KindStatefulSet {
+			continue
+		}
+		suffix := strings.TrimPrefix(pod.Name, owner.Name+"-")
+		if suffix == pod.Name {
+			continue
+		}
+		if ordinal, err := strconv.Atoi(suffix); err == nil {
+			return ordinal, true
+		}
+	}
+
+	return 0, false
+}
+
+// SelectPods lists the pods matched by a target
+// An empty result is not an error; callers decide how to report it
+func SelectPods(client kubernetes.Interface, target PodTarget) ([]corev1.Pod, error) {
+	pods, err := client.CoreV1().Pods(target.Namespace).List(context.TODO(), metav1.ListOptions{
+		LabelSelector: target.Selector,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+
+	selected := pods.Items
+
+	if target.LeaderLease != "" {
+		leader, err := ResolveLeaderPod(client, target.LeaderLease)
+		if err != nil {
+			return nil, err
+		}
+
+		selected = nil
+		for _, pod := range pods.Items {
+			if pod.Name == leader {
+				selected = append(selected, pod)
+				break
+			}
+		}
+		if len(selected) == 0 {
+			utils.Warn(fmt.Sprintf("Leader pod '%s' of Lease '%s' not found among %s", leader, target.LeaderLease, target))
+		}
+	}
+
+	if len(target.Ordinals) > 0 || len(target.ExceptOrdinals) > 0 {
+		selected = filterByOrdinal(selected, target.Ordinals, target.ExceptOrdinals)
+	}
+
+	return selected, nil
+}
+
+// filterByOrdinal keeps the StatefulSet pods whose ordinal is in include (if given) and not in exclude
+func filterByOrdinal(pods []corev1.Pod, include, exclude []int) []corev1.Pod {
+	var filtered []corev1.Pod
+
+	for _, pod := range pods {
+		ordinal, ok := PodOrdinal(pod)
+		if !ok {
+			utils.Warn(fmt.Sprintf("Skipping pod '%s' - not part of a StatefulSet", pod.Name))
+			continue
+		}
+
+		if len(include) > 0 && !containsOrdinal(include, ordinal) {
+			continue
+		}
+		if containsOrdinal(exclude, ordinal) {
+			continue
+		}
+
+		filtered = append(filtered, pod)
+	}
+
+	return filtered
+}
+
+// containsOrdinal reports whether an ordinal is in a list
+func containsOrdinal(ordinals []int, ordinal int) bool {
+	for _, candidate := range ordinals {
+		if candidate == ordinal {
+			return true
+		}
+	}
+	return false
+}
+
+// formatOrdinals renders a list of ordinals as "0,2"
+func formatOrdinals(ordinals []int) string {
+	formatted := make([]string, 0, len(ordinals))
+	for _, ordinal := range ordinals {
+		formatted = append(formatted, strconv.Itoa(ordinal))
+	}
+	return strings.Join(formatted, ",")
+}
@@ -0,0 +1,213 @@
+package chaos
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/fatih/color"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// Helper function to create a running controller replica
+func createTestReplica(name string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: map[string]string{"app": "controller"}},
+		Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+}
+
+func TestSelectPods_LeaderLease(t *testing.T) {
+	// Disable color for testing
+	originalNoColor := color.NoColor
+	color.NoColor = true
+	defer func() {
+		color.NoColor = originalNoColor
+	}()
+
+	client := fake.NewSimpleClientset(
+		createTestReplica("controller-0"),
+		createTestReplica("controller-1"),
+		createTestReplica("controller-2"),
+		createTestLease("default", "my-controller", "controller-1_9c1e"),
+	)
+
+	// Without a lease every matching pod is selected
+	pods, err := SelectPods(client, PodTarget{Namespace: "default", Selector: "app=controller"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(pods) != 3 {
+		t.Errorf("Expected 3 pods, got %d", len(pods))
+	}
+
+	// With a lease only the holder is selected, with or without a selector
+	for _, selector := range []string{"app=controller", ""} {
+		pods, err = SelectPods(client, PodTarget{Namespace: "default", Selector: selector, LeaderLease: "default/my-controller"})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(pods) != 1 || pods[0].Name != "controller-1" {
+			t.Errorf("Expected only the leader with selector '%s', got %v", selector, pods)
+		}
+	}
+
+	// A leader outside the selector is not selected
+	pods, err = SelectPods(client, PodTarget{Namespace: "default", Selector: "app=other", LeaderLease: "default/my-controller"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(pods) != 0 {
+		t.Errorf("Expected no pods, got %v", pods)
+	}
+}
+
+func TestKillPodsForTarget_LeaderLease(t *testing.T) {
+	// Disable color for testing
+	originalNoColor := color.NoColor
+	color.NoColor = true
+	defer func() {
+		color.NoColor = originalNoColor
+	}()
+
+	client := fake.NewSimpleClientset(
+		createTestReplica("controller-0"),
+		createTestReplica("controller-1"),
+		createTestLease("default", "my-controller", "controller-0"),
+	)
+
+	killedPods, err := KillPodsForTarget(client, PodTarget{Namespace: "default", LeaderLease: "default/my-controller"}, 1, false)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(killedPods) != 1 || killedPods[0].Name != "controller-0" {
+		t.Errorf("Expected only the leader to be killed, got %v", killedPods)
+	}
+
+	if _, err := client.CoreV1().Pods("default").Get(context.TODO(), "controller-1", metav1.GetOptions{}); err != nil {
+		t.Error("Expected the follower to survive")
+	}
+}
+
+// Helper function to create a running StatefulSet pod
+func createTestStatefulSetPod(statefulSet string, ordinal int) *corev1.Pod {
+	controller := true
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-%d", statefulSet, ordinal),
+			Namespace: "default",
+			Labels:    map[string]string{"app": statefulSet},
+			OwnerReferences: []metav1.OwnerReference{
+				{APIVersion: "apps/v1", Kind: KindStatefulSet, Name: statefulSet, Controller: &controller},
+			},
+		},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+}
+
+func TestPodOrdinal(t *testing.T) {
+	labelled := createTestReplica("anything")
+	labelled.Labels[appsv1.PodIndexLabel] = "4"
+
+	testCases := []struct {
+		name            string
+		pod             *corev1.Pod
+		expectedOrdinal int
+		expectedOK      bool
+	}{
+		{name: "owned by statefulset", pod: createTestStatefulSetPod("db", 2), expectedOrdinal: 2, expectedOK: true},
+		{name: "hyphenated statefulset name", pod: createTestStatefulSetPod("kafka-broker", 10), expectedOrdinal: 10, expectedOK: true},
+		{name: "pod index label", pod: labelled, expectedOrdinal: 4, expectedOK: true},
+		{name: "not a statefulset pod", pod: createTestReplica("web-5"), expectedOK: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ordinal, ok := PodOrdinal(*tc.pod)
+			if ok != tc.expectedOK {
+				t.Fatalf("Expected ok=%t, got %t", tc.expectedOK, ok)
+			}
+			if ok && ordinal != tc.expectedOrdinal {
+				t.Errorf("Expected ordinal %d, got %d", tc.expectedOrdinal, ordinal)
+			}
+		})
+	}
+}
+
+func TestSelectPods_Ordinals(t *testing.T) {
+	// Disable color for testing
+	originalNoColor := color.NoColor
+	color.NoColor = true
+	defer func() {
+		color.NoColor = originalNoColor
+	}()
+
+	client := fake.NewSimpleClientset(
+		createTestStatefulSetPod("db", 0),
+		createTestStatefulSetPod("db", 1),
+		createTestStatefulSetPod("db", 2),
+	)
+
+	testCases := []struct {
+		name     string
+		target   PodTarget
+		expected []string
+	}{
+		{name: "primary only", target: PodTarget{Namespace: "default", Selector: "app=db", Ordinals: []int{0}}, expected: []string{"db-0"}},
+		{name: "all but the primary", target: PodTarget{Namespace: "default", Selector: "app=db", ExceptOrdinals: []int{0}}, expected: []string{"db-1", "db-2"}},
+		{name: "missing ordinal", target: PodTarget{Namespace: "default", Selector: "app=db", Ordinals: []int{7}}, expected: nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			pods, err := SelectPods(client, tc.target)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			var names []string
+			for _, pod := range pods {
+				names = append(names, pod.Name)
+			}
+			sort.Strings(names)
+
+			if strings.Join(names, ",") != strings.Join(tc.expected, ",") {
+				t.Errorf("Expected %v, got %v", tc.expected, names)
+			}
+		})
+	}
+}
+
+func TestKillPodsForTarget_RecordsOrdinal(t *testing.T) {
+	// Disable color for testing
+	originalNoColor := color.NoColor
+	color.NoColor = true
+	defer func() {
+		color.NoColor = originalNoColor
+	}()
+
+	primary := createTestStatefulSetPod("db", 0)
+	primary.UID = "uid-db-0"
+	client := fake.NewSimpleClientset(primary, createTestStatefulSetPod("db", 1))
+
+	killedPods, err := KillPodsForTarget(client, PodTarget{Namespace: "default", Selector: "app=db", Ordinals: []int{0}}, 1, false)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(killedPods) != 1 {
+		t.Fatalf("Expected 1 killed pod, got %v", killedPods)
+	}
+	if killedPods[0].Name != "db-0" || killedPods[0].Ordinal != 0 || killedPods[0].UID != "uid-db-0" {
+		t.Errorf("Unexpected pod reference: %+v", killedPods[0])
+	}
+	if owner := killedPods[0].Owner; owner == nil || owner.Kind != KindStatefulSet || owner.Name != "db" {
+		t.Errorf("Expected the StatefulSet owner to be recorded, got %+v", owner)
+	}
+}
@@ -6,8 +6,17 @@ type Config struct {
 	Namespace  string
 	DryRun     bool
 	Verbose    bool
+
+	StateBackend   string // where actions are recorded: StateBackendFile or StateBackendConfigMap
+	StateNamespace string // namespace of the state ConfigMap for StateBackendConfigMap
 }
 
+// State backends
+const (
+	StateBackendFile      = "file"
+	StateBackendConfigMap = "configmap"
+)
+
 // Global config instance
 var GlobalConfig Config
 
@@ -0,0 +1,63 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// ClusterIdentity identifies the cluster a client is connected to
+type ClusterIdentity struct {
+	Server  string // API server URL
+	Context string // kubeconfig context name; empty when running in-cluster
+	UID     string // UID of the kube-system namespace, which lives as long as the cluster does
+}
+
+// GetClusterIdentity identifies the cluster selected by kubeconfigPath, following the same
+// priority order as NewClient
+// The server and context come from the kubeconfig; the kube-system UID is read through the client.
+// If the UID cannot be read, the identity is returned without it along with the error
+func GetClusterIdentity(kubeconfigPath string, client kubernetes.Interface) (ClusterIdentity, error) {
+	var identity ClusterIdentity
+
+	config, err := GetConfig(kubeconfigPath)
+	if err != nil {
+		return identity, err
+	}
+	identity.Server = config.Host
+	identity.Context = currentContext(kubeconfigPath)
+
+	namespace, err := client.CoreV1().Namespaces().Get(context.TODO(), "kube-system", metav1.GetOptions{})
+	if err != nil {
+		return identity, fmt.Errorf("failed to get kube-system namespace: %w", err)
+	}
+	identity.UID = string(namespace.UID)
+
+	return identity, nil
+}
+
+// currentContext returns the current context of the kubeconfig NewClient would use,
+// or an empty string when running in-cluster or if the kubeconfig cannot be read
+func currentContext(kubeconfigPath string) string {
+	if kubeconfigPath == "" {
+		if IsInCluster() {
+			return ""
+		}
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		kubeconfigPath = filepath.Join(homeDir, ".kube", "config")
+	}
+
+	rawConfig, err := clientcmd.LoadFromFile(kubeconfigPath)
+	if err != nil {
+		return ""
+	}
+	return rawConfig.CurrentContext
+}
@@ -0,0 +1,39 @@
+package k8s
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestGetClusterIdentity(t *testing.T) {
+	kubeconfigPath := createTempKubeconfig(t)
+	client := fake.NewSimpleClientset(&corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "kube-system", UID: "cluster-uid"},
+	})
+
+	identity, err := GetClusterIdentity(kubeconfigPath, client)
+	if err != nil {
+		t.Fatalf("Failed to get cluster identity: %v", err)
+	}
+
+	expected := ClusterIdentity{Server: "https://test-server:6443", Context: "test-context", UID: "cluster-uid"}
+	if identity != expected {
+		t.Errorf("Expected identity %+v, got %+v", expected, identity)
+	}
+}
+
+func TestGetClusterIdentity_WithoutKubeSystemAccess(t *testing.T) {
+	kubeconfigPath := createTempKubeconfig(t)
+
+	// Without the kube-system namespace the identity falls back to the server and context
+	identity, err := GetClusterIdentity(kubeconfigPath, fake.NewSimpleClientset())
+	if err == nil {
+		t.Error("Expected an error when kube-system cannot be read")
+	}
+	if identity.Server != "https://test-server:6443" || identity.Context != "test-context" || identity.UID != "" {
+		t.Errorf("Expected the server and context without a UID, got %+v", identity)
+	}
+}
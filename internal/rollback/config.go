@@ -0,0 +1,147 @@
+package rollback
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/isurusiri/tipsy/internal/chaos"
+	"github.com/isurusiri/tipsy/internal/state"
+	"github.com/isurusiri/tipsy/internal/utils"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// RestoreConfig restores a ConfigMap or Secret from backup, refusing to
+// overwrite it if someone else has changed or recreated it since the fault was
+// injected, unless force is set
+func RestoreConfig(client kubernetes.Interface, action state.ChaosAction, dryRun, force bool) error {
+	// Config fault actions use TargetPod to hold the object name
+	kind := action.Metadata["kind"]
+	name := action.TargetPod
+	utils.Info(fmt.Sprintf("Restoring %s '%s' in namespace '%s'", kind, name, action.Namespace))
+
+	backupPath, exists := action.Metadata["backupPath"]
+	if !exists || backupPath == "" {
+		return fmt.Errorf("missing backupPath in action metadata")
+	}
+
+	if dryRun {
+		utils.DryRun(fmt.Sprintf("Would check %s '%s' for changes made since the fault was injected", kind, name))
+		utils.DryRun(fmt.Sprintf("Would restore %s '%s' from backup: %s", kind, name, backupPath))
+		return nil
+	}
+
+	backupData, err := os.ReadFile(backupPath)
+	if err != nil {
+		return fmt.Errorf("failed to read backup file %s: %w", backupPath, err)
+	}
+
+	switch kind {
+	case chaos.KindConfigMap:
+		err = restoreConfigMap(client, action, backupData, force)
+	case chaos.KindSecret:
+		err = restoreSecret(client, action, backupData, force)
+	default:
+		return fmt.Errorf("unsupported config kind: %s", kind)
+	}
+	if err != nil {
+		return err
+	}
+
+	// Clean up the backup file
+	err = os.Remove(backupPath)
+	if err != nil {
+		utils.Warn(fmt.Sprintf("Failed to remove backup file %s: %v", backupPath, err))
+	}
+
+	utils.Info(fmt.Sprintf("Successfully restored %s '%s'", kind, name))
+	return nil
+}
+
+// restoreConfigMap writes the backed up data back into a ConfigMap
+func restoreConfigMap(client kubernetes.Interface, action state.ChaosAction, backupData []byte, force bool) error {
+	var original corev1.ConfigMap
+	if err := json.Unmarshal(backupData, &original); err != nil {
+		return fmt.Errorf("failed to unmarshal backup configmap: %w", err)
+	}
+
+	current, err := client.CoreV1().ConfigMaps(action.Namespace).Get(context.TODO(), action.TargetPod, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get configmap: %w", err)
+	}
+
+	if err := checkConfigDrift(action, string(current.UID), chaos.ConfigMapChecksum(current), force); err != nil {
+		return err
+	}
+
+	// Keep the current resourceVersion so a concurrent change makes the update fail
+	current.Data = original.Data
+	current.BinaryData = original.BinaryData
+	_, err = client.CoreV1().ConfigMaps(action.Namespace).Update(context.TODO(), current, metav1.UpdateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to restore configmap: %w", err)
+	}
+
+	return nil
+}
+
+// restoreSecret writes the backed up data back into a Secret
+func restoreSecret(client kubernetes.Interface, action state.ChaosAction, backupData []byte, force bool) error {
+	var original corev1.Secret
+	if err := json.Unmarshal(backupData, &original); err != nil {
+		return fmt.Errorf("failed to unmarshal backup secret: %w", err)
+	}
+
+	current, err := client.CoreV1().Secrets(action.Namespace).Get(context.TODO(), action.TargetPod, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get secret: %w", err)
+	}
+
+	if err := checkConfigDrift(action, string(current.UID), chaos.SecretChecksum(current), force); err != nil {
+		return err
+	}
+
+	// Keep the current resourceVersion so a concurrent change makes the update fail
+	current.Data = original.Data
+	_, err = client.CoreV1().Secrets(action.Namespace).Update(context.TODO(), current, metav1.UpdateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to restore secret: %w", err)
+	}
+
+	return nil
+}
+
+// checkConfigDrift compares the object's UID and current checksum with the ones recorded
+// when the fault was injected
+// A recreated or changed object is only overwritten when force is set
+func checkConfigDrift(action state.ChaosAction, currentUID, currentChecksum string, force bool) error {
+	kind, name := action.Metadata["kind"], action.TargetPod
+
+	if action.TargetUID != "" && currentUID != action.TargetUID {
+		if !force {
+			return fmt.Errorf("%s '%s' has been recreated since the fault was injected; refusing to overwrite it, use --force to restore the original anyway (saved in %s)",
+				kind, name, action.Metadata["backupPath"])
+		}
+		utils.Warn(fmt.Sprintf("%s '%s' has been recreated since the fault was injected; restoring the original anyway", kind, name))
+		return nil
+	}
+
+	expected := action.Metadata["checksum"]
+	if expected == "" {
+		utils.Warn(fmt.Sprintf("No checksum recorded for %s '%s'; restoring without a drift check", kind, name))
+		return nil
+	}
+
+	if currentChecksum != expected {
+		if !force {
+			return fmt.Errorf("%s '%s' has been changed since the fault was injected; refusing to overwrite it, use --force to restore the original anyway (saved in %s)",
+				kind, name, action.Metadata["backupPath"])
+		}
+		utils.Warn(fmt.Sprintf("%s '%s' has been changed since the fault was injected; restoring the original anyway", kind, name))
+	}
+
+	return nil
+}
@@ -0,0 +1,124 @@
+package rollback
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/isurusiri/tipsy/internal/chaos"
+	"github.com/isurusiri/tipsy/internal/state"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// injectConfigFault mutates a configmap and returns the action tipsy would save for it
+func injectConfigFault(t *testing.T) (*fake.Clientset, state.ChaosAction) {
+	t.Setenv("HOME", t.TempDir())
+
+	client := fake.NewSimpleClientset(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "app-config", Namespace: "default", UID: "original"},
+		Data:       map[string]string{"log_level": "info"},
+	})
+
+	result, err := chaos.MutateConfig(client, "default", chaos.KindConfigMap, "app-config", map[string]string{"log_level": "garbage"}, nil, false)
+	if err != nil {
+		t.Fatalf("Failed to inject config fault: %v", err)
+	}
+
+	return client, state.ChaosAction{
+		Type:      "config-fault",
+		TargetUID: string(result.UID),
+		TargetPod: "app-config",
+		Namespace: "default",
+		Metadata: map[string]string{
+			"kind":       chaos.KindConfigMap,
+			"backupPath": result.BackupPath,
+			"checksum":   result.Checksum,
+		},
+	}
+}
+
+func TestRestoreConfig(t *testing.T) {
+	client, action := injectConfigFault(t)
+
+	// Test dry run
+	if err := RestoreConfig(client, action, true, false); err != nil {
+		t.Errorf("Unexpected error in dry run: %v", err)
+	}
+
+	configMap, _ := client.CoreV1().ConfigMaps("default").Get(context.TODO(), "app-config", metav1.GetOptions{})
+	if configMap.Data["log_level"] != "garbage" {
+		t.Error("Expected configmap to be unchanged in dry run")
+	}
+
+	// Test actual execution
+	if err := RestoreConfig(client, action, false, false); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	configMap, _ = client.CoreV1().ConfigMaps("default").Get(context.TODO(), "app-config", metav1.GetOptions{})
+	if configMap.Data["log_level"] != "info" {
+		t.Errorf("Expected original log_level after rollback, got '%s'", configMap.Data["log_level"])
+	}
+
+	if _, err := os.Stat(action.Metadata["backupPath"]); !os.IsNotExist(err) {
+		t.Error("Expected backup file to be removed after rollback")
+	}
+}
+
+func TestRestoreConfig_Drift(t *testing.T) {
+	client, action := injectConfigFault(t)
+
+	// Someone else edits the configmap after the fault was injected
+	configMap, _ := client.CoreV1().ConfigMaps("default").Get(context.TODO(), "app-config", metav1.GetOptions{})
+	configMap.Data["log_level"] = "warn"
+	client.CoreV1().ConfigMaps("default").Update(context.TODO(), configMap, metav1.UpdateOptions{})
+
+	if err := RestoreConfig(client, action, false, false); err == nil {
+		t.Fatal("Expected rollback to refuse to overwrite a changed configmap")
+	}
+
+	configMap, _ = client.CoreV1().ConfigMaps("default").Get(context.TODO(), "app-config", metav1.GetOptions{})
+	if configMap.Data["log_level"] != "warn" {
+		t.Errorf("Expected the other change to be kept, got '%s'", configMap.Data["log_level"])
+	}
+
+	if _, err := os.Stat(action.Metadata["backupPath"]); err != nil {
+		t.Error("Expected backup file to be kept when rollback is refused")
+	}
+
+	// Force overwrites the other change
+	if err := RestoreConfig(client, action, false, true); err != nil {
+		t.Fatalf("Unexpected error with force: %v", err)
+	}
+
+	configMap, _ = client.CoreV1().ConfigMaps("default").Get(context.TODO(), "app-config", metav1.GetOptions{})
+	if configMap.Data["log_level"] != "info" {
+		t.Errorf("Expected original log_level after a forced rollback, got '%s'", configMap.Data["log_level"])
+	}
+}
+
+func TestRestoreConfig_Recreated(t *testing.T) {
+	client, action := injectConfigFault(t)
+
+	// The configmap is deleted and recreated with the faulty data still in it
+	client.CoreV1().ConfigMaps("default").Delete(context.TODO(), "app-config", metav1.DeleteOptions{})
+	client.CoreV1().ConfigMaps("default").Create(context.TODO(), &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "app-config", Namespace: "default", UID: "recreated"},
+		Data:       map[string]string{"log_level": "garbage"},
+	}, metav1.CreateOptions{})
+
+	if err := RestoreConfig(client, action, false, false); err == nil {
+		t.Fatal("Expected rollback to refuse to overwrite a recreated configmap")
+	}
+
+	if err := RestoreConfig(client, action, false, true); err != nil {
+		t.Fatalf("Unexpected error with force: %v", err)
+	}
+
+	configMap, _ := client.CoreV1().ConfigMaps("default").Get(context.TODO(), "app-config", metav1.GetOptions{})
+	if configMap.Data["log_level"] != "info" {
+		t.Errorf("Expected original log_level after a forced rollback, got '%s'", configMap.Data["log_level"])
+	}
+}
@@ -0,0 +1,112 @@
+package rollback
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/isurusiri/tipsy/internal/chaos"
+	"github.com/isurusiri/tipsy/internal/state"
+	"github.com/isurusiri/tipsy/internal/utils"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
+)
+
+// RestoreNode undoes only the change a single node action made, so faults stacked on one
+// node can be rolled back independently
+// Cordon and drain make the node schedulable again if tipsy cordoned it; taint removes the
+// taint tipsy added and leaves every other taint in place
+func RestoreNode(client kubernetes.Interface, action state.ChaosAction, dryRun bool) error {
+	// Node actions use TargetPod to hold the node name
+	nodeName := action.TargetPod
+	utils.Info(fmt.Sprintf("Restoring node '%s'", nodeName))
+
+	var (
+		uncordon bool
+		taint    corev1.Taint
+	)
+	switch action.Type {
+	case "taint":
+		spec, exists := action.Metadata["taint"]
+		if !exists {
+			return fmt.Errorf("missing taint in action metadata")
+		}
+		parsed, err := chaos.ParseTaint(spec)
+		if err != nil {
+			return err
+		}
+		taint = parsed
+	default:
+		// Older cordon actions carry no snapshot; they were only recorded for schedulable nodes
+		uncordon = true
+		if value, exists := action.Metadata["originalUnschedulable"]; exists {
+			wasUnschedulable, err := strconv.ParseBool(value)
+			if err != nil {
+				return fmt.Errorf("invalid originalUnschedulable value '%s': %w", value, err)
+			}
+			uncordon = !wasUnschedulable
+		}
+	}
+
+	if dryRun {
+		if action.Type == "taint" {
+			utils.DryRun(fmt.Sprintf("Would remove taint '%s' from node '%s'", taint.ToString(), nodeName))
+		} else if uncordon {
+			utils.DryRun(fmt.Sprintf("Would uncordon node '%s'", nodeName))
+		} else {
+			utils.DryRun(fmt.Sprintf("Would leave node '%s' cordoned, as it was before", nodeName))
+		}
+		return nil
+	}
+
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		node, err := client.CoreV1().Nodes().Get(context.TODO(), nodeName, metav1.GetOptions{})
+		var uid types.UID
+		if node != nil {
+			uid = node.UID
+		}
+		if recreated(action, fmt.Sprintf("node '%s'", nodeName), uid, err) {
+			return ErrResolvedByRecreation
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get node: %w", err)
+		}
+
+		changed := false
+		if action.Type == "taint" {
+			taints := []corev1.Taint{}
+			for _, existing := range node.Spec.Taints {
+				if existing.MatchTaint(&taint) {
+					changed = true
+					continue
+				}
+				taints = append(taints, existing)
+			}
+			node.Spec.Taints = taints
+		} else if uncordon && node.Spec.Unschedulable {
+			node.Spec.Unschedulable = false
+			changed = true
+		}
+
+		if !changed {
+			utils.Info(fmt.Sprintf("Node '%s' has nothing left to restore", nodeName))
+			return nil
+		}
+
+		_, err = client.CoreV1().Nodes().Update(context.TODO(), node, metav1.UpdateOptions{})
+		return err
+	})
+	if errors.Is(err, ErrResolvedByRecreation) {
+		return err
+	}
+	if err != nil {
+		return fmt.Errorf("failed to restore node: %w", err)
+	}
+
+	utils.Info(fmt.Sprintf("Successfully restored node '%s'", nodeName))
+	return nil
+}
@@ -0,0 +1,183 @@
+package rollback
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/isurusiri/tipsy/internal/chaos"
+	"github.com/isurusiri/tipsy/internal/state"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestRestoreNode(t *testing.T) {
+	// The node as tipsy left it: with an extra chaos taint, and cordoned by someone else
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "worker-1"},
+		Spec: corev1.NodeSpec{
+			Unschedulable: true,
+			Taints: []corev1.Taint{
+				{Key: "gpu", Value: "true", Effect: corev1.TaintEffectNoSchedule},
+				{Key: "tipsy.io/chaos", Value: "true", Effect: corev1.TaintEffectNoSchedule},
+			},
+		},
+	}
+
+	client := fake.NewSimpleClientset(node)
+	action := state.ChaosAction{
+		Type:      "taint",
+		TargetPod: "worker-1",
+		Metadata: map[string]string{
+			"taint":                 "tipsy.io/chaos=true:NoSchedule",
+			"originalUnschedulable": "false",
+		},
+	}
+
+	// Test dry run
+	err := RestoreNode(client, action, true)
+	if err != nil {
+		t.Errorf("Unexpected error in dry run: %v", err)
+	}
+
+	updated, _ := client.CoreV1().Nodes().Get(context.TODO(), "worker-1", metav1.GetOptions{})
+	if !updated.Spec.Unschedulable || len(updated.Spec.Taints) != 2 {
+		t.Error("Expected node to be unchanged in dry run")
+	}
+
+	// Test actual execution
+	err = RestoreNode(client, action, false)
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	updated, _ = client.CoreV1().Nodes().Get(context.TODO(), "worker-1", metav1.GetOptions{})
+	if !updated.Spec.Unschedulable {
+		t.Error("Expected a taint rollback to leave the cordon alone")
+	}
+	if len(updated.Spec.Taints) != 1 || updated.Spec.Taints[0].Key != "gpu" {
+		t.Errorf("Expected only the original taint to remain, got %v", updated.Spec.Taints)
+	}
+}
+
+func TestRollbackAll_StackedNodeFaults(t *testing.T) {
+	t.Cleanup(state.ReloadStateFilePath)
+	t.Setenv("TIPSY_STATE_FILE", filepath.Join(t.TempDir(), "state.json"))
+	state.ReloadStateFilePath()
+
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "worker-1"},
+		Spec: corev1.NodeSpec{
+			Taints: []corev1.Taint{{Key: "gpu", Value: "true", Effect: corev1.TaintEffectNoSchedule}},
+		},
+	}
+	client := fake.NewSimpleClientset(node)
+
+	// Cordon, then taint the same node, recording each as the node command does
+	cordoned, err := chaos.CordonNode(client, "worker-1", false)
+	if err != nil {
+		t.Fatalf("Failed to cordon node: %v", err)
+	}
+	taint, _ := chaos.ParseTaint(chaos.DefaultChaosTaint)
+	tainted, err := chaos.TaintNodes(client, []string{"worker-1"}, taint, false)
+	if err != nil || len(tainted) != 1 {
+		t.Fatalf("Failed to taint node: %v", err)
+	}
+
+	for _, action := range []state.ChaosAction{
+		{ID: "cordon1", Type: "cordon", TargetPod: "worker-1", Metadata: map[string]string{
+			"originalUnschedulable": strconv.FormatBool(cordoned.Unschedulable),
+		}},
+		{ID: "taint1", Type: "taint", TargetPod: "worker-1", Metadata: map[string]string{
+			"taint":                 chaos.DefaultChaosTaint,
+			"originalUnschedulable": strconv.FormatBool(tainted[0].Unschedulable),
+		}},
+	} {
+		if err := state.SaveAction(action); err != nil {
+			t.Fatalf("Failed to save action: %v", err)
+		}
+	}
+
+	// A taint added by someone else after the faults must survive the rollback
+	current, _ := client.CoreV1().Nodes().Get(context.TODO(), "worker-1", metav1.GetOptions{})
+	current.Spec.Taints = append(current.Spec.Taints, corev1.Taint{Key: "maintenance", Effect: corev1.TaintEffectNoSchedule})
+	if _, err := client.CoreV1().Nodes().Update(context.TODO(), current, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("Failed to update node: %v", err)
+	}
+
+	if err := RollbackAll(client, false, false, "", "", nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	updated, _ := client.CoreV1().Nodes().Get(context.TODO(), "worker-1", metav1.GetOptions{})
+	if updated.Spec.Unschedulable {
+		t.Error("Expected node to be schedulable after rolling back both faults")
+	}
+	if len(updated.Spec.Taints) != 2 || updated.Spec.Taints[0].Key != "gpu" || updated.Spec.Taints[1].Key != "maintenance" {
+		t.Errorf("Expected only the chaos taint removed, got %v", updated.Spec.Taints)
+	}
+}
+
+func TestRestoreNode_LegacyCordon(t *testing.T) {
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "worker-1"},
+		Spec: corev1.NodeSpec{
+			Unschedulable: true,
+			Taints:        []corev1.Taint{{Key: "gpu", Effect: corev1.TaintEffectNoSchedule}},
+		},
+	}
+
+	client := fake.NewSimpleClientset(node)
+
+	// Cordon actions without a snapshot only uncordon and leave taints alone
+	err := RestoreNode(client, state.ChaosAction{Type: "cordon", TargetPod: "worker-1"}, false)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	updated, _ := client.CoreV1().Nodes().Get(context.TODO(), "worker-1", metav1.GetOptions{})
+	if updated.Spec.Unschedulable {
+		t.Error("Expected node to be schedulable after rollback")
+	}
+	if len(updated.Spec.Taints) != 1 {
+		t.Errorf("Expected taints to be left alone, got %v", updated.Spec.Taints)
+	}
+
+	// Test missing node
+	err = RestoreNode(client, state.ChaosAction{Type: "cordon", TargetPod: "missing"}, false)
+	if err == nil {
+		t.Error("Expected error for missing node")
+	}
+}
+
+func TestRestoreNode_Recreated(t *testing.T) {
+	client := fake.NewSimpleClientset(&corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "worker-1", UID: "uid-new"},
+		Spec:       corev1.NodeSpec{Unschedulable: true},
+	})
+
+	action := state.ChaosAction{
+		Type:      "cordon",
+		TargetPod: "worker-1",
+		TargetUID: "uid-old",
+		Metadata:  map[string]string{"originalUnschedulable": "false"},
+	}
+
+	// A replacement node with the same name is left alone
+	if err := RestoreNode(client, action, false); !errors.Is(err, ErrResolvedByRecreation) {
+		t.Fatalf("Expected ErrResolvedByRecreation, got %v", err)
+	}
+	node, _ := client.CoreV1().Nodes().Get(context.TODO(), "worker-1", metav1.GetOptions{})
+	if !node.Spec.Unschedulable {
+		t.Error("Expected the replacement node to keep its own cordon")
+	}
+
+	// So is a node that is gone
+	client.CoreV1().Nodes().Delete(context.TODO(), "worker-1", metav1.DeleteOptions{})
+	if err := RestoreNode(client, action, false); !errors.Is(err, ErrResolvedByRecreation) {
+		t.Errorf("Expected ErrResolvedByRecreation for a deleted node, got %v", err)
+	}
+}
@@ -0,0 +1,75 @@
+package rollback
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/isurusiri/tipsy/internal/state"
+	"github.com/isurusiri/tipsy/internal/utils"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+// ErrResolvedByRecreation is returned when the object an action affected no longer exists,
+// so the fault went away with it and there is nothing left to roll back
+var ErrResolvedByRecreation = errors.New("resolved by recreation")
+
+// recreated reports whether the object an action affected is gone or has been replaced by a
+// new object with the same name, given the result of fetching it
+// Actions recorded without a UID are never treated as recreated
+func recreated(action state.ChaosAction, description string, uid types.UID, getErr error) bool {
+	if action.TargetUID == "" {
+		return false
+	}
+
+	if getErr != nil {
+		if apierrors.IsNotFound(getErr) {
+			utils.Info(fmt.Sprintf("The original %s is gone", description))
+			return true
+		}
+		return false
+	}
+
+	if string(uid) != action.TargetUID {
+		utils.Info(fmt.Sprintf("The original %s is gone and has been replaced by a new one; leaving the replacement alone", description))
+		return true
+	}
+	return false
+}
+
+// getOriginalPod fetches the pod an action affected
+// Returns ErrResolvedByRecreation if that pod is gone, including when a controller has replaced it
+// with a new pod of the same name, as a StatefulSet does with db-0 after a kill
+func getOriginalPod(client kubernetes.Interface, action state.ChaosAction) (*corev1.Pod, error) {
+	pod, err := client.CoreV1().Pods(action.Namespace).Get(context.TODO(), action.TargetPod, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) && action.TargetUID != "" {
+			utils.Info(fmt.Sprintf("The original %s is gone", describePod(action)))
+			return nil, ErrResolvedByRecreation
+		}
+		return nil, fmt.Errorf("failed to get pod: %w", err)
+	}
+
+	if action.TargetUID != "" && string(pod.UID) != action.TargetUID {
+		utils.Info(fmt.Sprintf("The original %s is gone and has been replaced by a new pod; leaving the replacement alone", describePod(action)))
+		return nil, ErrResolvedByRecreation
+	}
+
+	return pod, nil
+}
+
+// describePod names the target pod of an action, with its owner and ordinal where known
+func describePod(action state.ChaosAction) string {
+	description := fmt.Sprintf("pod '%s'", action.TargetPod)
+	if action.Owner != nil {
+		description += fmt.Sprintf(" of %s '%s'", action.Owner.Kind, action.Owner.Name)
+	}
+	if ordinal, exists := action.Metadata["ordinal"]; exists {
+		description += fmt.Sprintf(" (ordinal %s)", ordinal)
+	}
+	return description
+}
@@ -0,0 +1,110 @@
+package rollback
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/isurusiri/tipsy/internal/state"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestRollback_RecreatedPod(t *testing.T) {
+	// db-0 was recreated by its StatefulSet after the fault was injected
+	replacement := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "db-0", Namespace: "default", UID: "uid-new"},
+		Spec: corev1.PodSpec{
+			EphemeralContainers: []corev1.EphemeralContainer{
+				{EphemeralContainerCommon: corev1.EphemeralContainerCommon{Name: "tipsy-debug"}},
+			},
+		},
+	}
+
+	for _, actionType := range []string{"latency", "cpustress", "readiness-flap", "resize-squeeze"} {
+		t.Run(actionType, func(t *testing.T) {
+			client := fake.NewSimpleClientset(replacement)
+			action := state.ChaosAction{
+				Type:      actionType,
+				TargetPod: "db-0",
+				Namespace: "default",
+				TargetUID: "uid-original",
+				Owner:     &state.OwnerRef{Kind: "StatefulSet", Name: "db", UID: "uid-db"},
+				Metadata:  map[string]string{"ordinal": "0", "originalResources": "{}"},
+			}
+
+			err := rollbackAction(client, action, false, false)
+			if !errors.Is(err, ErrResolvedByRecreation) {
+				t.Fatalf("Expected ErrResolvedByRecreation, got %v", err)
+			}
+
+			for _, a := range client.Actions() {
+				if a.GetVerb() != "get" {
+					t.Errorf("Expected the replacement pod to be left alone, got %s %s", a.GetVerb(), a.GetSubresource())
+				}
+			}
+		})
+	}
+
+	// The original pod is still there, so rollback mutates it as usual
+	original := replacement.DeepCopy()
+	original.UID = "uid-original"
+	client := fake.NewSimpleClientset(original)
+	action := state.ChaosAction{Type: "latency", TargetPod: "db-0", Namespace: "default", TargetUID: "uid-original"}
+
+	if err := rollbackAction(client, action, false, false); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	patched := false
+	for _, a := range client.Actions() {
+		if a.GetVerb() == "patch" {
+			patched = true
+		}
+	}
+	if !patched {
+		t.Error("Expected the original pod to be rolled back")
+	}
+}
+
+func TestRollback_DeletedPod(t *testing.T) {
+	client := fake.NewSimpleClientset()
+
+	// With a recorded UID, a missing pod means the fault went away with it
+	action := state.ChaosAction{Type: "latency", TargetPod: "web-abc", Namespace: "default", TargetUID: "uid-original"}
+	if err := rollbackAction(client, action, false, false); !errors.Is(err, ErrResolvedByRecreation) {
+		t.Errorf("Expected ErrResolvedByRecreation, got %v", err)
+	}
+
+	// Without one, the pod may simply be unreachable, so the failure is reported
+	action.TargetUID = ""
+	if err := rollbackAction(client, action, false, false); err == nil || errors.Is(err, ErrResolvedByRecreation) {
+		t.Errorf("Expected a failure to get the pod, got %v", err)
+	}
+}
+
+func TestRollbackActions_ResolvedByRecreation(t *testing.T) {
+	// Registered first so it runs after the environment is restored
+	t.Cleanup(state.ReloadStateFilePath)
+	t.Setenv("TIPSY_STATE_FILE", filepath.Join(t.TempDir(), "state.json"))
+	state.ReloadStateFilePath()
+
+	action := state.ChaosAction{Type: "cpustress", TargetPod: "db-0", Namespace: "default", Timestamp: "2024-01-01T00:00:00Z", TargetUID: "uid-original"}
+	if err := state.SaveAction(action); err != nil {
+		t.Fatalf("Failed to save action: %v", err)
+	}
+
+	successCount, failedActions := RollbackActions(fake.NewSimpleClientset(), []state.ChaosAction{action}, false, false)
+	if successCount != 1 || len(failedActions) != 0 {
+		t.Errorf("Expected the action to count as rolled back, got %d successful and %d failed", successCount, len(failedActions))
+	}
+
+	remaining, err := state.LoadActions()
+	if err != nil {
+		t.Fatalf("Failed to load actions: %v", err)
+	}
+	if len(remaining) != 0 {
+		t.Errorf("Expected the resolved action to leave state, got %v", remaining)
+	}
+}
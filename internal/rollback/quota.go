@@ -0,0 +1,47 @@
+package rollback
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/isurusiri/tipsy/internal/chaos"
+	"github.com/isurusiri/tipsy/internal/state"
+	"github.com/isurusiri/tipsy/internal/utils"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// DeleteQuota removes the ResourceQuota created by a quota squeeze
+func DeleteQuota(client kubernetes.Interface, action state.ChaosAction, dryRun bool) error {
+	// Quota squeeze actions use TargetPod to hold the quota name
+	name := action.TargetPod
+	utils.Info(fmt.Sprintf("Deleting ResourceQuota '%s' in namespace '%s'", name, action.Namespace))
+
+	if dryRun {
+		utils.DryRun(fmt.Sprintf("Would delete ResourceQuota '%s' in namespace '%s'", name, action.Namespace))
+		return nil
+	}
+
+	quota, err := client.CoreV1().ResourceQuotas(action.Namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		utils.Info(fmt.Sprintf("ResourceQuota '%s' no longer exists", name))
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get resource quota: %w", err)
+	}
+
+	// Never delete a quota that tipsy did not create
+	if quota.Labels[chaos.ManagedByLabel] != chaos.ManagedByValue {
+		return fmt.Errorf("resource quota '%s' is not managed by tipsy; refusing to delete it", name)
+	}
+
+	err = client.CoreV1().ResourceQuotas(action.Namespace).Delete(context.TODO(), name, metav1.DeleteOptions{})
+	if err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete resource quota: %w", err)
+	}
+
+	utils.Info(fmt.Sprintf("Successfully deleted ResourceQuota '%s'", name))
+	return nil
+}
@@ -0,0 +1,52 @@
+package rollback
+
+import (
+	"context"
+	"testing"
+
+	"github.com/isurusiri/tipsy/internal/chaos"
+	"github.com/isurusiri/tipsy/internal/state"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestDeleteQuota(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		&corev1.ResourceQuota{ObjectMeta: metav1.ObjectMeta{
+			Name:      chaos.QuotaSqueezeName,
+			Namespace: "checkout",
+			Labels:    map[string]string{chaos.ManagedByLabel: chaos.ManagedByValue},
+		}},
+		&corev1.ResourceQuota{ObjectMeta: metav1.ObjectMeta{Name: "team-quota", Namespace: "checkout"}},
+	)
+
+	action := state.ChaosAction{Type: "quota-squeeze", TargetPod: chaos.QuotaSqueezeName, Namespace: "checkout"}
+
+	// Test dry run
+	if err := DeleteQuota(client, action, true); err != nil {
+		t.Errorf("Unexpected error in dry run: %v", err)
+	}
+	if _, err := client.CoreV1().ResourceQuotas("checkout").Get(context.TODO(), chaos.QuotaSqueezeName, metav1.GetOptions{}); err != nil {
+		t.Error("Expected quota to be kept in dry run")
+	}
+
+	// Test actual execution
+	if err := DeleteQuota(client, action, false); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := client.CoreV1().ResourceQuotas("checkout").Get(context.TODO(), chaos.QuotaSqueezeName, metav1.GetOptions{}); err == nil {
+		t.Error("Expected quota to be deleted")
+	}
+
+	// A quota that is already gone counts as rolled back
+	if err := DeleteQuota(client, action, false); err != nil {
+		t.Errorf("Unexpected error for missing quota: %v", err)
+	}
+
+	// Quotas that tipsy did not create are never deleted
+	foreign := state.ChaosAction{Type: "quota-squeeze", TargetPod: "team-quota", Namespace: "checkout"}
+	if err := DeleteQuota(client, foreign, false); err == nil {
+		t.Error("Expected error for a quota not managed by tipsy")
+	}
+}
@@ -0,0 +1,34 @@
+package rollback
+
+import (
+	"fmt"
+
+	"github.com/isurusiri/tipsy/internal/chaos"
+	"github.com/isurusiri/tipsy/internal/state"
+	"github.com/isurusiri/tipsy/internal/utils"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// RestoreReadiness leaves the tipsy readiness condition of a flapped pod True
+func RestoreReadiness(client kubernetes.Interface, action state.ChaosAction, dryRun bool) error {
+	utils.Info(fmt.Sprintf("Restoring readiness for pod '%s' in namespace '%s'", action.TargetPod, action.Namespace))
+
+	if dryRun {
+		utils.DryRun(fmt.Sprintf("Would set condition '%s' to True on pod '%s'", chaos.ReadinessGateCondition, action.TargetPod))
+		return nil
+	}
+
+	// A replacement pod was never flapped
+	if _, err := getOriginalPod(client, action); err != nil {
+		return err
+	}
+
+	err := chaos.SetReadinessCondition(client, action.Namespace, action.TargetPod, corev1.ConditionTrue)
+	if err != nil {
+		return err
+	}
+
+	utils.Info(fmt.Sprintf("Successfully restored readiness for pod '%s'", action.TargetPod))
+	return nil
+}
@@ -0,0 +1,43 @@
+package rollback
+
+import (
+	"context"
+	"testing"
+
+	"github.com/isurusiri/tipsy/internal/chaos"
+	"github.com/isurusiri/tipsy/internal/state"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestRestoreReadiness(t *testing.T) {
+	client := fake.NewSimpleClientset(&corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "api-1", Namespace: "default"},
+		Status: corev1.PodStatus{
+			Conditions: []corev1.PodCondition{{Type: chaos.ReadinessGateCondition, Status: corev1.ConditionFalse}},
+		},
+	})
+
+	action := state.ChaosAction{Type: "readiness-flap", TargetPod: "api-1", Namespace: "default"}
+
+	// Test dry run
+	if err := RestoreReadiness(client, action, true); err != nil {
+		t.Errorf("Unexpected error in dry run: %v", err)
+	}
+
+	pod, _ := client.CoreV1().Pods("default").Get(context.TODO(), "api-1", metav1.GetOptions{})
+	if pod.Status.Conditions[0].Status != corev1.ConditionFalse {
+		t.Error("Expected condition to be unchanged in dry run")
+	}
+
+	// Test actual execution
+	if err := RestoreReadiness(client, action, false); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	pod, _ = client.CoreV1().Pods("default").Get(context.TODO(), "api-1", metav1.GetOptions{})
+	if pod.Status.Conditions[0].Status != corev1.ConditionTrue {
+		t.Errorf("Expected condition True after rollback, got '%s'", pod.Status.Conditions[0].Status)
+	}
+}
@@ -0,0 +1,44 @@
+package rollback
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/isurusiri/tipsy/internal/chaos"
+	"github.com/isurusiri/tipsy/internal/state"
+	"github.com/isurusiri/tipsy/internal/utils"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// RestoreResources restores the original container resources of a squeezed pod
+func RestoreResources(client kubernetes.Interface, action state.ChaosAction, dryRun bool) error {
+	utils.Info(fmt.Sprintf("Restoring resources for pod '%s' in namespace '%s'", action.TargetPod, action.Namespace))
+
+	encoded, exists := action.Metadata["originalResources"]
+	if !exists {
+		return fmt.Errorf("missing originalResources in action metadata")
+	}
+
+	var original map[string]corev1.ResourceRequirements
+	if err := json.Unmarshal([]byte(encoded), &original); err != nil {
+		return fmt.Errorf("failed to unmarshal original resources: %w", err)
+	}
+
+	if dryRun {
+		utils.DryRun(fmt.Sprintf("Would resize %d container(s) in pod '%s' back to their original resources", len(original), action.TargetPod))
+		return nil
+	}
+
+	// A replacement pod was never squeezed
+	if _, err := getOriginalPod(client, action); err != nil {
+		return err
+	}
+
+	if err := chaos.RestorePodResources(client, action.Namespace, action.TargetPod, original); err != nil {
+		return err
+	}
+
+	utils.Info(fmt.Sprintf("Successfully restored resources for pod '%s'", action.TargetPod))
+	return nil
+}
@@ -0,0 +1,61 @@
+package rollback
+
+import (
+	"context"
+	"testing"
+
+	"github.com/isurusiri/tipsy/internal/state"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestRestoreResources(t *testing.T) {
+	client := fake.NewSimpleClientset(&corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "api-1", Namespace: "default"},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{
+				Name: "api",
+				Resources: corev1.ResourceRequirements{
+					Limits: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")},
+				},
+			}},
+		},
+	})
+
+	action := state.ChaosAction{
+		Type:      "resize-squeeze",
+		TargetPod: "api-1",
+		Namespace: "default",
+		Metadata: map[string]string{
+			"originalResources": `{"api":{"limits":{"cpu":"1"}}}`,
+		},
+	}
+
+	// Test dry run
+	if err := RestoreResources(client, action, true); err != nil {
+		t.Errorf("Unexpected error in dry run: %v", err)
+	}
+
+	pod, _ := client.CoreV1().Pods("default").Get(context.TODO(), "api-1", metav1.GetOptions{})
+	if pod.Spec.Containers[0].Resources.Limits.Cpu().String() != "100m" {
+		t.Error("Expected resources to be unchanged in dry run")
+	}
+
+	// Test actual execution
+	if err := RestoreResources(client, action, false); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	pod, _ = client.CoreV1().Pods("default").Get(context.TODO(), "api-1", metav1.GetOptions{})
+	if pod.Spec.Containers[0].Resources.Limits.Cpu().String() != "1" {
+		t.Errorf("Expected original CPU limit after rollback, got %s", pod.Spec.Containers[0].Resources.Limits.Cpu().String())
+	}
+
+	// Test missing metadata
+	action.Metadata = map[string]string{}
+	if err := RestoreResources(client, action, false); err == nil {
+		t.Error("Expected error when original resources are missing")
+	}
+}
@@ -3,21 +3,26 @@ package rollback
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 
+	"github.com/isurusiri/tipsy/internal/chaos"
 	"github.com/isurusiri/tipsy/internal/state"
 	"github.com/isurusiri/tipsy/internal/utils"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
 )
 
 // RollbackAll rolls back all chaos actions or filtered actions
-func RollbackAll(client kubernetes.Interface, dryRun bool, filterType, filterPod string) error {
+// Only actions recorded against the given cluster are rolled back; a nil cluster means every cluster
+// With force, targets changed since the fault was injected are restored anyway, see RestoreEndpoints
+func RollbackAll(client kubernetes.Interface, dryRun, force bool, filterType, filterPod string, cluster *state.ClusterRef) error {
 	utils.Info("Starting rollback operation")
 
 	// Load all actions from state
@@ -31,6 +36,12 @@ func RollbackAll(client kubernetes.Interface, dryRun bool, filterType, filterPod
 		return nil
 	}
 
+	// Leave alone actions recorded against other clusters
+	actions, skipped := state.FilterByCluster(actions, cluster)
+	if skipped > 0 {
+		utils.Info(fmt.Sprintf("Skipping %d action(s) recorded against other clusters; use --all-clusters to include them", skipped))
+	}
+
 	// Filter actions if filters are provided
 	filteredActions := filterActions(actions, filterType, filterPod)
 	if len(filteredActions) == 0 {
@@ -40,42 +51,83 @@ func RollbackAll(client kubernetes.Interface, dryRun bool, filterType, filterPod
 
 	utils.Info(fmt.Sprintf("Found %d action(s) to rollback", len(filteredActions)))
 
-	var successCount, failureCount int
+	successCount, failedActions := RollbackActions(client, filteredActions, dryRun, force)
+	failureCount := len(failedActions)
+
+	// Print summary
+	if dryRun {
+		utils.Info(fmt.Sprintf("Dry run completed: %d action(s) would be rolled back", len(filteredActions)))
+	} else {
+		utils.Info(fmt.Sprintf("Rollback completed: %d successful, %d failed", successCount, failureCount))
+		if len(failedActions) > 0 {
+			utils.Warn(fmt.Sprintf("Failed to rollback %d action(s)", len(failedActions)))
+		}
+	}
+
+	return nil
+}
+
+// RollbackByID rolls back the single action with the given ID
+// The action must have been recorded against the given cluster; a nil cluster accepts any
+func RollbackByID(client kubernetes.Interface, id string, dryRun, force bool, cluster *state.ClusterRef) error {
+	action, err := state.FindAction(id)
+	if err != nil {
+		return err
+	}
+
+	if !action.OnCluster(cluster) {
+		return fmt.Errorf("action '%s' was recorded against cluster '%s', not the current cluster '%s'; use --all-clusters to roll it back anyway",
+			id, action.Cluster, cluster)
+	}
+
+	utils.Info(fmt.Sprintf("Found %s action '%s' for '%s' to rollback", action.Type, id, action.TargetPod))
+
+	_, failedActions := RollbackActions(client, []state.ChaosAction{action}, dryRun, force)
+	if len(failedActions) > 0 {
+		return fmt.Errorf("failed to rollback action '%s'", id)
+	}
+
+	if dryRun {
+		utils.Info(fmt.Sprintf("Dry run completed: action '%s' would be rolled back", id))
+	} else {
+		utils.Info(fmt.Sprintf("Rollback completed: action '%s' rolled back", id))
+	}
+	return nil
+}
+
+// RollbackActions rolls back the given actions and removes the successful ones from state
+// Returns the number of successful rollbacks and the actions that failed
+func RollbackActions(client kubernetes.Interface, actions []state.ChaosAction, dryRun, force bool) (int, []state.ChaosAction) {
+	var successCount int
 	var failedActions []state.ChaosAction
 
 	// Process each action
-	for _, action := range filteredActions {
+	for _, action := range actions {
 		utils.Info(fmt.Sprintf("Rolling back %s action for pod '%s' in namespace '%s'", 
 			action.Type, action.TargetPod, action.Namespace))
 
-		err := rollbackAction(client, action, dryRun)
+		err := rollbackAction(client, action, dryRun, force)
+		if errors.Is(err, ErrResolvedByRecreation) {
+			// Nothing left to revert, so the action is settled and can leave state
+			utils.Info(fmt.Sprintf("The %s action for '%s' was resolved by recreation", action.Type, action.TargetPod))
+			err = nil
+		}
 		if err != nil {
 			utils.Error(fmt.Sprintf("Failed to rollback action: %v", err))
 			failedActions = append(failedActions, action)
-			failureCount++
 		} else {
-			// Only remove from state if not dry run and rollback was successful
-			if !dryRun {
-				err = state.DeleteAction(action)
-				if err != nil {
-					utils.Warn(fmt.Sprintf("Failed to remove action from state: %v", err))
-				}
-			}
 			successCount++
 		}
-	}
 
-	// Print summary
-	if dryRun {
-		utils.Info(fmt.Sprintf("Dry run completed: %d action(s) would be rolled back", len(filteredActions)))
-	} else {
-		utils.Info(fmt.Sprintf("Rollback completed: %d successful, %d failed", successCount, failureCount))
-		if len(failedActions) > 0 {
-			utils.Warn(fmt.Sprintf("Failed to rollback %d action(s)", len(failedActions)))
+		// Record the attempt unless dry run; a successful rollback removes the action from state
+		if !dryRun {
+			if _, recordErr := state.RecordRollback(action, err); recordErr != nil {
+				utils.Warn(fmt.Sprintf("Failed to record rollback in state: %v", recordErr))
+			}
 		}
 	}
 
-	return nil
+	return successCount, failedActions
 }
 
 // filterActions filters actions based on type and pod filters
@@ -100,16 +152,31 @@ func filterActions(actions []state.ChaosAction, filterType, filterPod string) []
 }
 
 // rollbackAction rolls back a specific action based on its type
-func rollbackAction(client kubernetes.Interface, action state.ChaosAction, dryRun bool) error {
+func rollbackAction(client kubernetes.Interface, action state.ChaosAction, dryRun, force bool) error {
 	switch action.Type {
 	case "latency", "packetloss":
 		return RevertTC(client, action, dryRun)
 	case "cpustress":
 		return RemoveEphemeral(client, action, dryRun)
 	case "misroute":
-		return RestoreEndpoints(client, action, dryRun)
+		if action.Metadata["mode"] == chaos.MisrouteModeSelectorSwap {
+			return RestoreSelector(client, action, dryRun, force)
+		}
+		return RestoreEndpoints(client, action, dryRun, force)
 	case "kill":
 		return handleKillAction(client, action, dryRun)
+	case "cordon", "drain", "taint":
+		return RestoreNode(client, action, dryRun)
+	case "scale":
+		return RestoreReplicas(client, action, dryRun)
+	case "config-fault":
+		return RestoreConfig(client, action, dryRun, force)
+	case "quota-squeeze":
+		return DeleteQuota(client, action, dryRun)
+	case "resize-squeeze":
+		return RestoreResources(client, action, dryRun)
+	case "readiness-flap":
+		return RestoreReadiness(client, action, dryRun)
 	default:
 		return fmt.Errorf("unknown action type: %s", action.Type)
 	}
@@ -125,10 +192,10 @@ func RevertTC(client kubernetes.Interface, action state.ChaosAction, dryRun bool
 		return nil
 	}
 
-	// Get the pod to find ephemeral containers
-	pod, err := client.CoreV1().Pods(action.Namespace).Get(context.TODO(), action.TargetPod, metav1.GetOptions{})
+	// Get the pod to find ephemeral containers; a replacement pod never had the fault injected
+	pod, err := getOriginalPod(client, action)
 	if err != nil {
-		return fmt.Errorf("failed to get pod: %w", err)
+		return err
 	}
 
 	// Find and remove ephemeral containers that match our pattern
@@ -168,10 +235,10 @@ func RemoveEphemeral(client kubernetes.Interface, action state.ChaosAction, dryR
 		return nil
 	}
 
-	// Get the pod to find ephemeral containers
-	pod, err := client.CoreV1().Pods(action.Namespace).Get(context.TODO(), action.TargetPod, metav1.GetOptions{})
+	// Get the pod to find ephemeral containers; a replacement pod never had the fault injected
+	pod, err := getOriginalPod(client, action)
 	if err != nil {
-		return fmt.Errorf("failed to get pod: %w", err)
+		return err
 	}
 
 	// Find ephemeral containers that match our pattern
@@ -200,8 +267,11 @@ func RemoveEphemeral(client kubernetes.Interface, action state.ChaosAction, dryR
 	return nil
 }
 
-// RestoreEndpoints restores original service endpoints from backup
-func RestoreEndpoints(client kubernetes.Interface, action state.ChaosAction, dryRun bool) error {
+// RestoreEndpoints restores original service endpoints and endpointslices from backup
+// While other misroutes of the same service remain, the action's backup is only dropped
+// from the stack; the original is restored when the last of them is rolled back
+// Endpoints changed by someone else since the misroute are left alone unless force is set
+func RestoreEndpoints(client kubernetes.Interface, action state.ChaosAction, dryRun, force bool) error {
 	utils.Info(fmt.Sprintf("Restoring endpoints for service '%s' in namespace '%s'", 
 		action.TargetPod, action.Namespace))
 
@@ -217,39 +287,96 @@ func RestoreEndpoints(client kubernetes.Interface, action state.ChaosAction, dry
 	}
 
 	if dryRun {
+		utils.DryRun(fmt.Sprintf("Would check endpoints for service '%s' for changes made since the misroute", action.TargetPod))
 		utils.DryRun(fmt.Sprintf("Would restore endpoints for service '%s' from backup: %s", action.TargetPod, backupPath))
+		utils.DryRun(fmt.Sprintf("Would replace the tipsy-owned endpointslices of service '%s' with the original ones", action.TargetPod))
 		return nil
 	}
 
-	// Read the backup file
-	backupData, err := os.ReadFile(backupPath)
+	// Read the backup, verifying its checksum
+	backup, err := chaos.ReadEndpointsBackup(backupPath)
 	if err != nil {
-		return fmt.Errorf("failed to read backup file %s: %w", backupPath, err)
+		return err
 	}
 
-	// Unmarshal the backup endpoints
-	var backupEndpoints corev1.Endpoints
-	err = json.Unmarshal(backupData, &backupEndpoints)
-	if err != nil {
-		return fmt.Errorf("failed to unmarshal backup endpoints: %w", err)
+	if !backup.Cluster.Matches(state.CurrentCluster()) {
+		return fmt.Errorf("backup %s was taken on cluster '%s', not the current cluster '%s'",
+			backupPath, backup.Cluster, state.CurrentCluster())
 	}
 
-	// Update the endpoints
-	_, err = client.CoreV1().Endpoints(action.Namespace).Update(context.TODO(), &backupEndpoints, metav1.UpdateOptions{})
+	// Backups written before they were stacked stand alone
+	if backup.Version > 0 {
+		stack, err := chaos.EndpointsBackupStack(backupPath)
+		if err != nil {
+			return err
+		}
+		if len(stack) > 1 {
+			if err := chaos.DropEndpointsBackup(backupPath); err != nil {
+				return err
+			}
+			utils.Info(fmt.Sprintf("Service '%s' stays misrouted by %d other action(s); its original endpoints are restored when the last is rolled back",
+				action.TargetPod, len(stack)-1))
+			return nil
+		}
+	}
+
+	// Restore onto the live object, checking it against what tipsy left behind, and start over
+	// from a fresh copy whenever someone else updates it in between
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		current, err := client.CoreV1().Endpoints(action.Namespace).Get(context.TODO(), action.TargetPod, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to get endpoints: %w", err)
+		}
+
+		if err := checkEndpointsDrift(action, backup, current, force); err != nil {
+			return err
+		}
+
+		// Keep the current resourceVersion so a concurrent change makes the update conflict
+		current.Subsets = backup.Endpoints.Subsets
+		_, err = client.CoreV1().Endpoints(action.Namespace).Update(context.TODO(), current, metav1.UpdateOptions{})
+		return err
+	})
 	if err != nil {
 		return fmt.Errorf("failed to restore endpoints: %w", err)
 	}
 
+	// Hand the service back its own EndpointSlices
+	if err := chaos.RestoreEndpointSlices(client, backup); err != nil {
+		return fmt.Errorf("failed to restore endpointslices: %w", err)
+	}
+
 	// Clean up the backup file
-	err = os.Remove(backupPath)
+	err = chaos.RemoveEndpointsBackup(backupPath)
 	if err != nil {
-		utils.Warn(fmt.Sprintf("Failed to remove backup file %s: %v", backupPath, err))
+		utils.Warn(err.Error())
 	}
 
 	utils.Info(fmt.Sprintf("Successfully restored endpoints for service '%s'", action.TargetPod))
 	return nil
 }
 
+// checkEndpointsDrift compares a service's current endpoints with those tipsy left behind
+// when it misrouted them; a change made since is refused unless forced
+func checkEndpointsDrift(action state.ChaosAction, backup *chaos.EndpointsBackup, current *corev1.Endpoints, force bool) error {
+	if backup.Applied == "" {
+		utils.Warn(fmt.Sprintf("No checksum recorded for the misrouted endpoints of service '%s'; restoring without a drift check", action.TargetPod))
+		return nil
+	}
+
+	if chaos.EndpointsChecksum(current) == backup.Applied {
+		return nil
+	}
+
+	if !force {
+		return fmt.Errorf("endpoints for service '%s' have been changed since they were misrouted; refusing to overwrite them, use --force to restore the original anyway",
+			action.TargetPod)
+	}
+
+	utils.Warn(fmt.Sprintf("Endpoints for service '%s' have been changed since they were misrouted; restoring the original anyway", action.TargetPod))
+	return nil
+}
+
 // removeEphemeralContainer removes a specific ephemeral container from a pod
 func removeEphemeralContainer(client kubernetes.Interface, namespace, podName, containerName string) error {
 	// Get current pod
@@ -1,16 +1,22 @@
 package rollback
 
 import (
+	"context"
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/isurusiri/tipsy/internal/chaos"
 	"github.com/isurusiri/tipsy/internal/state"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
 )
 
 func TestFilterActions(t *testing.T) {
@@ -154,7 +160,7 @@ func TestRollbackAction(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := rollbackAction(client, tt.action, tt.dryRun)
+			err := rollbackAction(client, tt.action, tt.dryRun, false)
 			
 			if tt.expectError && err == nil {
 				t.Error("Expected error but got none")
@@ -312,13 +318,13 @@ func TestRestoreEndpoints(t *testing.T) {
 	}
 
 	// Test dry run
-	err = RestoreEndpoints(client, action, true)
+	err = RestoreEndpoints(client, action, true, false)
 	if err != nil {
 		t.Errorf("Unexpected error in dry run: %v", err)
 	}
 
 	// Test actual execution
-	err = RestoreEndpoints(client, action, false)
+	err = RestoreEndpoints(client, action, false, false)
 	if err != nil {
 		t.Errorf("Unexpected error: %v", err)
 	}
@@ -434,25 +440,25 @@ func TestRollbackAll(t *testing.T) {
 	client := fake.NewSimpleClientset()
 
 	// Test dry run
-	err = RollbackAll(client, true, "", "")
+	err = RollbackAll(client, true, false, "", "", nil)
 	if err != nil {
 		t.Errorf("Unexpected error in dry run: %v", err)
 	}
 
 	// Test with type filter
-	err = RollbackAll(client, true, "latency", "")
+	err = RollbackAll(client, true, false, "latency", "", nil)
 	if err != nil {
 		t.Errorf("Unexpected error with type filter: %v", err)
 	}
 
 	// Test with pod filter
-	err = RollbackAll(client, true, "", "test-pod-1")
+	err = RollbackAll(client, true, false, "", "test-pod-1", nil)
 	if err != nil {
 		t.Errorf("Unexpected error with pod filter: %v", err)
 	}
 
 	// Test with no matches
-	err = RollbackAll(client, true, "nonexistent", "")
+	err = RollbackAll(client, true, false, "nonexistent", "", nil)
 	if err != nil {
 		t.Errorf("Unexpected error with no matches: %v", err)
 	}
@@ -462,3 +468,262 @@ func TestRollbackAll(t *testing.T) {
 func createFakeClient(objects ...runtime.Object) *fake.Clientset {
 	return fake.NewSimpleClientset(objects...)
 }
+
+func TestRollbackByID(t *testing.T) {
+	// Registered first so it runs after the environment is restored
+	t.Cleanup(state.ReloadStateFilePath)
+	t.Setenv("TIPSY_STATE_FILE", filepath.Join(t.TempDir(), "state.json"))
+	state.ReloadStateFilePath()
+
+	// Two kill actions on the same pod recorded within the same second
+	for _, id := range []string{"first", "second"} {
+		action := state.ChaosAction{ID: id, Type: "kill", TargetPod: "db-0", Namespace: "default", Timestamp: "2023-01-01T00:00:00Z"}
+		if err := state.SaveAction(action); err != nil {
+			t.Fatalf("Failed to save action: %v", err)
+		}
+	}
+
+	client := fake.NewSimpleClientset()
+
+	if err := RollbackByID(client, "second", false, false, nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	remaining, err := state.LoadActions()
+	if err != nil {
+		t.Fatalf("Failed to load actions: %v", err)
+	}
+	if len(remaining) != 1 || remaining[0].ID != "first" {
+		t.Errorf("Expected only the first action to remain, got %+v", remaining)
+	}
+
+	if err := RollbackByID(client, "missing", false, false, nil); err == nil {
+		t.Error("Expected an error for an unknown ID")
+	}
+}
+
+func TestRollback_OnlyCurrentCluster(t *testing.T) {
+	// Registered first so it runs after the environment is restored
+	t.Cleanup(state.ReloadStateFilePath)
+	t.Setenv("TIPSY_STATE_FILE", filepath.Join(t.TempDir(), "state.json"))
+	state.ReloadStateFilePath()
+
+	staging := &state.ClusterRef{Server: "https://staging:6443", Context: "staging", UID: "staging-uid"}
+	prod := &state.ClusterRef{Server: "https://prod:6443", Context: "prod", UID: "prod-uid"}
+
+	for _, action := range []state.ChaosAction{
+		{ID: "on-staging", Type: "kill", TargetPod: "web-1", Namespace: "default", Cluster: staging},
+		{ID: "on-prod", Type: "kill", TargetPod: "web-2", Namespace: "default", Cluster: prod},
+		{ID: "legacy", Type: "kill", TargetPod: "web-3", Namespace: "default"},
+	} {
+		if err := state.SaveAction(action); err != nil {
+			t.Fatalf("Failed to save action: %v", err)
+		}
+	}
+
+	client := fake.NewSimpleClientset()
+
+	if err := RollbackByID(client, "on-prod", false, false, staging); err == nil || !strings.Contains(err.Error(), "--all-clusters") {
+		t.Errorf("Expected rolling back a prod action against staging to be refused, got %v", err)
+	}
+
+	// Actions recorded before clusters were tracked cannot be told apart, so they are kept in scope
+	if err := RollbackAll(client, false, false, "", "", staging); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	remaining, err := state.LoadActions()
+	if err != nil {
+		t.Fatalf("Failed to load actions: %v", err)
+	}
+	if len(remaining) != 1 || remaining[0].ID != "on-prod" {
+		t.Errorf("Expected only the prod action to remain, got %+v", remaining)
+	}
+
+	if err := RollbackByID(client, "on-prod", false, false, nil); err != nil {
+		t.Errorf("Expected the prod action to be rolled back across clusters, got %v", err)
+	}
+}
+
+func TestRestoreEndpoints_StackedMisroutes(t *testing.T) {
+	// Keep backups out of the real home directory
+	t.Setenv("HOME", t.TempDir())
+
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Spec:       corev1.ServiceSpec{Ports: []corev1.ServicePort{{Name: "http", Port: 80, Protocol: corev1.ProtocolTCP}}},
+	}
+	original := &corev1.Endpoints{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Subsets: []corev1.EndpointSubset{{
+			Addresses: []corev1.EndpointAddress{{IP: "10.0.0.1"}},
+			Ports:     []corev1.EndpointPort{{Name: "http", Port: 80, Protocol: corev1.ProtocolTCP}},
+		}},
+	}
+	client := fake.NewSimpleClientset(service, original)
+
+	// Misroute the same service twice
+	actions := []state.ChaosAction{}
+	for _, id := range []string{"first", "second"} {
+		backupPath, err := chaos.MisrouteService(client, "web", "default", chaos.MisrouteOptions{RemoveAll: true}, false, id, nil)
+		if err != nil {
+			t.Fatalf("Failed to misroute service: %v", err)
+		}
+		actions = append(actions, state.ChaosAction{
+			ID: id, Type: "misroute", TargetPod: "web", Namespace: "default",
+			Metadata: map[string]string{"backupPath": backupPath},
+		})
+	}
+
+	// Rolling back one of them leaves the service misrouted by the other
+	if err := RestoreEndpoints(client, actions[0], false, false); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	endpoints, err := client.CoreV1().Endpoints("default").Get(context.TODO(), "web", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Failed to get endpoints: %v", err)
+	}
+	if len(endpoints.Subsets) != 0 {
+		t.Errorf("Expected the service to stay misrouted, got %+v", endpoints.Subsets)
+	}
+
+	// Rolling back the last restores the true original, not the misrouted endpoints the second backup took
+	if err := RestoreEndpoints(client, actions[1], false, false); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	endpoints, err = client.CoreV1().Endpoints("default").Get(context.TODO(), "web", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Failed to get endpoints: %v", err)
+	}
+	if len(endpoints.Subsets) != 1 || endpoints.Subsets[0].Addresses[0].IP != "10.0.0.1" {
+		t.Errorf("Expected the original endpoints to be restored, got %+v", endpoints.Subsets)
+	}
+	if _, err := os.Stat(filepath.Dir(actions[1].Metadata["backupPath"])); !os.IsNotExist(err) {
+		t.Error("Expected the backup stack to be cleaned up")
+	}
+}
+
+func TestRestoreEndpoints_OtherCluster(t *testing.T) {
+	// Keep backups out of the real home directory
+	t.Setenv("HOME", t.TempDir())
+
+	service := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"}}
+	endpoints := &corev1.Endpoints{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"}}
+	client := fake.NewSimpleClientset(service, endpoints)
+
+	prod := &state.ClusterRef{Server: "https://prod:6443", UID: "prod-uid"}
+	backupPath, err := chaos.MisrouteService(client, "web", "default", chaos.MisrouteOptions{RemoveAll: true}, false, "abc123", prod)
+	if err != nil {
+		t.Fatalf("Failed to misroute service: %v", err)
+	}
+
+	state.SetCluster(&state.ClusterRef{Server: "https://staging:6443", UID: "staging-uid"})
+	t.Cleanup(func() { state.SetCluster(nil) })
+
+	action := state.ChaosAction{ID: "abc123", Type: "misroute", TargetPod: "web", Namespace: "default",
+		Metadata: map[string]string{"backupPath": backupPath}}
+	if err := RestoreEndpoints(client, action, false, false); err == nil || !strings.Contains(err.Error(), "cluster") {
+		t.Errorf("Expected restoring a prod backup on staging to be refused, got %v", err)
+	}
+}
+
+// misrouteForRestore misroutes a service with a single endpoint and returns the client and action
+func misrouteForRestore(t *testing.T) (*fake.Clientset, state.ChaosAction) {
+	t.Helper()
+
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Spec:       corev1.ServiceSpec{Ports: []corev1.ServicePort{{Name: "http", Port: 80, Protocol: corev1.ProtocolTCP}}},
+	}
+	original := &corev1.Endpoints{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Subsets: []corev1.EndpointSubset{{
+			Addresses: []corev1.EndpointAddress{{IP: "10.0.0.1"}},
+			Ports:     []corev1.EndpointPort{{Name: "http", Port: 80, Protocol: corev1.ProtocolTCP}},
+		}},
+	}
+	client := fake.NewSimpleClientset(service, original)
+
+	backupPath, err := chaos.MisrouteService(client, "web", "default", chaos.MisrouteOptions{RemoveAll: true}, false, "abc123", nil)
+	if err != nil {
+		t.Fatalf("Failed to misroute service: %v", err)
+	}
+
+	return client, state.ChaosAction{ID: "abc123", Type: "misroute", TargetPod: "web", Namespace: "default",
+		Metadata: map[string]string{"backupPath": backupPath}}
+}
+
+func TestRestoreEndpoints_Drift(t *testing.T) {
+	// Keep backups out of the real home directory
+	t.Setenv("HOME", t.TempDir())
+
+	client, action := misrouteForRestore(t)
+
+	// Someone else changes the misrouted endpoints
+	changed, err := client.CoreV1().Endpoints("default").Get(context.TODO(), "web", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Failed to get endpoints: %v", err)
+	}
+	changed.Subsets = []corev1.EndpointSubset{{Addresses: []corev1.EndpointAddress{{IP: "10.0.0.7"}}}}
+	if _, err := client.CoreV1().Endpoints("default").Update(context.TODO(), changed, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("Failed to update endpoints: %v", err)
+	}
+
+	if err := RestoreEndpoints(client, action, false, false); err == nil || !strings.Contains(err.Error(), "--force") {
+		t.Fatalf("Expected restoring changed endpoints to be refused, got %v", err)
+	}
+	endpoints, err := client.CoreV1().Endpoints("default").Get(context.TODO(), "web", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Failed to get endpoints: %v", err)
+	}
+	if endpoints.Subsets[0].Addresses[0].IP != "10.0.0.7" {
+		t.Errorf("Expected the changed endpoints to be left alone, got %+v", endpoints.Subsets)
+	}
+	if _, err := os.Stat(action.Metadata["backupPath"]); err != nil {
+		t.Errorf("Expected the backup to be kept for a later rollback: %v", err)
+	}
+
+	// Forcing restores the original over the change
+	if err := RestoreEndpoints(client, action, false, true); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	endpoints, err = client.CoreV1().Endpoints("default").Get(context.TODO(), "web", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Failed to get endpoints: %v", err)
+	}
+	if len(endpoints.Subsets) != 1 || endpoints.Subsets[0].Addresses[0].IP != "10.0.0.1" {
+		t.Errorf("Expected the original endpoints to be restored, got %+v", endpoints.Subsets)
+	}
+}
+
+func TestRestoreEndpoints_RetriesOnConflict(t *testing.T) {
+	// Keep backups out of the real home directory
+	t.Setenv("HOME", t.TempDir())
+
+	client, action := misrouteForRestore(t)
+
+	// The first restore attempt loses a race with another writer
+	updates := 0
+	client.PrependReactor("update", "endpoints", func(k8stesting.Action) (bool, runtime.Object, error) {
+		updates++
+		if updates == 1 {
+			return true, nil, apierrors.NewConflict(schema.GroupResource{Resource: "endpoints"}, "web", nil)
+		}
+		return false, nil, nil
+	})
+
+	if err := RestoreEndpoints(client, action, false, false); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if updates != 2 {
+		t.Errorf("Expected the restore to be retried once, got %d update(s)", updates)
+	}
+
+	endpoints, err := client.CoreV1().Endpoints("default").Get(context.TODO(), "web", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Failed to get endpoints: %v", err)
+	}
+	if len(endpoints.Subsets) != 1 || endpoints.Subsets[0].Addresses[0].IP != "10.0.0.1" {
+		t.Errorf("Expected the original endpoints to be restored, got %+v", endpoints.Subsets)
+	}
+}
@@ -0,0 +1,97 @@
+package rollback
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/isurusiri/tipsy/internal/chaos"
+	"github.com/isurusiri/tipsy/internal/state"
+	"github.com/isurusiri/tipsy/internal/utils"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// RestoreReplicas restores a scaled workload's original replica count and,
+// if tipsy pinned it, the original bounds of its HorizontalPodAutoscaler
+func RestoreReplicas(client kubernetes.Interface, action state.ChaosAction, dryRun bool) error {
+	// Scale actions use TargetPod to hold the workload name
+	kind := action.Metadata["kind"]
+	name := action.TargetPod
+	utils.Info(fmt.Sprintf("Restoring replicas for %s '%s' in namespace '%s'", kind, name, action.Namespace))
+
+	replicas, err := parseReplicas(action.Metadata, "originalReplicas")
+	if err != nil {
+		return err
+	}
+
+	hpaName := action.Metadata["hpa"]
+	restoreHPA := hpaName != "" && action.Metadata["hpaPinned"] == "true"
+
+	if dryRun {
+		utils.DryRun(fmt.Sprintf("Would scale %s '%s' back to %d replica(s)", kind, name, replicas))
+		if restoreHPA {
+			utils.DryRun(fmt.Sprintf("Would restore the bounds of HorizontalPodAutoscaler '%s'", hpaName))
+		}
+		return nil
+	}
+
+	_, uid, err := chaos.GetReplicas(client, action.Namespace, kind, name)
+	isRecreated := recreated(action, fmt.Sprintf("%s '%s'", kind, name), uid, err)
+	if err != nil && !isRecreated {
+		return err
+	}
+
+	// Restore the HPA first so it does not immediately undo the restored replica count
+	// The HPA is its own object, so its bounds are restored even if the workload was recreated
+	if restoreHPA {
+		minReplicas, err := parseReplicas(action.Metadata, "hpaMinReplicas")
+		if err != nil {
+			return err
+		}
+		maxReplicas, err := parseReplicas(action.Metadata, "hpaMaxReplicas")
+		if err != nil {
+			return err
+		}
+
+		hpa, err := client.AutoscalingV2().HorizontalPodAutoscalers(action.Namespace).Get(context.TODO(), hpaName, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to get horizontal pod autoscaler: %w", err)
+		}
+
+		hpa.Spec.MinReplicas = &minReplicas
+		hpa.Spec.MaxReplicas = maxReplicas
+		_, err = client.AutoscalingV2().HorizontalPodAutoscalers(action.Namespace).Update(context.TODO(), hpa, metav1.UpdateOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to restore horizontal pod autoscaler: %w", err)
+		}
+
+		utils.Info(fmt.Sprintf("Restored HorizontalPodAutoscaler '%s' to min=%d, max=%d", hpaName, minReplicas, maxReplicas))
+	}
+
+	if isRecreated {
+		return ErrResolvedByRecreation
+	}
+
+	if err := chaos.SetReplicas(client, action.Namespace, kind, name, replicas); err != nil {
+		return err
+	}
+
+	utils.Info(fmt.Sprintf("Successfully restored %s '%s' to %d replica(s)", kind, name, replicas))
+	return nil
+}
+
+// parseReplicas reads a replica count from action metadata
+func parseReplicas(metadata map[string]string, key string) (int32, error) {
+	value, exists := metadata[key]
+	if !exists {
+		return 0, fmt.Errorf("missing %s in action metadata", key)
+	}
+
+	replicas, err := strconv.ParseInt(value, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s value '%s': %w", key, value, err)
+	}
+
+	return int32(replicas), nil
+}
@@ -0,0 +1,113 @@
+package rollback
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/isurusiri/tipsy/internal/state"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestRestoreReplicas(t *testing.T) {
+	replicas := int32(1)
+	pinned := int32(1)
+	client := fake.NewSimpleClientset(
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+			Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+		},
+		&autoscalingv2.HorizontalPodAutoscaler{
+			ObjectMeta: metav1.ObjectMeta{Name: "web-hpa", Namespace: "default"},
+			Spec:       autoscalingv2.HorizontalPodAutoscalerSpec{MinReplicas: &pinned, MaxReplicas: 1},
+		},
+	)
+
+	action := state.ChaosAction{
+		Type:      "scale",
+		TargetPod: "web",
+		Namespace: "default",
+		Metadata: map[string]string{
+			"kind":             "Deployment",
+			"originalReplicas": "4",
+			"hpa":              "web-hpa",
+			"hpaMinReplicas":   "2",
+			"hpaMaxReplicas":   "10",
+			"hpaPinned":        "true",
+		},
+	}
+
+	// Test dry run
+	if err := RestoreReplicas(client, action, true); err != nil {
+		t.Errorf("Unexpected error in dry run: %v", err)
+	}
+
+	deployment, _ := client.AppsV1().Deployments("default").Get(context.TODO(), "web", metav1.GetOptions{})
+	if *deployment.Spec.Replicas != 1 {
+		t.Error("Expected replicas to be unchanged in dry run")
+	}
+
+	// Test actual execution
+	if err := RestoreReplicas(client, action, false); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	deployment, _ = client.AppsV1().Deployments("default").Get(context.TODO(), "web", metav1.GetOptions{})
+	if *deployment.Spec.Replicas != 4 {
+		t.Errorf("Expected 4 replicas after rollback, got %d", *deployment.Spec.Replicas)
+	}
+
+	hpa, _ := client.AutoscalingV2().HorizontalPodAutoscalers("default").Get(context.TODO(), "web-hpa", metav1.GetOptions{})
+	if *hpa.Spec.MinReplicas != 2 || hpa.Spec.MaxReplicas != 10 {
+		t.Errorf("Expected HPA bounds 2-10 after rollback, got %d-%d", *hpa.Spec.MinReplicas, hpa.Spec.MaxReplicas)
+	}
+}
+
+func TestRestoreReplicas_MissingMetadata(t *testing.T) {
+	client := fake.NewSimpleClientset()
+
+	action := state.ChaosAction{
+		Type:      "scale",
+		TargetPod: "web",
+		Namespace: "default",
+		Metadata:  map[string]string{"kind": "Deployment"},
+	}
+
+	if err := RestoreReplicas(client, action, false); err == nil {
+		t.Error("Expected error when original replica count is missing")
+	}
+}
+
+func TestRestoreReplicas_Recreated(t *testing.T) {
+	replicas := int32(1)
+	client := fake.NewSimpleClientset(&appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default", UID: "uid-new"},
+		Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+	})
+
+	action := state.ChaosAction{
+		Type:      "scale",
+		TargetPod: "web",
+		Namespace: "default",
+		TargetUID: "uid-old",
+		Metadata:  map[string]string{"kind": "Deployment", "originalReplicas": "4"},
+	}
+
+	// A replacement with the same name is left alone
+	if err := RestoreReplicas(client, action, false); !errors.Is(err, ErrResolvedByRecreation) {
+		t.Fatalf("Expected ErrResolvedByRecreation, got %v", err)
+	}
+	deployment, _ := client.AppsV1().Deployments("default").Get(context.TODO(), "web", metav1.GetOptions{})
+	if *deployment.Spec.Replicas != 1 {
+		t.Errorf("Expected the replacement to keep 1 replica, got %d", *deployment.Spec.Replicas)
+	}
+
+	// So is a workload that is gone
+	client.AppsV1().Deployments("default").Delete(context.TODO(), "web", metav1.DeleteOptions{})
+	if err := RestoreReplicas(client, action, false); !errors.Is(err, ErrResolvedByRecreation) {
+		t.Errorf("Expected ErrResolvedByRecreation for a deleted deployment, got %v", err)
+	}
+}
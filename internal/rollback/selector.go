@@ -0,0 +1,56 @@
+package rollback
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/isurusiri/tipsy/internal/chaos"
+	"github.com/isurusiri/tipsy/internal/state"
+	"github.com/isurusiri/tipsy/internal/utils"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+)
+
+// RestoreSelector patches a selector-swapped service back to its original selector
+// A selector changed by someone else since the swap is left alone unless force is set
+func RestoreSelector(client kubernetes.Interface, action state.ChaosAction, dryRun, force bool) error {
+	// Misroute actions use TargetPod to hold the service name
+	name := action.TargetPod
+	utils.Info(fmt.Sprintf("Restoring selector of service '%s' in namespace '%s'", name, action.Namespace))
+
+	original, exists := action.Metadata["originalSelector"]
+	if !exists || original == "" {
+		return fmt.Errorf("missing originalSelector in action metadata")
+	}
+	selector, err := labels.ConvertSelectorToLabelsMap(original)
+	if err != nil {
+		return fmt.Errorf("invalid originalSelector in action metadata: %w", err)
+	}
+
+	if dryRun {
+		utils.DryRun(fmt.Sprintf("Would check the selector of service '%s' for changes made since the swap", name))
+		utils.DryRun(fmt.Sprintf("Would change the selector of service '%s' back to '%s'", name, original))
+		return nil
+	}
+
+	service, err := client.CoreV1().Services(action.Namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get service: %w", err)
+	}
+
+	if current := chaos.FormatSelector(service.Spec.Selector); current != action.Metadata["swappedSelector"] {
+		if !force {
+			return fmt.Errorf("selector of service '%s' has been changed to '%s' since it was swapped; refusing to overwrite it, use --force to restore '%s' anyway",
+				name, current, original)
+		}
+		utils.Warn(fmt.Sprintf("Selector of service '%s' has been changed to '%s' since it was swapped; restoring '%s' anyway", name, current, original))
+	}
+
+	if err := chaos.SetServiceSelector(client, action.Namespace, name, selector); err != nil {
+		return err
+	}
+
+	utils.Info(fmt.Sprintf("Successfully restored selector of service '%s' to '%s'", name, original))
+	return nil
+}
@@ -0,0 +1,76 @@
+package rollback
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/isurusiri/tipsy/internal/state"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestRestoreSelector(t *testing.T) {
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Spec:       corev1.ServiceSpec{Selector: map[string]string{"app": "decoy"}},
+	}
+	action := state.ChaosAction{
+		Type:      "misroute",
+		TargetPod: "web",
+		Namespace: "default",
+		Metadata: map[string]string{
+			"mode":             "selector-swap",
+			"originalSelector": "app=web,tier=frontend",
+			"swappedSelector":  "app=decoy",
+		},
+	}
+
+	client := fake.NewSimpleClientset(service)
+	if err := rollbackAction(client, action, false, false); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	restored, err := client.CoreV1().Services("default").Get(context.TODO(), "web", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Failed to get service: %v", err)
+	}
+	if len(restored.Spec.Selector) != 2 || restored.Spec.Selector["app"] != "web" || restored.Spec.Selector["tier"] != "frontend" {
+		t.Errorf("Expected the original selector back, got %v", restored.Spec.Selector)
+	}
+}
+
+func TestRestoreSelector_Drift(t *testing.T) {
+	// Someone pointed the service elsewhere after the swap
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Spec:       corev1.ServiceSpec{Selector: map[string]string{"app": "web-v2"}},
+	}
+	action := state.ChaosAction{
+		Type:      "misroute",
+		TargetPod: "web",
+		Namespace: "default",
+		Metadata: map[string]string{
+			"mode":             "selector-swap",
+			"originalSelector": "app=web",
+			"swappedSelector":  "app=decoy",
+		},
+	}
+
+	client := fake.NewSimpleClientset(service)
+	if err := RestoreSelector(client, action, false, false); err == nil || !strings.Contains(err.Error(), "--force") {
+		t.Fatalf("Expected a changed selector to be left alone, got %v", err)
+	}
+
+	if err := RestoreSelector(client, action, false, true); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	restored, err := client.CoreV1().Services("default").Get(context.TODO(), "web", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Failed to get service: %v", err)
+	}
+	if restored.Spec.Selector["app"] != "web" {
+		t.Errorf("Expected forcing to restore the original selector, got %v", restored.Spec.Selector)
+	}
+}
@@ -0,0 +1,62 @@
+package state
+
+// ClusterRef identifies the cluster an action was recorded against
+type ClusterRef struct {
+	Server  string `json:"server"`
+	Context string `json:"context,omitempty"`
+	UID     string `json:"uid,omitempty"` // UID of the kube-system namespace
+}
+
+// String names the cluster by its context, or by its server if it has none
+func (c ClusterRef) String() string {
+	if c.Context != "" {
+		return c.Context
+	}
+	return c.Server
+}
+
+// currentCluster is the cluster the running command is connected to, if known
+var currentCluster *ClusterRef
+
+// SetCluster records the cluster the running command is connected to
+// Actions saved afterwards without a cluster of their own are stamped with it
+func SetCluster(cluster *ClusterRef) {
+	currentCluster = cluster
+}
+
+// CurrentCluster returns the cluster set by SetCluster, or nil if none was set
+func CurrentCluster() *ClusterRef {
+	return currentCluster
+}
+
+// OnCluster reports whether an action was recorded against the given cluster
+// Clusters are compared by kube-system UID when both sides have one, since a cluster can be
+// reached through more than one server URL, and by server URL otherwise
+// A nil cluster matches every action, as does an action recorded before clusters were tracked
+func (a ChaosAction) OnCluster(cluster *ClusterRef) bool {
+	return a.Cluster.Matches(cluster)
+}
+
+// Matches reports whether two references name the same cluster, compared as in OnCluster
+// A nil reference matches any cluster
+func (c *ClusterRef) Matches(other *ClusterRef) bool {
+	if c == nil || other == nil {
+		return true
+	}
+	if c.UID != "" && other.UID != "" {
+		return c.UID == other.UID
+	}
+	return c.Server == other.Server
+}
+
+// FilterByCluster keeps the actions recorded against the given cluster, see OnCluster
+// Returns the kept actions and the number left out
+func FilterByCluster(actions []ChaosAction, cluster *ClusterRef) ([]ChaosAction, int) {
+	kept := []ChaosAction{}
+	for _, action := range actions {
+		if action.OnCluster(cluster) {
+			kept = append(kept, action)
+		}
+	}
+	return kept, len(actions) - len(kept)
+}
@@ -0,0 +1,51 @@
+package state
+
+import "testing"
+
+func TestOnCluster(t *testing.T) {
+	staging := &ClusterRef{Server: "https://staging:6443", Context: "staging", UID: "staging-uid"}
+
+	testCases := []struct {
+		name     string
+		recorded *ClusterRef
+		current  *ClusterRef
+		expected bool
+	}{
+		{"same cluster", staging, staging, true},
+		{"other cluster", &ClusterRef{Server: "https://prod:6443", UID: "prod-uid"}, staging, false},
+		{"same cluster through another server URL", &ClusterRef{Server: "https://10.0.0.1:6443", UID: "staging-uid"}, staging, true},
+		{"no UID, same server", &ClusterRef{Server: "https://staging:6443"}, staging, true},
+		{"no UID, other server", &ClusterRef{Server: "https://prod:6443"}, staging, false},
+		{"recorded before clusters were tracked", nil, staging, true},
+		{"every cluster", &ClusterRef{Server: "https://prod:6443", UID: "prod-uid"}, nil, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			action := ChaosAction{Type: "kill", Cluster: tc.recorded}
+			if got := action.OnCluster(tc.current); got != tc.expected {
+				t.Errorf("Expected OnCluster to be %t, got %t", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestSaveActionRecordsCluster(t *testing.T) {
+	useTestStateFile(t)
+
+	staging := &ClusterRef{Server: "https://staging:6443", Context: "staging", UID: "staging-uid"}
+	SetCluster(staging)
+	t.Cleanup(func() { SetCluster(nil) })
+
+	if err := SaveAction(ChaosAction{Type: "kill", TargetPod: "web-1", Namespace: "default"}); err != nil {
+		t.Fatalf("Failed to save action: %v", err)
+	}
+
+	actions, err := LoadActions()
+	if err != nil {
+		t.Fatalf("Failed to load actions: %v", err)
+	}
+	if len(actions) != 1 || actions[0].Cluster == nil || *actions[0].Cluster != *staging {
+		t.Errorf("Expected the action to record the current cluster, got %+v", actions)
+	}
+}
@@ -0,0 +1,152 @@
+package state
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
+)
+
+const (
+	// StateConfigMapName is the name of the ConfigMap holding state for the configmap backend
+	StateConfigMapName = "tipsy-state"
+	// stateConfigMapKey is the ConfigMap data key holding the actions as JSON
+	stateConfigMapKey = "actions.json"
+	// managedByLabel marks the ConfigMap as tipsy's, matching the label on other tipsy-created objects
+	managedByLabel = "app.kubernetes.io/managed-by"
+	managedByValue = "tipsy"
+)
+
+// ConfigMapStore keeps actions in a tipsy-labelled ConfigMap, so that everyone running
+// tipsy against a cluster shares one record of the faults in it
+// Concurrent updates are detected through the ConfigMap's resourceVersion and retried
+type ConfigMapStore struct {
+	client    kubernetes.Interface
+	namespace string
+}
+
+// NewConfigMapStore returns a store keeping actions in the state ConfigMap in the given namespace
+func NewConfigMapStore(client kubernetes.Interface, namespace string) *ConfigMapStore {
+	return &ConfigMapStore{client: client, namespace: namespace}
+}
+
+// Load returns every action recorded in the state ConfigMap
+func (s *ConfigMapStore) Load() ([]ChaosAction, error) {
+	cm, err := s.get()
+	if err != nil {
+		return nil, err
+	}
+	if cm == nil {
+		return []ChaosAction{}, nil
+	}
+
+	return s.decode(cm)
+}
+
+// Update applies fn to the recorded actions and writes the result back
+// The write is conditional on the resourceVersion that was read; on a conflict the
+// ConfigMap is read again and fn reapplied
+func (s *ConfigMapStore) Update(fn func(actions []ChaosAction) ([]ChaosAction, error)) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cm, err := s.get()
+		if err != nil {
+			return err
+		}
+
+		actions := []ChaosAction{}
+		if cm != nil {
+			if actions, err = s.decode(cm); err != nil {
+				return fmt.Errorf("failed to load existing actions: %w", err)
+			}
+		}
+
+		updated, err := fn(actions)
+		if err != nil {
+			return err
+		}
+
+		data, err := encodeActions(updated)
+		if err != nil {
+			return err
+		}
+
+		if cm == nil {
+			return s.create(string(data))
+		}
+
+		if cm.Data == nil {
+			cm.Data = map[string]string{}
+		}
+		cm.Data[stateConfigMapKey] = string(data)
+		if _, err := s.client.CoreV1().ConfigMaps(s.namespace).Update(context.TODO(), cm, metav1.UpdateOptions{}); err != nil {
+			if apierrors.IsConflict(err) {
+				return err
+			}
+			return fmt.Errorf("failed to update state configmap %s: %w", s.path(), err)
+		}
+
+		return nil
+	})
+}
+
+// get returns the state ConfigMap, or nil if it does not exist yet
+// A ConfigMap of the same name that tipsy does not manage is refused rather than overwritten
+func (s *ConfigMapStore) get() (*corev1.ConfigMap, error) {
+	cm, err := s.client.CoreV1().ConfigMaps(s.namespace).Get(context.TODO(), StateConfigMapName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get state configmap %s: %w", s.path(), err)
+	}
+
+	if cm.Labels[managedByLabel] != managedByValue {
+		return nil, fmt.Errorf("configmap %s exists but is not managed by tipsy", s.path())
+	}
+
+	return cm, nil
+}
+
+// create creates the state ConfigMap holding the given data
+// Losing the race to create it is reported as a conflict so the update is retried
+func (s *ConfigMapStore) create(data string) error {
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      StateConfigMapName,
+			Namespace: s.namespace,
+			Labels:    map[string]string{managedByLabel: managedByValue},
+		},
+		Data: map[string]string{stateConfigMapKey: data},
+	}
+
+	_, err := s.client.CoreV1().ConfigMaps(s.namespace).Create(context.TODO(), cm, metav1.CreateOptions{})
+	if apierrors.IsAlreadyExists(err) {
+		return apierrors.NewConflict(schema.GroupResource{Resource: "configmaps"}, StateConfigMapName, err)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create state configmap %s: %w", s.path(), err)
+	}
+
+	return nil
+}
+
+// decode parses the actions held in the state ConfigMap
+func (s *ConfigMapStore) decode(cm *corev1.ConfigMap) ([]ChaosAction, error) {
+	actions, err := decodeActions([]byte(cm.Data[stateConfigMapKey]))
+	if err != nil {
+		return nil, corruptStateError(err, s.path(),
+			fmt.Sprintf("save a copy with 'kubectl get configmap %s -n %s -o yaml > %s.yaml', then delete it",
+				StateConfigMapName, s.namespace, StateConfigMapName))
+	}
+	return actions, nil
+}
+
+// path names the state ConfigMap in messages
+func (s *ConfigMapStore) path() string {
+	return s.namespace + "/" + StateConfigMapName
+}
@@ -0,0 +1,178 @@
+package state
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// useTestStore points the package-level functions at a store for the duration of a test
+// History is still written next to the state file, so that is moved aside too
+func useTestStore(t *testing.T, store Store) {
+	useTestStateFile(t)
+
+	originalBackend := backend
+	backend = store
+	t.Cleanup(func() { backend = originalBackend })
+}
+
+func TestConfigMapStore(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	useTestStore(t, NewConfigMapStore(client, "chaos"))
+
+	// Loading before anything is saved finds no actions and creates nothing
+	actions, err := LoadActions()
+	if err != nil {
+		t.Fatalf("Failed to load actions: %v", err)
+	}
+	if len(actions) != 0 {
+		t.Errorf("Expected no actions, got %+v", actions)
+	}
+
+	for _, pod := range []string{"pod-1", "pod-2"} {
+		if err := SaveAction(ChaosAction{ID: pod, Type: "kill", TargetPod: pod, Namespace: "default"}); err != nil {
+			t.Fatalf("Failed to save action: %v", err)
+		}
+	}
+
+	cm, err := client.CoreV1().ConfigMaps("chaos").Get(context.TODO(), StateConfigMapName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Expected the state configmap to be created: %v", err)
+	}
+	if cm.Labels[managedByLabel] != managedByValue {
+		t.Errorf("Expected the state configmap to be labelled as managed by tipsy, got %v", cm.Labels)
+	}
+	if !strings.Contains(cm.Data[stateConfigMapKey], `"targetPod": "pod-2"`) {
+		t.Errorf("Expected the actions to be stored as JSON, got: %s", cm.Data[stateConfigMapKey])
+	}
+
+	if err := DeleteAction(ChaosAction{ID: "pod-1"}); err != nil {
+		t.Fatalf("Failed to delete action: %v", err)
+	}
+	action, err := FindAction("pod-2")
+	if err != nil {
+		t.Fatalf("Failed to find action: %v", err)
+	}
+	if action.TargetPod != "pod-2" {
+		t.Errorf("Expected pod-2, got %+v", action)
+	}
+
+	actions, err = LoadActions()
+	if err != nil {
+		t.Fatalf("Failed to load actions: %v", err)
+	}
+	if len(actions) != 1 {
+		t.Errorf("Expected 1 action after deleting one, got %+v", actions)
+	}
+}
+
+func TestConfigMapStoreRetriesOnConflict(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	store := NewConfigMapStore(client, "chaos")
+	useTestStore(t, store)
+
+	if err := SaveAction(ChaosAction{ID: "first", Type: "kill", TargetPod: "pod-1", Namespace: "default"}); err != nil {
+		t.Fatalf("Failed to save action: %v", err)
+	}
+
+	// Another tipsy invocation saves its action between our read and our write
+	raced := false
+	client.PrependReactor("update", "configmaps", func(k8stesting.Action) (bool, runtime.Object, error) {
+		if raced {
+			return false, nil, nil
+		}
+		raced = true
+
+		// Write straight to the tracker, since the fake client is locked while reactors run
+		gvr := corev1.SchemeGroupVersion.WithResource("configmaps")
+		obj, err := client.Tracker().Get(gvr, "chaos", StateConfigMapName)
+		if err != nil {
+			t.Fatalf("Failed to get configmap: %v", err)
+		}
+		cm := obj.(*corev1.ConfigMap)
+		stored, err := decodeActions([]byte(cm.Data[stateConfigMapKey]))
+		if err != nil {
+			t.Fatalf("Failed to decode actions: %v", err)
+		}
+		data, err := encodeActions(append(stored, ChaosAction{ID: "concurrent", Type: "kill", TargetPod: "pod-2"}))
+		if err != nil {
+			t.Fatalf("Failed to marshal actions: %v", err)
+		}
+		cm.Data[stateConfigMapKey] = string(data)
+		if err := client.Tracker().Update(gvr, cm, "chaos"); err != nil {
+			t.Fatalf("Failed to save the concurrent action: %v", err)
+		}
+
+		return true, nil, apierrors.NewConflict(schema.GroupResource{Resource: "configmaps"}, StateConfigMapName, errors.New("the object has been modified"))
+	})
+
+	if err := SaveAction(ChaosAction{ID: "second", Type: "kill", TargetPod: "pod-3", Namespace: "default"}); err != nil {
+		t.Fatalf("Failed to save action: %v", err)
+	}
+
+	actions, err := store.Load()
+	if err != nil {
+		t.Fatalf("Failed to load actions: %v", err)
+	}
+	ids := []string{}
+	for _, action := range actions {
+		ids = append(ids, action.ID)
+	}
+	if strings.Join(ids, ",") != "first,concurrent,second" {
+		t.Errorf("Expected the concurrent save to be kept, got %v", ids)
+	}
+}
+
+func TestConfigMapStoreRefusesForeignConfigMap(t *testing.T) {
+	client := fake.NewSimpleClientset(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: StateConfigMapName, Namespace: "chaos"},
+		Data:       map[string]string{"app": "settings"},
+	})
+	store := NewConfigMapStore(client, "chaos")
+
+	if err := store.Update(func(actions []ChaosAction) ([]ChaosAction, error) {
+		return append(actions, ChaosAction{Type: "kill"}), nil
+	}); err == nil || !strings.Contains(err.Error(), "not managed by tipsy") {
+		t.Errorf("Expected an error for a configmap tipsy does not manage, got %v", err)
+	}
+
+	cm, err := client.CoreV1().ConfigMaps("chaos").Get(context.TODO(), StateConfigMapName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Failed to get configmap: %v", err)
+	}
+	if _, ok := cm.Data[stateConfigMapKey]; ok {
+		t.Error("Expected the foreign configmap to be left untouched")
+	}
+}
+
+func TestConfigMapStoreCorrupt(t *testing.T) {
+	client := fake.NewSimpleClientset(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      StateConfigMapName,
+			Namespace: "chaos",
+			Labels:    map[string]string{managedByLabel: managedByValue},
+		},
+		Data: map[string]string{stateConfigMapKey: `[{"type": "kill"`},
+	})
+
+	_, err := NewConfigMapStore(client, "chaos").Load()
+	var corruptErr *CorruptStateError
+	if !errors.As(err, &corruptErr) {
+		t.Fatalf("Expected a CorruptStateError, got %v", err)
+	}
+	if corruptErr.Path != "chaos/"+StateConfigMapName {
+		t.Errorf("Expected the error to name the configmap, got %s", corruptErr.Path)
+	}
+	if !strings.Contains(err.Error(), "kubectl get configmap") {
+		t.Errorf("Expected the error to explain how to recover, got: %v", err)
+	}
+}
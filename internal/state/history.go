@@ -0,0 +1,146 @@
+package state
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// History events
+const (
+	// EventInjected records a fault being put in place
+	EventInjected = "injected"
+	// EventRolledBack records a fault being rolled back
+	EventRolledBack = "rolled-back"
+	// EventRollbackFailed records a failed attempt to roll a fault back
+	EventRollbackFailed = "rollback-failed"
+)
+
+// HistoryEvent is one entry in the experiment history
+// Unlike state, which only holds the faults currently in place, history keeps every
+// injection and rollback for auditing past experiments
+type HistoryEvent struct {
+	Time      time.Time         `json:"time"`
+	Event     string            `json:"event"`
+	ActionID  string            `json:"actionID,omitempty"`
+	Type      string            `json:"type"`
+	Target    string            `json:"target"`
+	Namespace string            `json:"namespace,omitempty"`
+	Cluster   *ClusterRef       `json:"cluster,omitempty"`
+	Metadata  map[string]string `json:"metadata,omitempty"`
+	StartedAt time.Time         `json:"startedAt,omitzero"` // when the fault was put in place
+	ExpiresAt time.Time         `json:"expiresAt,omitzero"`
+	Error     string            `json:"error,omitempty"`
+}
+
+// Elapsed returns how long the fault had been in place when the event happened
+func (e HistoryEvent) Elapsed() time.Duration {
+	if e.StartedAt.IsZero() {
+		return 0
+	}
+	return e.Time.Sub(e.StartedAt)
+}
+
+// HistoryFilter selects history events; zero fields match everything
+type HistoryFilter struct {
+	Since     time.Time
+	Type      string
+	Namespace string
+}
+
+// matches reports whether an event passes the filter
+func (f HistoryFilter) matches(event HistoryEvent) bool {
+	if !f.Since.IsZero() && event.Time.Before(f.Since) {
+		return false
+	}
+	if f.Type != "" && event.Type != f.Type {
+		return false
+	}
+	if f.Namespace != "" && event.Namespace != f.Namespace {
+		return false
+	}
+	return true
+}
+
+// GetHistoryFilePath returns the path to the history file, kept next to the state file
+func GetHistoryFilePath() string {
+	return filepath.Join(filepath.Dir(stateFile), "history.jsonl")
+}
+
+// newHistoryEvent describes an event that happened to an action
+func newHistoryEvent(event string, action ChaosAction, at time.Time) HistoryEvent {
+	return HistoryEvent{
+		Time:      at,
+		Event:     event,
+		ActionID:  action.ID,
+		Type:      action.Type,
+		Target:    action.TargetPod,
+		Namespace: action.Namespace,
+		Cluster:   action.Cluster,
+		Metadata:  action.Metadata,
+		StartedAt: action.StartedAt,
+		ExpiresAt: action.ExpiresAt,
+	}
+}
+
+// appendHistory appends an event to the history file as a single JSON line
+// The file is only ever appended to, so concurrent tipsy invocations add their lines
+// without rewriting each other's
+func appendHistory(event HistoryEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal history event: %w", err)
+	}
+
+	path := GetHistoryFilePath()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open history file: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write history file: %w", err)
+	}
+	return nil
+}
+
+// LoadHistory returns the history events matching the filter, oldest first
+// Lines that cannot be parsed, such as one cut short by a crash, are skipped
+func LoadHistory(filter HistoryFilter) ([]HistoryEvent, error) {
+	events := []HistoryEvent{}
+
+	file, err := os.Open(GetHistoryFilePath())
+	if os.IsNotExist(err) {
+		return events, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open history file: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	// Metadata can make a line longer than the scanner's default limit
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var event HistoryEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			continue
+		}
+		if filter.matches(event) {
+			events = append(events, event)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read history file: %w", err)
+	}
+
+	return events, nil
+}
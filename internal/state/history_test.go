@@ -0,0 +1,83 @@
+package state
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestHistory(t *testing.T) {
+	useTestStateFile(t)
+	useTestClock(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	scale := ChaosAction{ID: "scale-1", Type: "scale", TargetPod: "web", Namespace: "default", Timestamp: "2024-01-01T00:00:00Z"}
+	quota := ChaosAction{ID: "quota-1", Type: "quota-squeeze", TargetPod: "batch", Namespace: "jobs", Timestamp: "2024-01-01T00:00:00Z"}
+	for _, action := range []ChaosAction{scale, quota} {
+		if err := SaveAction(action); err != nil {
+			t.Fatalf("Failed to save action: %v", err)
+		}
+	}
+
+	useTestClock(t, time.Date(2024, 1, 1, 0, 5, 0, 0, time.UTC))
+	if _, err := RecordRollback(scale, errors.New("deployment is being deleted")); err != nil {
+		t.Fatalf("Failed to record rollback: %v", err)
+	}
+	if _, err := RecordRollback(scale, nil); err != nil {
+		t.Fatalf("Failed to record rollback: %v", err)
+	}
+
+	// The rolled back action has left state but not history
+	events, err := LoadHistory(HistoryFilter{})
+	if err != nil {
+		t.Fatalf("Failed to load history: %v", err)
+	}
+	expected := []string{EventInjected, EventInjected, EventRollbackFailed, EventRolledBack}
+	if len(events) != len(expected) {
+		t.Fatalf("Expected %d events, got %+v", len(expected), events)
+	}
+	for i, event := range events {
+		if event.Event != expected[i] {
+			t.Errorf("Expected event %d to be %s, got %s", i, expected[i], event.Event)
+		}
+	}
+	if events[2].Error != "deployment is being deleted" {
+		t.Errorf("Expected the failed rollback to record its error, got %+v", events[2])
+	}
+	if events[3].Elapsed() != 5*time.Minute {
+		t.Errorf("Expected the rollback 5m after injection, got %v", events[3].Elapsed())
+	}
+
+	// Filters
+	if events, _ := LoadHistory(HistoryFilter{Type: "quota-squeeze"}); len(events) != 1 || events[0].ActionID != "quota-1" {
+		t.Errorf("Expected only the quota injection, got %+v", events)
+	}
+	if events, _ := LoadHistory(HistoryFilter{Namespace: "default"}); len(events) != 3 {
+		t.Errorf("Expected the 3 events in default, got %+v", events)
+	}
+	if events, _ := LoadHistory(HistoryFilter{Since: time.Date(2024, 1, 1, 0, 1, 0, 0, time.UTC)}); len(events) != 2 {
+		t.Errorf("Expected the 2 rollback events, got %+v", events)
+	}
+}
+
+func TestLoadHistorySkipsTornLines(t *testing.T) {
+	useTestStateFile(t)
+
+	if events, err := LoadHistory(HistoryFilter{}); err != nil || len(events) != 0 {
+		t.Fatalf("Expected an empty history before anything is recorded, got %+v, %v", events, err)
+	}
+
+	content := `{"time":"2024-01-01T00:00:00Z","event":"injected","type":"kill","target":"web-1"}
+{"time":"2024-01-01T00:01:00Z","event":"inj`
+	if err := os.WriteFile(GetHistoryFilePath(), []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write history file: %v", err)
+	}
+
+	events, err := LoadHistory(HistoryFilter{})
+	if err != nil {
+		t.Fatalf("Failed to load history: %v", err)
+	}
+	if len(events) != 1 || events[0].Target != "web-1" {
+		t.Errorf("Expected the one complete event, got %+v", events)
+	}
+}
@@ -0,0 +1,139 @@
+package state
+
+import (
+	"fmt"
+	"time"
+)
+
+// Lifecycle statuses of a recorded action
+const (
+	// StatusActive means the fault is in place and has not outlived its duration
+	StatusActive = "active"
+	// StatusExpired means the fault's duration has passed but it has not been rolled back,
+	// usually because the command holding it was interrupted
+	StatusExpired = "expired"
+	// StatusRolledBack means the fault was rolled back; the action leaves state at that point
+	StatusRolledBack = "rolled-back"
+	// StatusRollbackFailed means the last attempt to roll the fault back failed
+	StatusRollbackFailed = "rollback-failed"
+)
+
+// RollbackAttempt records one try at rolling an action back
+type RollbackAttempt struct {
+	At    time.Time `json:"at"`
+	Error string    `json:"error,omitempty"` // empty if the attempt succeeded
+}
+
+// selfCleaningTypes are the faults whose injector removes itself once the duration passes,
+// so an expired action of these types has nothing left to roll back
+var selfCleaningTypes = map[string]bool{
+	"latency":    true,
+	"packetloss": true,
+	"cpustress":  true,
+}
+
+// now returns the current time; replaced in tests
+var now = time.Now
+
+// initLifecycle fills in the lifecycle fields of an action that lacks them
+// The start time comes from the action's timestamp and the expiry from its duration metadata
+func initLifecycle(action *ChaosAction) {
+	if action.StartedAt.IsZero() {
+		started, err := time.Parse(time.RFC3339, action.Timestamp)
+		if err != nil {
+			started = now().UTC()
+		}
+		action.StartedAt = started
+	}
+
+	if action.ExpiresAt.IsZero() {
+		if duration, err := time.ParseDuration(action.Metadata["duration"]); err == nil && duration > 0 {
+			action.ExpiresAt = action.StartedAt.Add(duration)
+		}
+	}
+
+	if action.Status == "" {
+		action.Status = StatusActive
+	}
+}
+
+// Expired reports whether an action has outlived its duration
+func (a ChaosAction) Expired(at time.Time) bool {
+	return !a.ExpiresAt.IsZero() && !at.Before(a.ExpiresAt)
+}
+
+// sweepExpired prunes expired self-cleaning actions and marks other expired active actions
+// as expired, so they stay visible to rollback without piling up as if still in force
+// Reports whether anything changed
+func sweepExpired(actions []ChaosAction, at time.Time) ([]ChaosAction, bool) {
+	swept := make([]ChaosAction, 0, len(actions))
+	changed := false
+
+	for _, action := range actions {
+		if !action.Expired(at) {
+			swept = append(swept, action)
+			continue
+		}
+
+		if selfCleaningTypes[action.Type] {
+			changed = true
+			continue
+		}
+
+		if action.Status == StatusActive {
+			action.Status = StatusExpired
+			changed = true
+		}
+		swept = append(swept, action)
+	}
+
+	return swept, changed
+}
+
+// RecordRollback records an attempt to roll an action back
+// A successful attempt removes the action from state; a failed one keeps it, marked rollback-failed
+// Returns the action as updated with the attempt
+func RecordRollback(action ChaosAction, rollbackErr error) (ChaosAction, error) {
+	attempt := RollbackAttempt{At: now().UTC()}
+	if rollbackErr != nil {
+		attempt.Error = rollbackErr.Error()
+	}
+
+	recorded := action
+	err := backend.Update(func(actions []ChaosAction) ([]ChaosAction, error) {
+		index := -1
+		for i := range actions {
+			if sameAction(actions[i], action) {
+				index = i
+				break
+			}
+		}
+		if index < 0 {
+			return nil, fmt.Errorf("action not found in state")
+		}
+
+		recorded = actions[index]
+		recorded.RollbackAttempts = append(recorded.RollbackAttempts, attempt)
+		if rollbackErr != nil {
+			recorded.Status = StatusRollbackFailed
+			actions[index] = recorded
+			return actions, nil
+		}
+
+		recorded.Status = StatusRolledBack
+		return append(actions[:index], actions[index+1:]...), nil
+	})
+	if err != nil {
+		return action, err
+	}
+
+	// Best effort, as in SaveAction
+	event := newHistoryEvent(EventRolledBack, recorded, attempt.At)
+	if rollbackErr != nil {
+		event.Event = EventRollbackFailed
+		event.Error = attempt.Error
+	}
+	_ = appendHistory(event)
+
+	return recorded, nil
+}
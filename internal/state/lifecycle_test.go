@@ -0,0 +1,178 @@
+package state
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// useTestClock fixes the clock used for expiry for the duration of a test
+func useTestClock(t *testing.T, at time.Time) {
+	originalNow := now
+	now = func() time.Time { return at }
+	t.Cleanup(func() { now = originalNow })
+}
+
+// useTestStateFile points the state file at a temporary directory for the duration of a test
+func useTestStateFile(t *testing.T) {
+	originalStateFile := stateFile
+	stateFile = filepath.Join(t.TempDir(), "lifecycle_test_state.json")
+	t.Cleanup(func() { stateFile = originalStateFile })
+}
+
+func TestInitLifecycle(t *testing.T) {
+	action := ChaosAction{
+		Type:      "scale",
+		TargetPod: "web",
+		Timestamp: "2024-01-01T00:00:00Z",
+		Metadata:  map[string]string{"duration": "5m"},
+	}
+	initLifecycle(&action)
+
+	started := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	if !action.StartedAt.Equal(started) {
+		t.Errorf("Expected StartedAt %v, got %v", started, action.StartedAt)
+	}
+	if !action.ExpiresAt.Equal(started.Add(5 * time.Minute)) {
+		t.Errorf("Expected ExpiresAt %v, got %v", started.Add(5*time.Minute), action.ExpiresAt)
+	}
+	if action.Status != StatusActive {
+		t.Errorf("Expected status %s, got %s", StatusActive, action.Status)
+	}
+
+	// A fault without a duration never expires
+	for _, duration := range []string{"", "0s"} {
+		action := ChaosAction{Type: "scale", Timestamp: "2024-01-01T00:00:00Z", Metadata: map[string]string{"duration": duration}}
+		initLifecycle(&action)
+		if !action.ExpiresAt.IsZero() {
+			t.Errorf("Expected no expiry for duration '%s', got %v", duration, action.ExpiresAt)
+		}
+	}
+}
+
+func TestSweepExpired(t *testing.T) {
+	at := time.Date(2024, 1, 1, 1, 0, 0, 0, time.UTC)
+	expired := at.Add(-time.Minute)
+	pending := at.Add(time.Minute)
+
+	actions := []ChaosAction{
+		{ID: "latency-expired", Type: "latency", ExpiresAt: expired, Status: StatusActive},
+		{ID: "latency-pending", Type: "latency", ExpiresAt: pending, Status: StatusActive},
+		{ID: "scale-expired", Type: "scale", ExpiresAt: expired, Status: StatusActive},
+		{ID: "scale-failed", Type: "scale", ExpiresAt: expired, Status: StatusRollbackFailed},
+		{ID: "kill", Type: "kill", Status: StatusActive},
+	}
+
+	swept, changed := sweepExpired(actions, at)
+	if !changed {
+		t.Error("Expected the sweep to report a change")
+	}
+
+	statuses := make(map[string]string)
+	for _, action := range swept {
+		statuses[action.ID] = action.Status
+	}
+
+	expected := map[string]string{
+		"latency-pending": StatusActive,
+		"scale-expired":   StatusExpired,
+		"scale-failed":    StatusRollbackFailed,
+		"kill":            StatusActive,
+	}
+	if len(statuses) != len(expected) {
+		t.Fatalf("Expected %d actions after the sweep, got %v", len(expected), statuses)
+	}
+	for id, status := range expected {
+		if statuses[id] != status {
+			t.Errorf("Expected %s to have status %s, got %s", id, status, statuses[id])
+		}
+	}
+
+	if _, changed := sweepExpired(swept, at); changed {
+		t.Error("Expected a second sweep to change nothing")
+	}
+}
+
+func TestLoadActionsSweepsExpired(t *testing.T) {
+	useTestStateFile(t)
+	useTestClock(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	for _, actionType := range []string{"latency", "quota-squeeze"} {
+		action := ChaosAction{Type: actionType, TargetPod: "target", Namespace: "default", Metadata: map[string]string{"duration": "1m"}}
+		action.Timestamp = now().Format(time.RFC3339)
+		if err := SaveAction(action); err != nil {
+			t.Fatalf("Failed to save action: %v", err)
+		}
+	}
+
+	useTestClock(t, time.Date(2024, 1, 1, 0, 5, 0, 0, time.UTC))
+
+	actions, err := LoadActions()
+	if err != nil {
+		t.Fatalf("Failed to load actions: %v", err)
+	}
+	if len(actions) != 1 || actions[0].Type != "quota-squeeze" || actions[0].Status != StatusExpired {
+		t.Fatalf("Expected only the quota action, marked expired, got %+v", actions)
+	}
+
+	// The sweep is written back to the state file
+	stored, err := loadActions()
+	if err != nil {
+		t.Fatalf("Failed to load actions: %v", err)
+	}
+	if len(stored) != 1 || stored[0].Status != StatusExpired {
+		t.Errorf("Expected the sweep to be persisted, got %+v", stored)
+	}
+}
+
+func TestRecordRollback(t *testing.T) {
+	useTestStateFile(t)
+	useTestClock(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	action := ChaosAction{ID: "abc123", Type: "scale", TargetPod: "web", Namespace: "default"}
+	if err := SaveAction(action); err != nil {
+		t.Fatalf("Failed to save action: %v", err)
+	}
+
+	// A failed attempt keeps the action and records why
+	recorded, err := RecordRollback(action, errors.New("deployment is being deleted"))
+	if err != nil {
+		t.Fatalf("Failed to record rollback: %v", err)
+	}
+	if recorded.Status != StatusRollbackFailed || len(recorded.RollbackAttempts) != 1 {
+		t.Errorf("Unexpected action after a failed rollback: %+v", recorded)
+	}
+
+	actions, err := LoadActions()
+	if err != nil {
+		t.Fatalf("Failed to load actions: %v", err)
+	}
+	if len(actions) != 1 || actions[0].Status != StatusRollbackFailed {
+		t.Fatalf("Expected the failed action to stay in state, got %+v", actions)
+	}
+	if attempts := actions[0].RollbackAttempts; len(attempts) != 1 || attempts[0].Error != "deployment is being deleted" {
+		t.Errorf("Unexpected rollback attempts: %+v", attempts)
+	}
+
+	// A successful attempt removes the action
+	recorded, err = RecordRollback(action, nil)
+	if err != nil {
+		t.Fatalf("Failed to record rollback: %v", err)
+	}
+	if recorded.Status != StatusRolledBack || len(recorded.RollbackAttempts) != 2 {
+		t.Errorf("Unexpected action after a successful rollback: %+v", recorded)
+	}
+
+	actions, err = LoadActions()
+	if err != nil {
+		t.Fatalf("Failed to load actions: %v", err)
+	}
+	if len(actions) != 0 {
+		t.Errorf("Expected the rolled back action to leave state, got %+v", actions)
+	}
+
+	if _, err := RecordRollback(action, nil); err == nil {
+		t.Error("Expected an error for an action no longer in state")
+	}
+}
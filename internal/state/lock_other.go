@@ -0,0 +1,16 @@
+//go:build !unix
+
+package state
+
+import "os"
+
+// flock is a no-op where advisory file locks are not available; the in-process
+// mutex still serialises access within a single tipsy invocation
+func flock(file *os.File) error {
+	return nil
+}
+
+// funlock is a no-op where advisory file locks are not available
+func funlock(file *os.File) error {
+	return nil
+}
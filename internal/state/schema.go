@@ -0,0 +1,186 @@
+package state
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// CurrentVersion is the version of the state format written by this build of tipsy
+// Bump it, and add a migration, whenever a change to ChaosAction would misread older state
+const CurrentVersion = 2
+
+// stateEnvelope is the stored form of state: the actions along with the format version
+type stateEnvelope struct {
+	Version int             `json:"version"`
+	Actions json.RawMessage `json:"actions"`
+}
+
+// migrations upgrade stored state one version at a time; migrations[v] turns version v into v+1
+var migrations = map[int]func(stateEnvelope) (stateEnvelope, error){
+	1: migrateV1,
+}
+
+// migrateV1 upgrades the bare array of actions written before state was versioned
+// Actions recorded before lifecycle tracking existed get their start, expiry and status filled in
+func migrateV1(envelope stateEnvelope) (stateEnvelope, error) {
+	var actions []ChaosAction
+	if err := json.Unmarshal(envelope.Actions, &actions); err != nil {
+		return envelope, err
+	}
+
+	for i := range actions {
+		initLifecycle(&actions[i])
+	}
+
+	raw, err := json.Marshal(actions)
+	if err != nil {
+		return envelope, err
+	}
+	return stateEnvelope{Version: 2, Actions: raw}, nil
+}
+
+// VersionError reports state written by a newer tipsy than this one
+type VersionError struct {
+	Version int
+}
+
+func (e *VersionError) Error() string {
+	return fmt.Sprintf("state version %d is newer than the latest this tipsy understands (%d); upgrade tipsy", e.Version, CurrentVersion)
+}
+
+// corruptStateError wraps a decoding error as a CorruptStateError, except for state from a
+// newer tipsy, which is intact and only needs a newer tipsy to read it
+func corruptStateError(err error, path, recovery string) error {
+	var versionErr *VersionError
+	if errors.As(err, &versionErr) {
+		return err
+	}
+	return &CorruptStateError{Path: path, Err: err, Recovery: recovery}
+}
+
+// decodeActions parses stored state of any known version, migrating it to the current one
+// Empty data holds no actions
+func decodeActions(data []byte) ([]ChaosAction, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return []ChaosAction{}, nil
+	}
+
+	var envelope stateEnvelope
+	if data[0] == '[' {
+		// Unversioned state is a bare array of actions
+		envelope = stateEnvelope{Version: 1, Actions: data}
+	} else if err := json.Unmarshal(data, &envelope); err != nil {
+		return nil, err
+	}
+
+	if envelope.Version < 1 {
+		return nil, fmt.Errorf("missing or invalid state version %d", envelope.Version)
+	}
+	if envelope.Version > CurrentVersion {
+		return nil, &VersionError{Version: envelope.Version}
+	}
+
+	for envelope.Version < CurrentVersion {
+		migrate := migrations[envelope.Version]
+		from := envelope.Version
+
+		var err error
+		if envelope, err = migrate(envelope); err != nil {
+			return nil, fmt.Errorf("failed to migrate state from version %d: %w", from, err)
+		}
+	}
+
+	actions := []ChaosAction{}
+	if len(envelope.Actions) > 0 {
+		if err := json.Unmarshal(envelope.Actions, &actions); err != nil {
+			return nil, err
+		}
+	}
+	if actions == nil {
+		// "actions": null
+		actions = []ChaosAction{}
+	}
+
+	return actions, nil
+}
+
+// encodeActions renders actions as pretty-printed state of the current version
+func encodeActions(actions []ChaosAction) ([]byte, error) {
+	if actions == nil {
+		actions = []ChaosAction{}
+	}
+
+	raw, err := json.Marshal(actions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal actions to JSON: %w", err)
+	}
+
+	data, err := json.MarshalIndent(stateEnvelope{Version: CurrentVersion, Actions: raw}, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal actions to JSON: %w", err)
+	}
+	return data, nil
+}
+
+// Migrate rewrites the stored state in the current format
+// Older state is already upgraded as it is read; this makes the upgrade permanent,
+// so older state can be migrated ahead of time rather than on the next write
+func Migrate() error {
+	return backend.Update(func(actions []ChaosAction) ([]ChaosAction, error) {
+		return actions, nil
+	})
+}
+
+// Export returns the recorded actions as state of the current version, for Import elsewhere
+func Export() ([]byte, error) {
+	actions, err := backend.Load()
+	if err != nil {
+		return nil, err
+	}
+	return encodeActions(actions)
+}
+
+// Import adds the actions in exported state of any known version to the state store
+// Actions already in the store are skipped; with replace, the store's actions are discarded first
+// Returns the number of actions imported
+func Import(data []byte, replace bool) (int, error) {
+	imported, err := decodeActions(data)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read exported state: %w", err)
+	}
+
+	count := 0
+	err = backend.Update(func(actions []ChaosAction) ([]ChaosAction, error) {
+		if replace {
+			actions = []ChaosAction{}
+		}
+
+		count = 0
+		for _, action := range imported {
+			if containsAction(actions, action) {
+				continue
+			}
+			actions = append(actions, action)
+			count++
+		}
+		return actions, nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// containsAction reports whether an action is among the given ones, see sameAction
+func containsAction(actions []ChaosAction, action ChaosAction) bool {
+	for _, existing := range actions {
+		if sameAction(existing, action) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,144 @@
+package state
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDecodeActionsMigratesUnversionedState(t *testing.T) {
+	// State as written before it was versioned, from before lifecycle tracking existed
+	legacy := `[{"type": "scale", "targetPod": "web", "namespace": "default", "timestamp": "2024-01-01T00:00:00Z", "metadata": {"duration": "5m"}}]`
+
+	actions, err := decodeActions([]byte(legacy))
+	if err != nil {
+		t.Fatalf("Failed to decode legacy state: %v", err)
+	}
+	if len(actions) != 1 {
+		t.Fatalf("Expected 1 action, got %+v", actions)
+	}
+	if actions[0].Status != StatusActive || !actions[0].ExpiresAt.Equal(time.Date(2024, 1, 1, 0, 5, 0, 0, time.UTC)) {
+		t.Errorf("Expected the migration to fill in lifecycle fields, got %+v", actions[0])
+	}
+}
+
+func TestDecodeActionsVersions(t *testing.T) {
+	testCases := []struct {
+		name    string
+		data    string
+		count   int
+		wantErr bool
+	}{
+		{name: "empty", data: "", count: 0},
+		{name: "current version", data: `{"version": 2, "actions": [{"type": "kill", "targetPod": "web-1"}]}`, count: 1},
+		{name: "no actions", data: `{"version": 2, "actions": null}`, count: 0},
+		{name: "missing version", data: `{"actions": []}`, wantErr: true},
+		{name: "not JSON", data: `{"version": 2, "act`, wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actions, err := decodeActions([]byte(tc.data))
+			if tc.wantErr {
+				if err == nil {
+					t.Errorf("Expected an error, got %+v", actions)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if len(actions) != tc.count {
+				t.Errorf("Expected %d actions, got %+v", tc.count, actions)
+			}
+		})
+	}
+}
+
+func TestLoadNewerStateVersion(t *testing.T) {
+	useTestStateFile(t)
+
+	if err := os.WriteFile(stateFile, []byte(`{"version": 99, "actions": []}`), 0644); err != nil {
+		t.Fatalf("Failed to write state file: %v", err)
+	}
+
+	// State from a newer tipsy is intact, so it is not reported as corrupt
+	_, err := LoadActions()
+	var versionErr *VersionError
+	if !errors.As(err, &versionErr) || versionErr.Version != 99 {
+		t.Fatalf("Expected a VersionError for version 99, got %v", err)
+	}
+	var corruptErr *CorruptStateError
+	if errors.As(err, &corruptErr) {
+		t.Error("Expected newer state not to be reported as corrupt")
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	useTestStateFile(t)
+
+	legacy := `[{"id": "abc123", "type": "kill", "targetPod": "web-1", "namespace": "default", "timestamp": "2024-01-01T00:00:00Z"}]`
+	if err := os.WriteFile(stateFile, []byte(legacy), 0644); err != nil {
+		t.Fatalf("Failed to write state file: %v", err)
+	}
+
+	if err := Migrate(); err != nil {
+		t.Fatalf("Failed to migrate state: %v", err)
+	}
+
+	content, err := os.ReadFile(stateFile)
+	if err != nil {
+		t.Fatalf("Failed to read state file: %v", err)
+	}
+	if !strings.Contains(string(content), `"version": 2`) || !strings.Contains(string(content), `"id": "abc123"`) {
+		t.Errorf("Expected the state file to be rewritten as version 2, got: %s", content)
+	}
+}
+
+func TestExportImport(t *testing.T) {
+	useTestStateFile(t)
+
+	for _, id := range []string{"first", "second"} {
+		if err := SaveAction(ChaosAction{ID: id, Type: "kill", TargetPod: id, Namespace: "default"}); err != nil {
+			t.Fatalf("Failed to save action: %v", err)
+		}
+	}
+
+	exported, err := Export()
+	if err != nil {
+		t.Fatalf("Failed to export state: %v", err)
+	}
+
+	// Import into a different store, one of whose actions is already there
+	useTestStateFile(t)
+	if err := SaveAction(ChaosAction{ID: "first", Type: "kill", TargetPod: "first", Namespace: "default"}); err != nil {
+		t.Fatalf("Failed to save action: %v", err)
+	}
+
+	count, err := Import(exported, false)
+	if err != nil {
+		t.Fatalf("Failed to import state: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("Expected 1 action imported, got %d", count)
+	}
+	if actions, _ := LoadActions(); len(actions) != 2 {
+		t.Errorf("Expected 2 actions after the import, got %+v", actions)
+	}
+
+	// Replacing discards what was there; unversioned state can be imported too
+	count, err = Import([]byte(`[{"id": "legacy", "type": "kill", "targetPod": "web-9"}]`), true)
+	if err != nil {
+		t.Fatalf("Failed to import state: %v", err)
+	}
+	actions, _ := LoadActions()
+	if count != 1 || len(actions) != 1 || actions[0].ID != "legacy" {
+		t.Errorf("Expected only the legacy action after replacing, got %d imported, %+v", count, actions)
+	}
+
+	if _, err := Import([]byte(`not json`), false); err == nil {
+		t.Error("Expected an error importing invalid state")
+	}
+}
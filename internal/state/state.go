@@ -1,7 +1,8 @@
 package state
 
 import (
-	"encoding/json"
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -11,11 +12,27 @@ import (
 
 // ChaosAction represents a single chaos engineering action
 type ChaosAction struct {
+	ID        string            `json:"id,omitempty"`
 	Type      string            `json:"type"`
 	TargetPod string            `json:"targetPod"`
 	Namespace string            `json:"namespace"`
 	Timestamp string            `json:"timestamp"`
 	Metadata  map[string]string `json:"metadata"`
+	TargetUID string            `json:"targetUID,omitempty"` // UID of the affected object, to tell it from a replacement with the same name
+	Owner     *OwnerRef         `json:"owner,omitempty"`     // controller of the affected object, if any
+	Cluster   *ClusterRef       `json:"cluster,omitempty"`   // cluster the action was recorded against
+
+	StartedAt        time.Time         `json:"startedAt,omitzero"`
+	ExpiresAt        time.Time         `json:"expiresAt,omitzero"` // zero if the fault has no duration
+	Status           string            `json:"status,omitempty"`
+	RollbackAttempts []RollbackAttempt `json:"rollbackAttempts,omitempty"`
+}
+
+// OwnerRef identifies the controller of an affected object, such as the StatefulSet owning a pod
+type OwnerRef struct {
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+	UID  string `json:"uid"`
 }
 
 var (
@@ -41,55 +58,68 @@ func init() {
 	stateFile = filepath.Join(homeDir, ".tipsy", "state.json")
 }
 
-// SaveAction saves a chaos action to the state file
-func SaveAction(action ChaosAction) error {
-	mu.Lock()
-	defer mu.Unlock()
+// NewActionID generates a unique ID for a chaos action
+func NewActionID() string {
+	b := make([]byte, 6)
+	if _, err := rand.Read(b); err != nil {
+		// crypto/rand does not fail on supported platforms; fall back to the clock just in case
+		return fmt.Sprintf("%012x", time.Now().UnixNano()&0xffffffffffff)
+	}
+	return hex.EncodeToString(b)
+}
 
-	// Ensure the action has a timestamp if not set
+// SaveAction saves a chaos action to the state store
+// An action saved without an ID is given one; callers that keep the action to roll it back
+// later should set the ID themselves with NewActionID
+func SaveAction(action ChaosAction) error {
+	// Ensure the action has an ID and a timestamp if not set
+	if action.ID == "" {
+		action.ID = NewActionID()
+	}
 	if action.Timestamp == "" {
 		action.Timestamp = time.Now().UTC().Format(time.RFC3339)
 	}
-
-	// Load existing actions
-	actions, err := loadActions()
-	if err != nil {
-		return fmt.Errorf("failed to load existing actions: %w", err)
-	}
-
-	// Append the new action
-	actions = append(actions, action)
-
-	// Ensure the directory exists
-	dir := filepath.Dir(stateFile)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return fmt.Errorf("failed to create state directory: %w", err)
+	if action.Cluster == nil {
+		action.Cluster = currentCluster
 	}
+	initLifecycle(&action)
 
-	// Marshal to pretty-printed JSON
-	data, err := json.MarshalIndent(actions, "", "  ")
+	err := backend.Update(func(actions []ChaosAction) ([]ChaosAction, error) {
+		// Drop or mark any existing actions that have expired, then append the new action
+		actions, _ = sweepExpired(actions, now())
+		return append(actions, action), nil
+	})
 	if err != nil {
-		return fmt.Errorf("failed to marshal actions to JSON: %w", err)
-	}
-
-	// Write to file
-	if err := os.WriteFile(stateFile, data, 0644); err != nil {
-		return fmt.Errorf("failed to write state file: %w", err)
+		return err
 	}
 
+	// Best effort: history is an audit trail and must not fail the injection it records
+	_ = appendHistory(newHistoryEvent(EventInjected, action, action.StartedAt))
 	return nil
 }
 
-// LoadActions loads all chaos actions from the state file
+// LoadActions loads all chaos actions from the state store
+// Expired actions are pruned or marked as they are loaded, see sweepExpired
 func LoadActions() ([]ChaosAction, error) {
-	mu.Lock()
-	defer mu.Unlock()
+	actions, err := backend.Load()
+	if err != nil {
+		return nil, err
+	}
 
-	return loadActions()
+	actions, changed := sweepExpired(actions, now())
+	if changed {
+		// Best effort: the sweep is repeated on the next load if the write fails
+		_ = backend.Update(func(stored []ChaosAction) ([]ChaosAction, error) {
+			swept, _ := sweepExpired(stored, now())
+			return swept, nil
+		})
+	}
+
+	return actions, nil
 }
 
-// loadActions is the internal function that loads actions without locking
-// (used by SaveAction to avoid double-locking)
+// loadActions loads actions from the state file without locking it
+// (used by the file store, which holds the lock around a whole load-modify-save cycle)
 func loadActions() ([]ChaosAction, error) {
 	// Check if file exists
 	if _, err := os.Stat(stateFile); os.IsNotExist(err) {
@@ -103,20 +133,15 @@ func loadActions() ([]ChaosAction, error) {
 		return nil, fmt.Errorf("failed to read state file: %w", err)
 	}
 
-	// Unmarshal JSON
-	var actions []ChaosAction
-	if len(data) == 0 {
-		// Empty file, return empty slice
-		return []ChaosAction{}, nil
-	}
-
-	if err := json.Unmarshal(data, &actions); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal state file: %w", err)
+	actions, err := decodeActions(data)
+	if err != nil {
+		return nil, corruptStateError(err, stateFile, "")
 	}
 
 	return actions, nil
 }
 
+
 // GetStateFilePath returns the path to the state file
 func GetStateFilePath() string {
 	return stateFile
@@ -140,76 +165,60 @@ func ReloadStateFilePath() {
 	stateFile = filepath.Join(homeDir, ".tipsy", "state.json")
 }
 
-// DeleteAction removes a specific action from the state file
+// DeleteAction removes a specific action from the state store
 func DeleteAction(actionToDelete ChaosAction) error {
-	mu.Lock()
-	defer mu.Unlock()
-
-	// Load existing actions
-	actions, err := loadActions()
-	if err != nil {
-		return fmt.Errorf("failed to load existing actions: %w", err)
-	}
-
-	// Find and remove the action
-	var newActions []ChaosAction
-	found := false
-	for _, action := range actions {
-		// Compare actions by all fields to ensure we're removing the right one
-		if action.Type == actionToDelete.Type &&
-			action.TargetPod == actionToDelete.TargetPod &&
-			action.Namespace == actionToDelete.Namespace &&
-			action.Timestamp == actionToDelete.Timestamp {
-			found = true
-			continue // Skip this action (effectively removing it)
+	return backend.Update(func(actions []ChaosAction) ([]ChaosAction, error) {
+		// Find and remove the action
+		newActions := []ChaosAction{}
+		found := false
+		for _, action := range actions {
+			if !found && sameAction(action, actionToDelete) {
+				found = true
+				continue // Skip this action (effectively removing it)
+			}
+			newActions = append(newActions, action)
 		}
-		newActions = append(newActions, action)
-	}
 
-	if !found {
-		return fmt.Errorf("action not found in state")
-	}
+		if !found {
+			return nil, fmt.Errorf("action not found in state")
+		}
 
-	// Ensure the directory exists
-	dir := filepath.Dir(stateFile)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return fmt.Errorf("failed to create state directory: %w", err)
-	}
+		return newActions, nil
+	})
+}
 
-	// Marshal to pretty-printed JSON
-	data, err := json.MarshalIndent(newActions, "", "  ")
+// FindAction returns the action with the given ID
+func FindAction(id string) (ChaosAction, error) {
+	actions, err := backend.Load()
 	if err != nil {
-		return fmt.Errorf("failed to marshal actions to JSON: %w", err)
+		return ChaosAction{}, fmt.Errorf("failed to load existing actions: %w", err)
 	}
 
-	// Write to file
-	if err := os.WriteFile(stateFile, data, 0644); err != nil {
-		return fmt.Errorf("failed to write state file: %w", err)
+	for _, action := range actions {
+		if action.ID == id {
+			return action, nil
+		}
 	}
 
-	return nil
+	return ChaosAction{}, fmt.Errorf("no action with ID '%s' in state", id)
 }
 
-// ClearActions removes all actions from the state file
-func ClearActions() error {
-	mu.Lock()
-	defer mu.Unlock()
-
-	// Ensure the directory exists
-	dir := filepath.Dir(stateFile)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return fmt.Errorf("failed to create state directory: %w", err)
-	}
-
-	// Write empty array to file
-	data, err := json.MarshalIndent([]ChaosAction{}, "", "  ")
-	if err != nil {
-		return fmt.Errorf("failed to marshal empty actions: %w", err)
-	}
-
-	if err := os.WriteFile(stateFile, data, 0644); err != nil {
-		return fmt.Errorf("failed to write empty state file: %w", err)
-	}
+// sameAction reports whether a stored action is the one to delete
+// Actions are matched by ID; actions recorded before IDs existed fall back to comparing
+// their type, target, namespace and timestamp
+func sameAction(stored, target ChaosAction) bool {
+	if stored.ID != "" && target.ID != "" {
+		return stored.ID == target.ID
+	}
+	return stored.Type == target.Type &&
+		stored.TargetPod == target.TargetPod &&
+		stored.Namespace == target.Namespace &&
+		stored.Timestamp == target.Timestamp
+}
 
-	return nil
+// ClearActions removes all actions from the state store
+func ClearActions() error {
+	return backend.Update(func([]ChaosAction) ([]ChaosAction, error) {
+		return []ChaosAction{}, nil
+	})
 }
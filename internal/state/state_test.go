@@ -403,12 +403,18 @@ func TestStateFilePrettyPrinting(t *testing.T) {
 		t.Errorf("Expected pretty-printed JSON with indentation, got: %s", string(content))
 	}
 
-	// Verify it's valid JSON
-	var actions []ChaosAction
-	err = json.Unmarshal(content, &actions)
+	// Verify it's valid JSON, wrapped in a versioned envelope
+	var envelope struct {
+		Version int           `json:"version"`
+		Actions []ChaosAction `json:"actions"`
+	}
+	err = json.Unmarshal(content, &envelope)
 	if err != nil {
 		t.Fatalf("State file contains invalid JSON: %v", err)
 	}
+	if envelope.Version != CurrentVersion || len(envelope.Actions) != 1 {
+		t.Errorf("Expected version %d state with 1 action, got: %s", CurrentVersion, string(content))
+	}
 }
 
 // Helper function to check if a string contains a substring
@@ -730,3 +736,90 @@ func slicesEqual(a, b []string) bool {
 	}
 	return true
 }
+
+func TestSaveActionAssignsID(t *testing.T) {
+	originalStateFile := stateFile
+	defer func() {
+		stateFile = originalStateFile
+	}()
+	stateFile = filepath.Join(t.TempDir(), "id_test_state.json")
+
+	// An action saved without an ID gets one, and a given ID is kept
+	if err := SaveAction(ChaosAction{Type: "latency", TargetPod: "pod-1", Namespace: "default"}); err != nil {
+		t.Fatalf("Failed to save action: %v", err)
+	}
+	if err := SaveAction(ChaosAction{ID: "abc123", Type: "latency", TargetPod: "pod-2", Namespace: "default"}); err != nil {
+		t.Fatalf("Failed to save action: %v", err)
+	}
+
+	actions, err := LoadActions()
+	if err != nil {
+		t.Fatalf("Failed to load actions: %v", err)
+	}
+	if len(actions) != 2 {
+		t.Fatalf("Expected 2 actions, got %d", len(actions))
+	}
+	if actions[0].ID == "" {
+		t.Error("Expected a generated ID")
+	}
+	if actions[1].ID != "abc123" {
+		t.Errorf("Expected ID 'abc123' to be kept, got '%s'", actions[1].ID)
+	}
+
+	found, err := FindAction(actions[0].ID)
+	if err != nil {
+		t.Fatalf("Failed to find action: %v", err)
+	}
+	if found.TargetPod != "pod-1" {
+		t.Errorf("Expected to find pod-1, got %s", found.TargetPod)
+	}
+
+	if _, err := FindAction("missing"); err == nil {
+		t.Error("Expected an error for an unknown ID")
+	}
+}
+
+func TestDeleteActionByID(t *testing.T) {
+	originalStateFile := stateFile
+	defer func() {
+		stateFile = originalStateFile
+	}()
+	stateFile = filepath.Join(t.TempDir(), "delete_by_id_test_state.json")
+
+	// Two actions on the same pod recorded within the same second
+	timestamp := "2023-01-01T00:00:00Z"
+	first := ChaosAction{ID: "first", Type: "latency", TargetPod: "pod-1", Namespace: "default", Timestamp: timestamp}
+	second := ChaosAction{ID: "second", Type: "latency", TargetPod: "pod-1", Namespace: "default", Timestamp: timestamp}
+	for _, action := range []ChaosAction{first, second} {
+		if err := SaveAction(action); err != nil {
+			t.Fatalf("Failed to save action: %v", err)
+		}
+	}
+
+	if err := DeleteAction(second); err != nil {
+		t.Fatalf("Failed to delete action: %v", err)
+	}
+
+	actions, err := LoadActions()
+	if err != nil {
+		t.Fatalf("Failed to load actions: %v", err)
+	}
+	if len(actions) != 1 || actions[0].ID != "first" {
+		t.Errorf("Expected only the first action to remain, got %+v", actions)
+	}
+
+	// An action without an ID still matches on its fields, removing one copy only
+	if err := SaveAction(first); err != nil {
+		t.Fatalf("Failed to save action: %v", err)
+	}
+	if err := DeleteAction(ChaosAction{Type: "latency", TargetPod: "pod-1", Namespace: "default", Timestamp: timestamp}); err != nil {
+		t.Fatalf("Failed to delete action: %v", err)
+	}
+	actions, err = LoadActions()
+	if err != nil {
+		t.Fatalf("Failed to load actions: %v", err)
+	}
+	if len(actions) != 1 {
+		t.Errorf("Expected 1 action to remain, got %d", len(actions))
+	}
+}
@@ -0,0 +1,169 @@
+package state
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Store persists recorded chaos actions
+type Store interface {
+	// Load returns every recorded action
+	Load() ([]ChaosAction, error)
+	// Update replaces the recorded actions with the result of fn applied to the current ones,
+	// without losing concurrent updates from other tipsy invocations
+	// fn may be called more than once and must not keep state between calls;
+	// if it returns an error nothing is written
+	Update(fn func(actions []ChaosAction) ([]ChaosAction, error)) error
+}
+
+// backend is the store used by the package-level functions
+var backend Store = NewFileStore()
+
+// SetStore replaces the store used by the package-level functions
+func SetStore(store Store) {
+	backend = store
+}
+
+// fileStore keeps actions in the local state file, see GetStateFilePath
+type fileStore struct{}
+
+// NewFileStore returns the store that keeps actions in the local state file
+func NewFileStore() Store {
+	return fileStore{}
+}
+
+func (fileStore) Load() ([]ChaosAction, error) {
+	unlock, err := lockState()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	return loadActions()
+}
+
+func (fileStore) Update(fn func(actions []ChaosAction) ([]ChaosAction, error)) error {
+	unlock, err := lockState()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	actions, err := loadActions()
+	if err != nil {
+		return fmt.Errorf("failed to load existing actions: %w", err)
+	}
+
+	updated, err := fn(actions)
+	if err != nil {
+		return err
+	}
+
+	return writeActions(updated)
+}
+
+// CorruptStateError reports stored state that exists but cannot be parsed,
+// typically after a crash or a hand edit gone wrong
+type CorruptStateError struct {
+	Path     string // the state file, or the namespace/name of the state ConfigMap
+	Err      error
+	Recovery string // how to recover; defaults to moving the state file aside
+}
+
+func (e *CorruptStateError) Error() string {
+	recovery := e.Recovery
+	if recovery == "" {
+		recovery = fmt.Sprintf("move it aside with 'mv %s %s.corrupt'", e.Path, e.Path)
+	}
+	return fmt.Sprintf("state %s is corrupt: %v; to recover, %s "+
+		"and check the old state for faults that still need rolling back by hand", e.Path, e.Err, recovery)
+}
+
+func (e *CorruptStateError) Unwrap() error {
+	return e.Err
+}
+
+// lockState serialises a load-modify-save cycle on the state file, both within this
+// process and across concurrent tipsy invocations
+// The advisory lock is held on a sibling .lock file, since the state file itself is
+// replaced on every write
+func lockState() (func(), error) {
+	mu.Lock()
+
+	dir := filepath.Dir(stateFile)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		mu.Unlock()
+		return nil, fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	lockFile, err := os.OpenFile(stateFile+".lock", os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		mu.Unlock()
+		return nil, fmt.Errorf("failed to open state lock file: %w", err)
+	}
+
+	if err := flock(lockFile); err != nil {
+		lockFile.Close()
+		mu.Unlock()
+		return nil, fmt.Errorf("failed to lock state file: %w", err)
+	}
+
+	return func() {
+		funlock(lockFile)
+		lockFile.Close()
+		mu.Unlock()
+	}, nil
+}
+
+// writeActions replaces the contents of the state file
+// The new contents are written to a temporary file in the same directory and renamed over
+// the state file, so a crash mid-write leaves either the old or the new file, never a mix
+func writeActions(actions []ChaosAction) error {
+	// Ensure the directory exists
+	dir := filepath.Dir(stateFile)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	data, err := encodeActions(actions)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(dir, filepath.Base(stateFile)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+	tmpPath := tmp.Name()
+
+	// Remove the temporary file unless it was renamed into place
+	committed := false
+	defer func() {
+		if !committed {
+			os.Remove(tmpPath)
+		}
+	}()
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+	if err := os.Chmod(tmpPath, 0644); err != nil {
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+
+	if err := os.Rename(tmpPath, stateFile); err != nil {
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+	committed = true
+
+	return nil
+}
@@ -0,0 +1,122 @@
+package state
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestWriteActionsIsAtomic(t *testing.T) {
+	useTestStateFile(t)
+
+	for i := 0; i < 3; i++ {
+		if err := SaveAction(ChaosAction{Type: "latency", TargetPod: fmt.Sprintf("pod-%d", i), Namespace: "default"}); err != nil {
+			t.Fatalf("Failed to save action: %v", err)
+		}
+	}
+
+	// Only the state file and its lock file are left behind; temporary files are renamed or removed
+	entries, err := os.ReadDir(filepath.Dir(stateFile))
+	if err != nil {
+		t.Fatalf("Failed to read state directory: %v", err)
+	}
+	for _, entry := range entries {
+		if strings.Contains(entry.Name(), ".tmp-") {
+			t.Errorf("Unexpected temporary file left behind: %s", entry.Name())
+		}
+	}
+
+	info, err := os.Stat(stateFile)
+	if err != nil {
+		t.Fatalf("Failed to stat state file: %v", err)
+	}
+	if info.Mode().Perm() != 0644 {
+		t.Errorf("Expected state file mode 0644, got %v", info.Mode().Perm())
+	}
+}
+
+func TestCorruptStateFile(t *testing.T) {
+	useTestStateFile(t)
+
+	// A write cut short by a crash before atomic writes existed
+	corrupt := []byte(`[{"type": "latency", "targetPod": "pod-1", "names`)
+	if err := os.WriteFile(stateFile, corrupt, 0644); err != nil {
+		t.Fatalf("Failed to write corrupt state file: %v", err)
+	}
+
+	_, err := LoadActions()
+	var corruptErr *CorruptStateError
+	if !errors.As(err, &corruptErr) {
+		t.Fatalf("Expected a CorruptStateError, got %v", err)
+	}
+	if corruptErr.Path != stateFile {
+		t.Errorf("Expected the error to name %s, got %s", stateFile, corruptErr.Path)
+	}
+	if !strings.Contains(err.Error(), "mv "+stateFile) {
+		t.Errorf("Expected the error to explain how to recover, got: %v", err)
+	}
+
+	// Saving must not overwrite the corrupt file and lose whatever it still holds
+	if err := SaveAction(ChaosAction{Type: "latency", TargetPod: "pod-2", Namespace: "default"}); !errors.As(err, &corruptErr) {
+		t.Errorf("Expected saving to fail with a CorruptStateError, got %v", err)
+	}
+	content, err := os.ReadFile(stateFile)
+	if err != nil {
+		t.Fatalf("Failed to read state file: %v", err)
+	}
+	if string(content) != string(corrupt) {
+		t.Errorf("Expected the corrupt state file to be left untouched, got: %s", content)
+	}
+}
+
+// TestHelperSaveActions saves actions from a child process started by TestSaveActionAcrossProcesses
+func TestHelperSaveActions(t *testing.T) {
+	if os.Getenv("TIPSY_STATE_LOCK_HELPER") != "1" {
+		t.Skip("helper process for TestSaveActionAcrossProcesses")
+	}
+
+	for i := 0; i < 10; i++ {
+		action := ChaosAction{Type: "latency", TargetPod: fmt.Sprintf("pod-%d-%d", os.Getpid(), i), Namespace: "default"}
+		if err := SaveAction(action); err != nil {
+			t.Fatalf("Failed to save action: %v", err)
+		}
+	}
+}
+
+func TestSaveActionAcrossProcesses(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("advisory file locks are not used on windows")
+	}
+
+	useTestStateFile(t)
+
+	// Several tipsy invocations saving at once, as in a CI matrix
+	const processes = 4
+	commands := make([]*exec.Cmd, processes)
+	for i := range commands {
+		cmd := exec.Command(os.Args[0], "-test.run=^TestHelperSaveActions$")
+		cmd.Env = append(os.Environ(), "TIPSY_STATE_LOCK_HELPER=1", "TIPSY_STATE_FILE="+stateFile)
+		if err := cmd.Start(); err != nil {
+			t.Fatalf("Failed to start helper process: %v", err)
+		}
+		commands[i] = cmd
+	}
+	for _, cmd := range commands {
+		if err := cmd.Wait(); err != nil {
+			t.Fatalf("Helper process failed: %v", err)
+		}
+	}
+
+	actions, err := LoadActions()
+	if err != nil {
+		t.Fatalf("Failed to load actions: %v", err)
+	}
+	if len(actions) != processes*10 {
+		t.Errorf("Expected %d actions, got %d; concurrent saves were lost", processes*10, len(actions))
+	}
+}
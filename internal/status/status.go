@@ -0,0 +1,416 @@
+package status
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/isurusiri/tipsy/internal/chaos"
+	"github.com/isurusiri/tipsy/internal/state"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Live states of a recorded fault
+const (
+	// StateActive means the fault is still in force
+	StateActive = "active"
+	// StateExpired means the fault ran its course: its duration has passed or its injector has exited
+	StateExpired = "expired"
+	// StateDrifted means the target still exists but no longer carries the fault
+	StateDrifted = "drifted"
+	// StateOrphaned means the target is gone or has been replaced by a new object with the same name
+	StateOrphaned = "orphaned"
+	// StateUnknown means the cluster could not be checked
+	StateUnknown = "unknown"
+)
+
+// Result is the live state of a recorded action
+type Result struct {
+	Action state.ChaosAction `json:"action"`
+	State  string            `json:"state"`
+	Detail string            `json:"detail"`
+}
+
+// injectorPrefixes are the name prefixes of the ephemeral containers each pod fault injects
+var injectorPrefixes = map[string]string{
+	"latency":    "latency-injector-",
+	"packetloss": "packetloss-injector-",
+	"cpustress":  "tipsy-cpu-stress-",
+}
+
+// CheckAll checks every action against the cluster
+func CheckAll(client kubernetes.Interface, actions []state.ChaosAction, now time.Time) []Result {
+	results := make([]Result, 0, len(actions))
+	for _, action := range actions {
+		results = append(results, Check(client, action, now))
+	}
+	return results
+}
+
+// Check works out whether a recorded action is still in force in the cluster
+// A gone target wins over everything else; a fault whose duration has passed is reported
+// as expired even if it is still in place, since it outlived what was asked for
+func Check(client kubernetes.Interface, action state.ChaosAction, now time.Time) Result {
+	liveState, detail, err := checkTarget(client, action)
+	if err != nil {
+		return Result{Action: action, State: StateUnknown, Detail: err.Error()}
+	}
+
+	if liveState == StateActive || liveState == StateDrifted {
+		if expiry, ok := expiresAt(action); ok && !now.Before(expiry) {
+			if liveState == StateActive {
+				detail = fmt.Sprintf("duration elapsed at %s but the fault is still in place; run 'tipsy rollback'", expiry.Format(time.RFC3339))
+			} else {
+				detail = fmt.Sprintf("duration elapsed at %s; %s", expiry.Format(time.RFC3339), detail)
+			}
+			liveState = StateExpired
+		}
+	}
+
+	return Result{Action: action, State: liveState, Detail: detail}
+}
+
+// expiresAt returns when an action's duration runs out, if it has one
+func expiresAt(action state.ChaosAction) (time.Time, bool) {
+	if !action.ExpiresAt.IsZero() {
+		return action.ExpiresAt, true
+	}
+
+	// Fall back to the duration metadata for actions built outside the state file
+	duration, err := time.ParseDuration(action.Metadata["duration"])
+	if err != nil || duration <= 0 {
+		return time.Time{}, false
+	}
+
+	started, err := time.Parse(time.RFC3339, action.Timestamp)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	return started.Add(duration), true
+}
+
+// checkTarget inspects the object an action affected
+func checkTarget(client kubernetes.Interface, action state.ChaosAction) (string, string, error) {
+	switch action.Type {
+	case "latency", "packetloss", "cpustress":
+		return checkInjector(client, action)
+	case "kill":
+		return checkKill(client, action)
+	case "cordon", "drain", "taint":
+		return checkNode(client, action)
+	case "scale":
+		return checkScale(client, action)
+	case "config-fault":
+		return checkConfig(client, action)
+	case "quota-squeeze":
+		return checkQuota(client, action)
+	case "resize-squeeze":
+		return checkResize(client, action)
+	case "readiness-flap":
+		return checkReadiness(client, action)
+	case "misroute":
+		if action.Metadata["mode"] == chaos.MisrouteModeSelectorSwap {
+			return checkSelector(client, action)
+		}
+		return checkEndpoints(client, action)
+	default:
+		return "", "", fmt.Errorf("unknown action type: %s", action.Type)
+	}
+}
+
+// getPod fetches the pod an action affected
+// Returns a nil pod and an orphaned detail if the pod is gone or has been replaced
+func getPod(client kubernetes.Interface, action state.ChaosAction) (*corev1.Pod, string, error) {
+	pod, err := client.CoreV1().Pods(action.Namespace).Get(context.TODO(), action.TargetPod, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, "pod no longer exists", nil
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get pod: %w", err)
+	}
+
+	if replaced(pod.UID, action) {
+		return nil, fmt.Sprintf("pod was replaced (UID %s, originally %s)", pod.UID, action.TargetUID), nil
+	}
+
+	return pod, "", nil
+}
+
+// replaced reports whether an object is a replacement for the one an action recorded
+func replaced(uid types.UID, action state.ChaosAction) bool {
+	return action.TargetUID != "" && string(uid) != action.TargetUID
+}
+
+// checkInjector reports the state of the ephemeral container injected by a pod fault
+func checkInjector(client kubernetes.Interface, action state.ChaosAction) (string, string, error) {
+	pod, orphaned, err := getPod(client, action)
+	if err != nil || pod == nil {
+		return StateOrphaned, orphaned, err
+	}
+
+	prefix := injectorPrefixes[action.Type]
+	found := false
+	for _, status := range pod.Status.EphemeralContainerStatuses {
+		if !strings.HasPrefix(status.Name, prefix) {
+			continue
+		}
+		found = true
+		if status.State.Terminated == nil {
+			return StateActive, fmt.Sprintf("injector '%s' is running", status.Name), nil
+		}
+	}
+
+	if found {
+		return StateExpired, "injector has exited", nil
+	}
+
+	for _, container := range pod.Spec.EphemeralContainers {
+		if strings.HasPrefix(container.Name, prefix) {
+			return StateActive, fmt.Sprintf("injector '%s' is starting", container.Name), nil
+		}
+	}
+
+	return StateDrifted, "no injector container found in pod", nil
+}
+
+// checkKill reports whether a killed pod has gone; a kill is a one-shot fault
+func checkKill(client kubernetes.Interface, action state.ChaosAction) (string, string, error) {
+	pod, orphaned, err := getPod(client, action)
+	if err != nil {
+		return "", "", err
+	}
+	if pod == nil {
+		return StateExpired, orphaned, nil
+	}
+
+	if pod.DeletionTimestamp != nil {
+		return StateActive, "pod is terminating", nil
+	}
+	return StateDrifted, "pod is still running", nil
+}
+
+// checkNode reports whether a node is still cordoned or tainted
+func checkNode(client kubernetes.Interface, action state.ChaosAction) (string, string, error) {
+	// Node actions use TargetPod to hold the node name
+	node, err := client.CoreV1().Nodes().Get(context.TODO(), action.TargetPod, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return StateOrphaned, "node no longer exists", nil
+	}
+	if err != nil {
+		return "", "", fmt.Errorf("failed to get node: %w", err)
+	}
+	if replaced(node.UID, action) {
+		return StateOrphaned, fmt.Sprintf("node was replaced (UID %s, originally %s)", node.UID, action.TargetUID), nil
+	}
+
+	if action.Type == "taint" {
+		taint, err := chaos.ParseTaint(action.Metadata["taint"])
+		if err != nil {
+			return "", "", err
+		}
+		for _, existing := range node.Spec.Taints {
+			if existing.MatchTaint(&taint) {
+				return StateActive, fmt.Sprintf("node has taint '%s'", taint.ToString()), nil
+			}
+		}
+		return StateDrifted, fmt.Sprintf("taint '%s' has been removed", taint.ToString()), nil
+	}
+
+	if node.Spec.Unschedulable {
+		return StateActive, "node is cordoned", nil
+	}
+	return StateDrifted, "node is schedulable again", nil
+}
+
+// checkScale reports whether a workload still runs the replica count tipsy set
+func checkScale(client kubernetes.Interface, action state.ChaosAction) (string, string, error) {
+	replicas, uid, err := chaos.GetReplicas(client, action.Namespace, action.Metadata["kind"], action.TargetPod)
+	if apierrors.IsNotFound(err) {
+		return StateOrphaned, fmt.Sprintf("%s no longer exists", action.Metadata["kind"]), nil
+	}
+	if err != nil {
+		return "", "", err
+	}
+	if replaced(uid, action) {
+		return StateOrphaned, fmt.Sprintf("%s was replaced (UID %s, originally %s)", action.Metadata["kind"], uid, action.TargetUID), nil
+	}
+
+	if fmt.Sprintf("%d", replicas) == action.Metadata["replicas"] {
+		return StateActive, fmt.Sprintf("scaled to %d replica(s)", replicas), nil
+	}
+	return StateDrifted, fmt.Sprintf("now at %d replica(s), tipsy set %s", replicas, action.Metadata["replicas"]), nil
+}
+
+// checkConfig reports whether a ConfigMap or Secret still holds the data tipsy wrote
+func checkConfig(client kubernetes.Interface, action state.ChaosAction) (string, string, error) {
+	kind := action.Metadata["kind"]
+
+	var uid types.UID
+	var current string
+	var err error
+	switch kind {
+	case chaos.KindConfigMap:
+		var configMap *corev1.ConfigMap
+		configMap, err = client.CoreV1().ConfigMaps(action.Namespace).Get(context.TODO(), action.TargetPod, metav1.GetOptions{})
+		if err == nil {
+			uid, current = configMap.UID, chaos.ConfigMapChecksum(configMap)
+		}
+	case chaos.KindSecret:
+		var secret *corev1.Secret
+		secret, err = client.CoreV1().Secrets(action.Namespace).Get(context.TODO(), action.TargetPod, metav1.GetOptions{})
+		if err == nil {
+			uid, current = secret.UID, chaos.SecretChecksum(secret)
+		}
+	default:
+		return "", "", fmt.Errorf("unsupported config kind: %s", kind)
+	}
+
+	if apierrors.IsNotFound(err) {
+		return StateOrphaned, fmt.Sprintf("%s no longer exists", kind), nil
+	}
+	if err != nil {
+		return "", "", fmt.Errorf("failed to get %s: %w", strings.ToLower(kind), err)
+	}
+	if replaced(uid, action) {
+		return StateOrphaned, fmt.Sprintf("%s was replaced (UID %s, originally %s)", kind, uid, action.TargetUID), nil
+	}
+
+	if current == action.Metadata["checksum"] {
+		return StateActive, fmt.Sprintf("keys %s still hold the injected values", action.Metadata["keys"]), nil
+	}
+	return StateDrifted, fmt.Sprintf("%s has changed since tipsy mutated it", kind), nil
+}
+
+// checkQuota reports whether the tipsy ResourceQuota is still present
+func checkQuota(client kubernetes.Interface, action state.ChaosAction) (string, string, error) {
+	// Quota actions use TargetPod to hold the quota name
+	_, err := client.CoreV1().ResourceQuotas(action.Namespace).Get(context.TODO(), action.TargetPod, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return StateDrifted, "quota has been deleted", nil
+	}
+	if err != nil {
+		return "", "", fmt.Errorf("failed to get resource quota: %w", err)
+	}
+	return StateActive, "quota is in place", nil
+}
+
+// checkResize reports whether a pod's containers still run with the squeezed limits
+func checkResize(client kubernetes.Interface, action state.ChaosAction) (string, string, error) {
+	pod, orphaned, err := getPod(client, action)
+	if err != nil || pod == nil {
+		return StateOrphaned, orphaned, err
+	}
+
+	limits := map[corev1.ResourceName]string{
+		corev1.ResourceCPU:    action.Metadata["cpu"],
+		corev1.ResourceMemory: action.Metadata["memory"],
+	}
+	for _, container := range pod.Spec.Containers {
+		for name, value := range limits {
+			if value == "" {
+				continue
+			}
+			expected, err := resource.ParseQuantity(value)
+			if err != nil {
+				return "", "", fmt.Errorf("invalid %s quantity '%s': %w", name, value, err)
+			}
+			current, exists := container.Resources.Limits[name]
+			if !exists || current.Cmp(expected) != 0 {
+				return StateDrifted, fmt.Sprintf("container '%s' no longer has a %s limit of %s", container.Name, name, value), nil
+			}
+		}
+	}
+
+	return StateActive, "containers run with the squeezed limits", nil
+}
+
+// checkReadiness reports the tipsy readiness condition of a flapped pod
+func checkReadiness(client kubernetes.Interface, action state.ChaosAction) (string, string, error) {
+	pod, orphaned, err := getPod(client, action)
+	if err != nil || pod == nil {
+		return StateOrphaned, orphaned, err
+	}
+
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type != chaos.ReadinessGateCondition {
+			continue
+		}
+		// A True condition leaves the pod ready, so the fault is no longer holding it back
+		if condition.Status == corev1.ConditionTrue {
+			return StateDrifted, fmt.Sprintf("condition '%s' is True", chaos.ReadinessGateCondition), nil
+		}
+		return StateActive, fmt.Sprintf("condition '%s' is %s", chaos.ReadinessGateCondition, condition.Status), nil
+	}
+	return StateDrifted, fmt.Sprintf("condition '%s' is not set", chaos.ReadinessGateCondition), nil
+}
+
+// checkEndpoints reports whether a misrouted service's Endpoints still differ from the backup
+func checkEndpoints(client kubernetes.Interface, action state.ChaosAction) (string, string, error) {
+	// Misroute actions use TargetPod to hold the service name
+	_, err := client.CoreV1().Services(action.Namespace).Get(context.TODO(), action.TargetPod, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return StateOrphaned, "service no longer exists", nil
+	}
+	if err != nil {
+		return "", "", fmt.Errorf("failed to get service: %w", err)
+	}
+
+	endpoints, err := client.CoreV1().Endpoints(action.Namespace).Get(context.TODO(), action.TargetPod, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return StateDrifted, "endpoints no longer exist", nil
+	}
+	if err != nil {
+		return "", "", fmt.Errorf("failed to get endpoints: %w", err)
+	}
+
+	// Compare against the true original, the oldest backup stacked for the service
+	backupPath := action.Metadata["backupPath"]
+	backup, err := chaos.ReadEndpointsBackup(backupPath)
+	if err != nil {
+		return "", "", err
+	}
+	if backup.Version > 0 {
+		stack, err := chaos.EndpointsBackupStack(backupPath)
+		if err != nil {
+			return "", "", err
+		}
+		if backup, err = chaos.ReadEndpointsBackup(stack[0]); err != nil {
+			return "", "", err
+		}
+	}
+
+	if equality.Semantic.DeepEqual(endpoints.Subsets, backup.Endpoints.Subsets) {
+		return StateDrifted, "endpoints match the backup again", nil
+	}
+	return StateActive, "endpoints differ from the backup", nil
+}
+
+// checkSelector reports whether a selector-swapped service still has the swapped selector
+func checkSelector(client kubernetes.Interface, action state.ChaosAction) (string, string, error) {
+	// Misroute actions use TargetPod to hold the service name
+	service, err := client.CoreV1().Services(action.Namespace).Get(context.TODO(), action.TargetPod, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return StateOrphaned, "service no longer exists", nil
+	}
+	if err != nil {
+		return "", "", fmt.Errorf("failed to get service: %w", err)
+	}
+
+	current := chaos.FormatSelector(service.Spec.Selector)
+	switch current {
+	case action.Metadata["swappedSelector"]:
+		return StateActive, fmt.Sprintf("selector is '%s'", current), nil
+	case action.Metadata["originalSelector"]:
+		return StateDrifted, "selector matches the original again", nil
+	default:
+		return StateDrifted, fmt.Sprintf("selector has been changed to '%s'", current), nil
+	}
+}
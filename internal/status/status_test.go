@@ -0,0 +1,312 @@
+package status
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/isurusiri/tipsy/internal/chaos"
+	"github.com/isurusiri/tipsy/internal/state"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+var testNow = time.Date(2024, 1, 1, 0, 10, 0, 0, time.UTC)
+
+// Helper function to create a pod with a latency injector
+// A nil injector state means the injector has been added but has not reported a status yet
+func createTestInjectedPod(uid string, injectorState *corev1.ContainerState) *corev1.Pod {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-1", Namespace: "default", UID: types.UID(uid)},
+		Spec: corev1.PodSpec{
+			EphemeralContainers: []corev1.EphemeralContainer{
+				{EphemeralContainerCommon: corev1.EphemeralContainerCommon{Name: "latency-injector-1700000000"}},
+			},
+		},
+	}
+	if injectorState != nil {
+		pod.Status.EphemeralContainerStatuses = []corev1.ContainerStatus{
+			{Name: "latency-injector-1700000000", State: *injectorState},
+		}
+	}
+	return pod
+}
+
+// Helper function to create a latency action recorded at the given offset before testNow
+func createTestLatencyAction(age time.Duration, duration string) state.ChaosAction {
+	return state.ChaosAction{
+		ID:        "abc123",
+		Type:      "latency",
+		TargetPod: "web-1",
+		Namespace: "default",
+		TargetUID: "uid-1",
+		Timestamp: testNow.Add(-age).Format(time.RFC3339),
+		Metadata:  map[string]string{"delay": "200ms", "duration": duration},
+	}
+}
+
+func TestCheck_Injector(t *testing.T) {
+	running := &corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}
+	terminated := &corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 0}}
+
+	testCases := []struct {
+		name          string
+		pod           *corev1.Pod
+		action        state.ChaosAction
+		expectedState string
+	}{
+		{
+			name:          "injector running",
+			pod:           createTestInjectedPod("uid-1", running),
+			action:        createTestLatencyAction(time.Minute, "5m"),
+			expectedState: StateActive,
+		},
+		{
+			name:          "injector starting",
+			pod:           createTestInjectedPod("uid-1", nil),
+			action:        createTestLatencyAction(time.Minute, "5m"),
+			expectedState: StateActive,
+		},
+		{
+			name:          "injector exited",
+			pod:           createTestInjectedPod("uid-1", terminated),
+			action:        createTestLatencyAction(time.Minute, "5m"),
+			expectedState: StateExpired,
+		},
+		{
+			name:          "duration elapsed",
+			pod:           createTestInjectedPod("uid-1", running),
+			action:        createTestLatencyAction(time.Hour, "5m"),
+			expectedState: StateExpired,
+		},
+		{
+			name:          "pod replaced",
+			pod:           createTestInjectedPod("uid-2", running),
+			action:        createTestLatencyAction(time.Minute, "5m"),
+			expectedState: StateOrphaned,
+		},
+		{
+			name: "injector missing",
+			pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "web-1", Namespace: "default", UID: "uid-1"},
+			},
+			action:        createTestLatencyAction(time.Minute, "5m"),
+			expectedState: StateDrifted,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := fake.NewSimpleClientset(tc.pod)
+
+			result := Check(client, tc.action, testNow)
+			if result.State != tc.expectedState {
+				t.Errorf("Expected state %s, got %s (%s)", tc.expectedState, result.State, result.Detail)
+			}
+		})
+	}
+
+	// A pod that no longer exists is orphaned
+	result := Check(fake.NewSimpleClientset(), createTestLatencyAction(time.Minute, "5m"), testNow)
+	if result.State != StateOrphaned {
+		t.Errorf("Expected state %s for a deleted pod, got %s", StateOrphaned, result.State)
+	}
+}
+
+func TestCheck_Node(t *testing.T) {
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-1", UID: "uid-node"},
+		Spec: corev1.NodeSpec{
+			Unschedulable: true,
+			Taints:        []corev1.Taint{{Key: "tipsy.io/chaos", Value: "true", Effect: corev1.TaintEffectNoSchedule}},
+		},
+	}
+	client := fake.NewSimpleClientset(node)
+
+	cordon := state.ChaosAction{Type: "cordon", TargetPod: "node-1", TargetUID: "uid-node", Timestamp: testNow.Format(time.RFC3339)}
+	if result := Check(client, cordon, testNow); result.State != StateActive {
+		t.Errorf("Expected cordoned node to be active, got %s (%s)", result.State, result.Detail)
+	}
+
+	taint := state.ChaosAction{
+		Type:      "taint",
+		TargetPod: "node-1",
+		Timestamp: testNow.Format(time.RFC3339),
+		Metadata:  map[string]string{"taint": "tipsy.io/chaos=true:NoSchedule"},
+	}
+	if result := Check(client, taint, testNow); result.State != StateActive {
+		t.Errorf("Expected tainted node to be active, got %s (%s)", result.State, result.Detail)
+	}
+
+	// Someone uncordoned the node and removed the taint by hand
+	node.Spec.Unschedulable = false
+	node.Spec.Taints = nil
+	client = fake.NewSimpleClientset(node)
+	if result := Check(client, cordon, testNow); result.State != StateDrifted {
+		t.Errorf("Expected uncordoned node to be drifted, got %s (%s)", result.State, result.Detail)
+	}
+	if result := Check(client, taint, testNow); result.State != StateDrifted {
+		t.Errorf("Expected untainted node to be drifted, got %s (%s)", result.State, result.Detail)
+	}
+}
+
+func TestCheck_Resize(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "api-1", Namespace: "default", UID: "uid-1"},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name: "app",
+					Resources: corev1.ResourceRequirements{
+						Limits: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")},
+					},
+				},
+			},
+		},
+	}
+	action := state.ChaosAction{
+		Type:      "resize-squeeze",
+		TargetPod: "api-1",
+		Namespace: "default",
+		TargetUID: "uid-1",
+		Timestamp: testNow.Format(time.RFC3339),
+		Metadata:  map[string]string{"cpu": "100m", "duration": "1m"},
+	}
+
+	if result := Check(fake.NewSimpleClientset(pod), action, testNow); result.State != StateActive {
+		t.Errorf("Expected squeezed pod to be active, got %s (%s)", result.State, result.Detail)
+	}
+
+	pod.Spec.Containers[0].Resources.Limits[corev1.ResourceCPU] = resource.MustParse("500m")
+	if result := Check(fake.NewSimpleClientset(pod), action, testNow); result.State != StateDrifted {
+		t.Errorf("Expected resized pod to be drifted, got %s (%s)", result.State, result.Detail)
+	}
+}
+
+func TestCheck_Readiness(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "api-1", Namespace: "default", UID: "uid-1"},
+		Status: corev1.PodStatus{
+			Conditions: []corev1.PodCondition{{Type: chaos.ReadinessGateCondition, Status: corev1.ConditionFalse}},
+		},
+	}
+	action := state.ChaosAction{
+		Type:      "readiness-flap",
+		TargetPod: "api-1",
+		Namespace: "default",
+		TargetUID: "uid-1",
+		Timestamp: testNow.Format(time.RFC3339),
+		Metadata:  map[string]string{"period": "10s", "duration": "1m"},
+	}
+
+	if result := Check(fake.NewSimpleClientset(pod), action, testNow); result.State != StateActive {
+		t.Errorf("Expected not-ready pod to be active, got %s (%s)", result.State, result.Detail)
+	}
+
+	pod.Status.Conditions[0].Status = corev1.ConditionTrue
+	if result := Check(fake.NewSimpleClientset(pod), action, testNow); result.State != StateDrifted {
+		t.Errorf("Expected ready pod to be drifted, got %s (%s)", result.State, result.Detail)
+	}
+}
+
+func TestCheck_Misroute(t *testing.T) {
+	original := &corev1.Endpoints{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Subsets: []corev1.EndpointSubset{
+			{
+				Addresses: []corev1.EndpointAddress{{IP: "10.0.0.1"}},
+				Ports:     []corev1.EndpointPort{{Port: 8080}},
+			},
+		},
+	}
+	data, err := json.Marshal(original)
+	if err != nil {
+		t.Fatalf("Failed to marshal backup: %v", err)
+	}
+	backupPath := filepath.Join(t.TempDir(), "web_default.json")
+	if err := os.WriteFile(backupPath, data, 0600); err != nil {
+		t.Fatalf("Failed to write backup: %v", err)
+	}
+
+	service := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"}}
+	action := state.ChaosAction{
+		Type:      "misroute",
+		TargetPod: "web",
+		Namespace: "default",
+		Timestamp: testNow.Format(time.RFC3339),
+		Metadata:  map[string]string{"backupPath": backupPath},
+	}
+
+	misrouted := original.DeepCopy()
+	misrouted.Subsets = []corev1.EndpointSubset{}
+	if result := Check(fake.NewSimpleClientset(service, misrouted), action, testNow); result.State != StateActive {
+		t.Errorf("Expected misrouted endpoints to be active, got %s (%s)", result.State, result.Detail)
+	}
+
+	// The endpoints controller put the original addresses back
+	if result := Check(fake.NewSimpleClientset(service, original), action, testNow); result.State != StateDrifted {
+		t.Errorf("Expected reconciled endpoints to be drifted, got %s (%s)", result.State, result.Detail)
+	}
+
+	if result := Check(fake.NewSimpleClientset(), action, testNow); result.State != StateOrphaned {
+		t.Errorf("Expected a deleted service to be orphaned, got %s (%s)", result.State, result.Detail)
+	}
+}
+
+func TestCheck_SelectorSwap(t *testing.T) {
+	action := state.ChaosAction{
+		Type:      "misroute",
+		TargetPod: "web",
+		Namespace: "default",
+		Timestamp: testNow.Format(time.RFC3339),
+		Metadata:  map[string]string{"mode": "selector-swap", "originalSelector": "app=web", "swappedSelector": "app=decoy"},
+	}
+	service := func(app string) *corev1.Service {
+		return &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+			Spec:       corev1.ServiceSpec{Selector: map[string]string{"app": app}},
+		}
+	}
+
+	if result := Check(fake.NewSimpleClientset(service("decoy")), action, testNow); result.State != StateActive {
+		t.Errorf("Expected a swapped selector to be active, got %s (%s)", result.State, result.Detail)
+	}
+	if result := Check(fake.NewSimpleClientset(service("web")), action, testNow); result.State != StateDrifted {
+		t.Errorf("Expected a restored selector to be drifted, got %s (%s)", result.State, result.Detail)
+	}
+}
+
+func TestCheck_Kill(t *testing.T) {
+	action := state.ChaosAction{Type: "kill", TargetPod: "db-0", Namespace: "default", TargetUID: "uid-1", Timestamp: testNow.Format(time.RFC3339)}
+
+	replacement := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "db-0", Namespace: "default", UID: "uid-2"}}
+	if result := Check(fake.NewSimpleClientset(replacement), action, testNow); result.State != StateExpired {
+		t.Errorf("Expected a completed kill to be expired, got %s (%s)", result.State, result.Detail)
+	}
+}
+
+func TestCheck_UnknownType(t *testing.T) {
+	action := state.ChaosAction{Type: "unknown", TargetPod: "web-1", Namespace: "default"}
+	if result := Check(fake.NewSimpleClientset(), action, testNow); result.State != StateUnknown {
+		t.Errorf("Expected state %s, got %s", StateUnknown, result.State)
+	}
+}
+
+func TestExpiresAt(t *testing.T) {
+	action := createTestLatencyAction(time.Minute, "5m")
+	expiry, ok := expiresAt(action)
+	if !ok || !expiry.Equal(testNow.Add(4*time.Minute)) {
+		t.Errorf("Unexpected expiry: %v (%t)", expiry, ok)
+	}
+
+	for _, duration := range []string{"", "0s", "invalid"} {
+		if _, ok := expiresAt(createTestLatencyAction(time.Minute, duration)); ok {
+			t.Errorf("Expected no expiry for duration '%s'", duration)
+		}
+	}
+}